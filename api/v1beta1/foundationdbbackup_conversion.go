@@ -0,0 +1,86 @@
+/*
+ * foundationdbbackup_conversion.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Convertible = &FoundationDBBackup{}
+
+// ConvertTo converts this backup to the hub version.
+func (backup *FoundationDBBackup) ConvertTo(dstRaw conversion.Hub) error {
+	dst, canCast := dstRaw.(*v1beta2.FoundationDBBackup)
+	if !canCast {
+		return fmt.Errorf("cannot convert a FoundationDBBackup to a %T", dstRaw)
+	}
+
+	dst.ObjectMeta = *backup.ObjectMeta.DeepCopy()
+
+	dst.Spec = v1beta2.FoundationDBBackupSpec{}
+	err := convertThroughJSON(backup.Spec, &dst.Spec)
+	if err != nil {
+		return err
+	}
+
+	dst.Status = v1beta2.FoundationDBBackupStatus{}
+	err = convertThroughJSON(backup.Status, &dst.Status)
+	if err != nil {
+		return err
+	}
+	dst.Status.Generations.NeedsBackupReconfiguration = backup.Status.Generations.NeedsBackupReconfiguration
+	if backup.Status.BackupDetails != nil && dst.Status.BackupDetails != nil {
+		dst.Status.BackupDetails.SnapshotPeriodSeconds = backup.Status.BackupDetails.SnapshotPeriodSeconds
+	}
+
+	return nil
+}
+
+// ConvertFrom converts a backup from the hub version into this version.
+func (backup *FoundationDBBackup) ConvertFrom(srcRaw conversion.Hub) error {
+	src, canCast := srcRaw.(*v1beta2.FoundationDBBackup)
+	if !canCast {
+		return fmt.Errorf("cannot convert a %T to a FoundationDBBackup", srcRaw)
+	}
+
+	backup.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	backup.Spec = FoundationDBBackupSpec{}
+	err := convertThroughJSON(src.Spec, &backup.Spec)
+	if err != nil {
+		return err
+	}
+
+	backup.Status = FoundationDBBackupStatus{}
+	err = convertThroughJSON(src.Status, &backup.Status)
+	if err != nil {
+		return err
+	}
+	backup.Status.Generations.NeedsBackupReconfiguration = src.Status.Generations.NeedsBackupReconfiguration
+	if src.Status.BackupDetails != nil && backup.Status.BackupDetails != nil {
+		backup.Status.BackupDetails.SnapshotPeriodSeconds = src.Status.BackupDetails.SnapshotPeriodSeconds
+	}
+
+	return nil
+}
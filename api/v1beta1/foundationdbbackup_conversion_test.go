@@ -0,0 +1,76 @@
+/*
+ * foundationdbbackup_conversion_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConvertingBackup(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	agentCount := 3
+	snapshotPeriod := 3600
+
+	backup := &FoundationDBBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "sample-backup",
+			Namespace:  "default",
+			Generation: 2,
+		},
+		Spec: FoundationDBBackupSpec{
+			Version:               Versions.Default.String(),
+			ClusterName:           "sample-cluster",
+			AgentCount:            &agentCount,
+			SnapshotPeriodSeconds: &snapshotPeriod,
+		},
+		Status: FoundationDBBackupStatus{
+			BackupDetails: &FoundationDBBackupStatusBackupDetails{
+				URL:                   "blobstore://test@test-service/sample-backup?bucket=fdb-backups",
+				Running:               true,
+				SnapshotPeriodSeconds: 864000,
+			},
+			Generations: BackupGenerationStatus{
+				Reconciled:                 1,
+				NeedsBackupReconfiguration: 2,
+			},
+		},
+	}
+
+	hub := &v1beta2.FoundationDBBackup{}
+	err := backup.ConvertTo(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(hub.ObjectMeta).To(gomega.Equal(backup.ObjectMeta))
+	g.Expect(*hub.Spec.AgentCount).To(gomega.Equal(3))
+	g.Expect(*hub.Spec.SnapshotPeriodSeconds).To(gomega.Equal(3600))
+	g.Expect(hub.Status.BackupDetails.Running).To(gomega.BeTrue())
+	g.Expect(hub.Status.BackupDetails.SnapshotPeriodSeconds).To(gomega.Equal(864000))
+	g.Expect(hub.Status.Generations.Reconciled).To(gomega.Equal(int64(1)))
+	g.Expect(hub.Status.Generations.NeedsBackupReconfiguration).To(gomega.Equal(int64(2)))
+
+	converted := &FoundationDBBackup{}
+	err = converted.ConvertFrom(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted).To(gomega.Equal(backup))
+}
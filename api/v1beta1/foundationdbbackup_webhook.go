@@ -0,0 +1,72 @@
+/*
+ * foundationdbbackup_webhook.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-apps-foundationdb-org-v1beta1-foundationdbbackup,mutating=false,failurePolicy=fail,groups=apps.foundationdb.org,resources=foundationdbbackups,versions=v1beta1,name=vfoundationdbbackup.kb.io
+
+var _ webhook.Validator = &FoundationDBBackup{}
+
+// SetupWebhookWithManager registers the webhooks for backups with the
+// manager.
+func (backup *FoundationDBBackup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(backup).Complete()
+}
+
+// ValidateCreate checks that a new backup has a valid spec.
+func (backup *FoundationDBBackup) ValidateCreate() error {
+	return newValidationError("FoundationDBBackup", backup.Name, backup.validateSpec())
+}
+
+// ValidateUpdate checks that an updated backup has a valid spec.
+func (backup *FoundationDBBackup) ValidateUpdate(old runtime.Object) error {
+	return newValidationError("FoundationDBBackup", backup.Name, backup.validateSpec())
+}
+
+// ValidateDelete checks whether a backup can be deleted.
+//
+// We do not place any restrictions on deleting backups.
+func (backup *FoundationDBBackup) ValidateDelete() error {
+	return nil
+}
+
+// validateSpec checks the backup spec for values that the operator will not
+// be able to reconcile.
+func (backup *FoundationDBBackup) validateSpec() field.ErrorList {
+	specPath := field.NewPath("spec")
+	allErrs := validateVersion(specPath.Child("version"), backup.Spec.Version)
+
+	if backup.Spec.AgentCount != nil && *backup.Spec.AgentCount < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("agentCount"), *backup.Spec.AgentCount, "must not be negative"))
+	}
+
+	if backup.Spec.SnapshotPeriodSeconds != nil && *backup.Spec.SnapshotPeriodSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("snapshotPeriodSeconds"), *backup.Spec.SnapshotPeriodSeconds, "must be positive"))
+	}
+
+	return allErrs
+}
@@ -0,0 +1,72 @@
+/*
+ * foundationdbbackup_webhook_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidatingBackup(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	createBackup := func() *FoundationDBBackup {
+		return &FoundationDBBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sample-cluster",
+				Namespace: "default",
+			},
+			Spec: FoundationDBBackupSpec{
+				Version:     Versions.Default.String(),
+				ClusterName: "sample-cluster",
+				AccountName: "test@test-service",
+			},
+		}
+	}
+
+	backup := createBackup()
+	g.Expect(backup.ValidateCreate()).To(gomega.Succeed())
+	g.Expect(backup.ValidateUpdate(createBackup())).To(gomega.Succeed())
+
+	backup = createBackup()
+	backup.Spec.Version = "latest"
+	err := backup.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.version"))
+
+	backup = createBackup()
+	agentCount := -1
+	backup.Spec.AgentCount = &agentCount
+	err = backup.ValidateUpdate(createBackup())
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.agentCount"))
+
+	backup = createBackup()
+	snapshotPeriod := 0
+	backup.Spec.SnapshotPeriodSeconds = &snapshotPeriod
+	err = backup.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.snapshotPeriodSeconds"))
+}
@@ -0,0 +1,519 @@
+/*
+ * foundationdbcluster_conversion.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// DeprecatedFieldsAnnotation provides the annotation that we use to preserve
+// the values of deprecated fields when we convert a resource to a version
+// that does not have those fields.
+const DeprecatedFieldsAnnotation = "foundationdb.org/v1beta1-deprecated-fields"
+
+var _ conversion.Convertible = &FoundationDBCluster{}
+
+// deprecatedClusterFields holds the fields from the cluster spec that were
+// removed in v1beta2.
+// +kubebuilder:object:generate=false
+type deprecatedClusterFields struct {
+	SidecarVersion               int                                      `json:"sidecarVersion,omitempty"`
+	PodLabels                    map[string]string                        `json:"podLabels,omitempty"`
+	Resources                    *corev1.ResourceRequirements             `json:"resources,omitempty"`
+	InitContainers               []corev1.Container                       `json:"initContainers,omitempty"`
+	Containers                   []corev1.Container                       `json:"containers,omitempty"`
+	Volumes                      []corev1.Volume                          `json:"volumes,omitempty"`
+	PodSecurityContext           *corev1.PodSecurityContext               `json:"podSecurityContext,omitempty"`
+	AutomountServiceAccountToken *bool                                    `json:"automountServiceAccountToken,omitempty"`
+	NextInstanceID               int                                      `json:"nextInstanceID,omitempty"`
+	StorageClass                 *string                                  `json:"storageClass,omitempty"`
+	VolumeSize                   string                                   `json:"volumeSize,omitempty"`
+	RunningVersion               string                                   `json:"runningVersion,omitempty"`
+	ConnectionString             string                                   `json:"connectionString,omitempty"`
+	Configured                   bool                                     `json:"configured,omitempty"`
+	VolumeClaim                  *corev1.PersistentVolumeClaim            `json:"volumeClaim,omitempty"`
+	CustomParameters             []string                                 `json:"customParameters,omitempty"`
+	PendingRemovals              map[string]string                        `json:"pendingRemovals,omitempty"`
+	MainContainer                *deprecatedContainerFields               `json:"mainContainer,omitempty"`
+	SidecarContainer             *deprecatedContainerFields               `json:"sidecarContainer,omitempty"`
+	ProcessVolumeClaims          map[string]*corev1.PersistentVolumeClaim `json:"processVolumeClaims,omitempty"`
+}
+
+// deprecatedContainerFields holds the fields from the container overrides that
+// were removed in v1beta2.
+// +kubebuilder:object:generate=false
+type deprecatedContainerFields struct {
+	Env             []corev1.EnvVar         `json:"env,omitempty"`
+	VolumeMounts    []corev1.VolumeMount    `json:"volumeMounts,omitempty"`
+	ImageName       string                  `json:"imageName,omitempty"`
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+}
+
+// ConvertTo converts this cluster to the hub version.
+//
+// The deprecated fields that customize the pods and volume claims are moved
+// into the process settings. The deprecated fields that have no equivalent in
+// v1beta2 are preserved in an annotation so that they can be restored when
+// converting back to v1beta1.
+func (cluster *FoundationDBCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst, canCast := dstRaw.(*v1beta2.FoundationDBCluster)
+	if !canCast {
+		return fmt.Errorf("cannot convert a FoundationDBCluster to a %T", dstRaw)
+	}
+
+	src := cluster.DeepCopy()
+	moveDeprecatedPodTemplate(&src.Spec)
+	moveDeprecatedClusterFields(&src.Spec)
+
+	dst.ObjectMeta = src.ObjectMeta
+	err := setDeprecatedFieldsAnnotation(&dst.ObjectMeta, getDeprecatedClusterFields(&src.Spec))
+	if err != nil {
+		return err
+	}
+
+	dst.Spec = v1beta2.FoundationDBClusterSpec{}
+	err = convertThroughJSON(src.Spec, &dst.Spec)
+	if err != nil {
+		return err
+	}
+
+	dst.Status = v1beta2.FoundationDBClusterStatus{}
+	err = convertThroughJSON(src.Status, &dst.Status)
+	if err != nil {
+		return err
+	}
+	dst.Status.Generations.DatabaseUnavailable = src.Status.Generations.DatabaseUnavailable
+
+	return nil
+}
+
+// ConvertFrom converts a cluster from the hub version into this version.
+func (cluster *FoundationDBCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src, canCast := srcRaw.(*v1beta2.FoundationDBCluster)
+	if !canCast {
+		return fmt.Errorf("cannot convert a %T to a FoundationDBCluster", srcRaw)
+	}
+
+	cluster.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	cluster.Spec = FoundationDBClusterSpec{}
+	err := convertThroughJSON(src.Spec, &cluster.Spec)
+	if err != nil {
+		return err
+	}
+
+	cluster.Status = FoundationDBClusterStatus{}
+	err = convertThroughJSON(src.Status, &cluster.Status)
+	if err != nil {
+		return err
+	}
+	cluster.Status.Generations.DatabaseUnavailable = src.Status.Generations.DatabaseUnavailable
+
+	annotation, present := cluster.Annotations[DeprecatedFieldsAnnotation]
+	if !present {
+		return nil
+	}
+	delete(cluster.Annotations, DeprecatedFieldsAnnotation)
+
+	deprecatedFields := &deprecatedClusterFields{}
+	err = json.Unmarshal([]byte(annotation), deprecatedFields)
+	if err != nil {
+		return err
+	}
+	deprecatedFields.apply(&cluster.Spec)
+
+	return nil
+}
+
+// moveDeprecatedClusterFields moves the values from the deprecated fields that
+// customize the pods, volume claims, and fdbserver parameters into the process
+// settings, and clears the deprecated fields.
+//
+// The values are applied to the templates for every process class, in the
+// same way that the operator applies the deprecated fields when it builds
+// pods and volume claims. As in NormalizeClusterSpec, every process class
+// gets its own pod template, and the general process settings will get a
+// volume claim template if they need one. Fields that have no equivalent in
+// the process settings, and volume sizes that cannot be parsed, are left in
+// place.
+func moveDeprecatedClusterFields(spec *FoundationDBClusterSpec) {
+	var volumeSize *resource.Quantity
+	if spec.VolumeSize != "" {
+		size, err := resource.ParseQuantity(spec.VolumeSize)
+		if err == nil {
+			volumeSize = &size
+		}
+	}
+
+	hasPodFields := len(spec.PodLabels) > 0 || spec.Resources != nil ||
+		len(spec.InitContainers) > 0 || len(spec.Containers) > 0 || len(spec.Volumes) > 0 ||
+		spec.PodSecurityContext != nil || spec.AutomountServiceAccountToken != nil ||
+		getDeprecatedContainerFields(spec.MainContainer) != nil ||
+		getDeprecatedContainerFields(spec.SidecarContainer) != nil
+	hasVolumeFields := len(spec.PodLabels) > 0 || spec.VolumeClaim != nil ||
+		volumeSize != nil || spec.StorageClass != nil
+	hasProcessVolumeClaims := false
+	for _, settings := range spec.Processes {
+		if settings.VolumeClaim != nil {
+			hasProcessVolumeClaims = true
+		}
+	}
+
+	if !hasPodFields && !hasVolumeFields && !hasProcessVolumeClaims && len(spec.CustomParameters) == 0 {
+		return
+	}
+
+	if spec.Processes == nil {
+		spec.Processes = make(map[string]ProcessSettings)
+	}
+	generalVolumeClaim := spec.Processes["general"].VolumeClaim
+	_, present := spec.Processes["general"]
+	if !present {
+		spec.Processes["general"] = ProcessSettings{}
+	}
+
+	for processClass, settings := range spec.Processes {
+		// The cluster-wide volume claim takes precedence over any process
+		// settings, and a volume claim takes precedence over a volume claim
+		// template.
+		volumeClaim := spec.VolumeClaim
+		if volumeClaim == nil {
+			volumeClaim = settings.VolumeClaim
+		}
+		if volumeClaim == nil && settings.VolumeClaimTemplate != nil {
+			volumeClaim = generalVolumeClaim
+		}
+		if volumeClaim != nil && (processClass == "general" || settings.VolumeClaim != nil || settings.VolumeClaimTemplate != nil) {
+			settings.VolumeClaimTemplate = volumeClaim.DeepCopy()
+		}
+		settings.VolumeClaim = nil
+
+		if hasPodFields && settings.PodTemplate == nil {
+			settings.PodTemplate = &corev1.PodTemplateSpec{}
+		}
+
+		if processClass == "general" {
+			if hasVolumeFields && settings.VolumeClaimTemplate == nil {
+				settings.VolumeClaimTemplate = &corev1.PersistentVolumeClaim{}
+			}
+			if len(spec.CustomParameters) > 0 && settings.CustomParameters == nil {
+				settings.CustomParameters = &[]string{}
+			}
+		}
+
+		if hasPodFields {
+			settings.PodTemplate = settings.PodTemplate.DeepCopy()
+			applyDeprecatedPodFields(spec, settings.PodTemplate)
+		}
+
+		if settings.VolumeClaimTemplate != nil && hasVolumeFields {
+			settings.VolumeClaimTemplate = settings.VolumeClaimTemplate.DeepCopy()
+			for label, value := range spec.PodLabels {
+				if settings.VolumeClaimTemplate.Labels == nil {
+					settings.VolumeClaimTemplate.Labels = make(map[string]string)
+				}
+				settings.VolumeClaimTemplate.Labels[label] = value
+			}
+			if volumeSize != nil {
+				if settings.VolumeClaimTemplate.Spec.Resources.Requests == nil {
+					settings.VolumeClaimTemplate.Spec.Resources.Requests = corev1.ResourceList{}
+				}
+				settings.VolumeClaimTemplate.Spec.Resources.Requests[corev1.ResourceStorage] = *volumeSize
+			}
+			if spec.StorageClass != nil {
+				storageClass := *spec.StorageClass
+				settings.VolumeClaimTemplate.Spec.StorageClassName = &storageClass
+			}
+		}
+
+		if settings.CustomParameters != nil && len(spec.CustomParameters) > 0 {
+			customParameters := make([]string, 0, len(*settings.CustomParameters)+len(spec.CustomParameters))
+			customParameters = append(customParameters, *settings.CustomParameters...)
+			customParameters = append(customParameters, spec.CustomParameters...)
+			settings.CustomParameters = &customParameters
+		}
+
+		spec.Processes[processClass] = settings
+	}
+
+	if len(spec.PodLabels) > 0 {
+		if spec.ConfigMap == nil {
+			spec.ConfigMap = &corev1.ConfigMap{}
+		}
+		if spec.ConfigMap.Labels == nil {
+			spec.ConfigMap.Labels = make(map[string]string)
+		}
+		for label, value := range spec.PodLabels {
+			spec.ConfigMap.Labels[label] = value
+		}
+	}
+
+	spec.PodLabels = nil
+	spec.Resources = nil
+	spec.InitContainers = nil
+	spec.Containers = nil
+	spec.Volumes = nil
+	spec.PodSecurityContext = nil
+	spec.AutomountServiceAccountToken = nil
+	spec.VolumeClaim = nil
+	spec.StorageClass = nil
+	spec.CustomParameters = nil
+	if volumeSize != nil {
+		spec.VolumeSize = ""
+	}
+	clearDeprecatedContainerFields(&spec.MainContainer)
+	clearDeprecatedContainerFields(&spec.SidecarContainer)
+}
+
+// applyDeprecatedPodFields applies the deprecated fields that customize the
+// pods to a pod template.
+func applyDeprecatedPodFields(spec *FoundationDBClusterSpec, template *corev1.PodTemplateSpec) {
+	for label, value := range spec.PodLabels {
+		if template.Labels == nil {
+			template.Labels = make(map[string]string)
+		}
+		template.Labels[label] = value
+	}
+
+	if spec.Resources != nil || getDeprecatedContainerFields(spec.MainContainer) != nil {
+		template.Spec.Containers, _ = ensureContainerPresent(template.Spec.Containers, "foundationdb", 0)
+		template.Spec.Containers = customizeContainerFromList(template.Spec.Containers, "foundationdb", func(container *corev1.Container) {
+			if spec.Resources != nil {
+				container.Resources = *spec.Resources.DeepCopy()
+			}
+			applyDeprecatedContainerFields(container, spec.MainContainer)
+		})
+	}
+
+	if getDeprecatedContainerFields(spec.SidecarContainer) != nil {
+		sidecarUpdater := func(container *corev1.Container) {
+			applyDeprecatedContainerFields(container, spec.SidecarContainer)
+		}
+		template.Spec.InitContainers = customizeContainerFromList(template.Spec.InitContainers, "foundationdb-kubernetes-init", sidecarUpdater)
+		template.Spec.Containers = customizeContainerFromList(template.Spec.Containers, "foundationdb-kubernetes-sidecar", sidecarUpdater)
+	}
+
+	for _, container := range spec.InitContainers {
+		template.Spec.InitContainers = append(template.Spec.InitContainers, *container.DeepCopy())
+	}
+	for _, container := range spec.Containers {
+		template.Spec.Containers = append(template.Spec.Containers, *container.DeepCopy())
+	}
+	for _, volume := range spec.Volumes {
+		template.Spec.Volumes = append(template.Spec.Volumes, *volume.DeepCopy())
+	}
+
+	if spec.PodSecurityContext != nil {
+		template.Spec.SecurityContext = spec.PodSecurityContext.DeepCopy()
+	}
+	if spec.AutomountServiceAccountToken != nil {
+		automountServiceAccountToken := *spec.AutomountServiceAccountToken
+		template.Spec.AutomountServiceAccountToken = &automountServiceAccountToken
+	}
+}
+
+// applyDeprecatedContainerFields applies the deprecated fields from container
+// overrides to a container.
+//
+// Environment variables from the overrides replace any variables with the
+// same name in the container.
+func applyDeprecatedContainerFields(container *corev1.Container, overrides ContainerOverrides) {
+	if len(overrides.Env) > 0 {
+		envOverrides := make(map[string]bool, len(overrides.Env))
+		env := make([]corev1.EnvVar, 0, len(overrides.Env)+len(container.Env))
+		for _, envVar := range overrides.Env {
+			env = append(env, *envVar.DeepCopy())
+			envOverrides[envVar.Name] = true
+		}
+		for _, envVar := range container.Env {
+			if !envOverrides[envVar.Name] {
+				env = append(env, envVar)
+			}
+		}
+		container.Env = env
+	}
+
+	for _, volumeMount := range overrides.VolumeMounts {
+		container.VolumeMounts = append(container.VolumeMounts, *volumeMount.DeepCopy())
+	}
+
+	if overrides.SecurityContext != nil {
+		container.SecurityContext = overrides.SecurityContext.DeepCopy()
+	}
+
+	if overrides.ImageName != "" {
+		container.Image = overrides.ImageName
+	}
+}
+
+// clearDeprecatedContainerFields clears the deprecated fields in the container
+// overrides.
+func clearDeprecatedContainerFields(overrides *ContainerOverrides) {
+	overrides.Env = nil
+	overrides.VolumeMounts = nil
+	overrides.ImageName = ""
+	overrides.SecurityContext = nil
+}
+
+// getDeprecatedClusterFields gets the values of the deprecated fields in a
+// cluster spec. This will return nil if none of the deprecated fields are
+// set.
+func getDeprecatedClusterFields(spec *FoundationDBClusterSpec) *deprecatedClusterFields {
+	fields := &deprecatedClusterFields{
+		SidecarVersion:               spec.SidecarVersion,
+		PodLabels:                    spec.PodLabels,
+		Resources:                    spec.Resources,
+		InitContainers:               spec.InitContainers,
+		Containers:                   spec.Containers,
+		Volumes:                      spec.Volumes,
+		PodSecurityContext:           spec.PodSecurityContext,
+		AutomountServiceAccountToken: spec.AutomountServiceAccountToken,
+		NextInstanceID:               spec.NextInstanceID,
+		StorageClass:                 spec.StorageClass,
+		VolumeSize:                   spec.VolumeSize,
+		RunningVersion:               spec.RunningVersion,
+		ConnectionString:             spec.ConnectionString,
+		Configured:                   spec.Configured,
+		VolumeClaim:                  spec.VolumeClaim,
+		CustomParameters:             spec.CustomParameters,
+		PendingRemovals:              spec.PendingRemovals,
+		MainContainer:                getDeprecatedContainerFields(spec.MainContainer),
+		SidecarContainer:             getDeprecatedContainerFields(spec.SidecarContainer),
+	}
+
+	for processClass, settings := range spec.Processes {
+		if settings.VolumeClaim == nil {
+			continue
+		}
+		if fields.ProcessVolumeClaims == nil {
+			fields.ProcessVolumeClaims = make(map[string]*corev1.PersistentVolumeClaim)
+		}
+		fields.ProcessVolumeClaims[processClass] = settings.VolumeClaim
+	}
+
+	if reflect.DeepEqual(*fields, deprecatedClusterFields{}) {
+		return nil
+	}
+	return fields
+}
+
+// apply sets the deprecated fields in a cluster spec.
+func (fields *deprecatedClusterFields) apply(spec *FoundationDBClusterSpec) {
+	spec.SidecarVersion = fields.SidecarVersion
+	spec.PodLabels = fields.PodLabels
+	spec.Resources = fields.Resources
+	spec.InitContainers = fields.InitContainers
+	spec.Containers = fields.Containers
+	spec.Volumes = fields.Volumes
+	spec.PodSecurityContext = fields.PodSecurityContext
+	spec.AutomountServiceAccountToken = fields.AutomountServiceAccountToken
+	spec.NextInstanceID = fields.NextInstanceID
+	spec.StorageClass = fields.StorageClass
+	spec.VolumeSize = fields.VolumeSize
+	spec.RunningVersion = fields.RunningVersion
+	spec.ConnectionString = fields.ConnectionString
+	spec.Configured = fields.Configured
+	spec.VolumeClaim = fields.VolumeClaim
+	spec.CustomParameters = fields.CustomParameters
+	spec.PendingRemovals = fields.PendingRemovals
+	fields.MainContainer.apply(&spec.MainContainer)
+	fields.SidecarContainer.apply(&spec.SidecarContainer)
+
+	for processClass, volumeClaim := range fields.ProcessVolumeClaims {
+		if spec.Processes == nil {
+			spec.Processes = make(map[string]ProcessSettings)
+		}
+		settings := spec.Processes[processClass]
+		settings.VolumeClaim = volumeClaim
+		spec.Processes[processClass] = settings
+	}
+}
+
+// getDeprecatedContainerFields gets the values of the deprecated fields in
+// the container overrides. This will return nil if none of the deprecated
+// fields are set.
+func getDeprecatedContainerFields(overrides ContainerOverrides) *deprecatedContainerFields {
+	fields := &deprecatedContainerFields{
+		Env:             overrides.Env,
+		VolumeMounts:    overrides.VolumeMounts,
+		ImageName:       overrides.ImageName,
+		SecurityContext: overrides.SecurityContext,
+	}
+
+	if reflect.DeepEqual(*fields, deprecatedContainerFields{}) {
+		return nil
+	}
+	return fields
+}
+
+// apply sets the deprecated fields in the container overrides.
+func (fields *deprecatedContainerFields) apply(overrides *ContainerOverrides) {
+	if fields == nil {
+		return
+	}
+	overrides.Env = fields.Env
+	overrides.VolumeMounts = fields.VolumeMounts
+	overrides.ImageName = fields.ImageName
+	overrides.SecurityContext = fields.SecurityContext
+}
+
+// setDeprecatedFieldsAnnotation stores the deprecated fields from a resource
+// in an annotation on the converted resource. If there are no deprecated
+// fields, this will remove the annotation.
+func setDeprecatedFieldsAnnotation(metadata *metav1.ObjectMeta, fields interface{}) error {
+	if reflect.ValueOf(fields).IsNil() {
+		delete(metadata.Annotations, DeprecatedFieldsAnnotation)
+		return nil
+	}
+
+	annotation, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	if metadata.Annotations == nil {
+		metadata.Annotations = make(map[string]string)
+	}
+	metadata.Annotations[DeprecatedFieldsAnnotation] = string(annotation)
+	return nil
+}
+
+// convertThroughJSON copies the fields from one version of a structure to
+// another version by encoding the source as JSON and decoding it into the
+// destination.
+//
+// Fields that were removed or renamed between versions must be handled
+// separately.
+func convertThroughJSON(src interface{}, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
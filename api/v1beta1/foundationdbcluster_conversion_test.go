@@ -0,0 +1,281 @@
+/*
+ * foundationdbcluster_conversion_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createConversionCluster() *FoundationDBCluster {
+	return &FoundationDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "sample-cluster",
+			Namespace:  "default",
+			Generation: 3,
+			Labels:     map[string]string{"app": "sample"},
+		},
+		Spec: FoundationDBClusterSpec{
+			Version:              Versions.Default.String(),
+			FaultDomain:          FoundationDBClusterFaultDomain{Key: "foundationdb.org/none"},
+			ProcessCounts:        ProcessCounts{Storage: 5, Log: 4},
+			SeedConnectionString: "test:abcd@127.0.0.1:4501",
+			DatabaseConfiguration: DatabaseConfiguration{
+				RedundancyMode: "double",
+				StorageEngine:  "ssd",
+			},
+			Processes: map[string]ProcessSettings{
+				"general": {
+					CustomParameters: &[]string{"knob_disable_posix_kernel_aio=1"},
+				},
+			},
+			MainContainer: ContainerOverrides{
+				EnableTLS: true,
+			},
+		},
+		Status: FoundationDBClusterStatus{
+			ProcessCounts: ProcessCounts{Storage: 5, Log: 4},
+			Generations: ClusterGenerationStatus{
+				Reconciled:          2,
+				DatabaseUnavailable: 3,
+			},
+			DatabaseConfiguration: DatabaseConfiguration{
+				RedundancyMode: "double",
+				StorageEngine:  "ssd",
+			},
+		},
+	}
+}
+
+func TestConvertingClusterWithoutDeprecatedFields(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := createConversionCluster()
+	hub := &v1beta2.FoundationDBCluster{}
+	err := cluster.ConvertTo(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(hub.ObjectMeta).To(gomega.Equal(cluster.ObjectMeta))
+	g.Expect(hub.Annotations).NotTo(gomega.HaveKey(DeprecatedFieldsAnnotation))
+	g.Expect(hub.Spec.Version).To(gomega.Equal(cluster.Spec.Version))
+	g.Expect(hub.Spec.SeedConnectionString).To(gomega.Equal("test:abcd@127.0.0.1:4501"))
+	g.Expect(hub.Spec.ProcessCounts.Storage).To(gomega.Equal(5))
+	g.Expect(hub.Spec.DatabaseConfiguration.RedundancyMode).To(gomega.Equal("double"))
+	g.Expect(hub.Spec.MainContainer.EnableTLS).To(gomega.BeTrue())
+	g.Expect(*hub.Spec.Processes["general"].CustomParameters).To(gomega.Equal([]string{"knob_disable_posix_kernel_aio=1"}))
+	g.Expect(hub.Status.ProcessCounts.Log).To(gomega.Equal(4))
+	g.Expect(hub.Status.Generations.Reconciled).To(gomega.Equal(int64(2)))
+	g.Expect(hub.Status.Generations.DatabaseUnavailable).To(gomega.Equal(int64(3)))
+
+	converted := &FoundationDBCluster{}
+	err = converted.ConvertFrom(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted).To(gomega.Equal(cluster))
+}
+
+func TestConvertingClusterWithDeprecatedFields(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := createConversionCluster()
+	storageClass := "ebs"
+	cluster.Spec.SidecarVersion = 2
+	cluster.Spec.PodLabels = map[string]string{"fdb-label": "value"}
+	cluster.Spec.StorageClass = &storageClass
+	cluster.Spec.VolumeSize = "16G"
+	cluster.Spec.CustomParameters = []string{"knob_test=1"}
+	cluster.Spec.PendingRemovals = map[string]string{"storage-1": "127.0.0.1"}
+	cluster.Spec.MainContainer.Env = []corev1.EnvVar{{Name: "FDB_TLS_CA_FILE", Value: "/tmp/ca.pem"}}
+	cluster.Spec.SidecarContainer.ImageName = "fdb/sidecar"
+	cluster.Spec.Processes["storage"] = ProcessSettings{
+		VolumeClaim: &corev1.PersistentVolumeClaim{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("64G"),
+					},
+				},
+			},
+		},
+	}
+
+	hub := &v1beta2.FoundationDBCluster{}
+	err := cluster.ConvertTo(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cluster.Annotations).To(gomega.BeNil())
+
+	g.Expect(hub.Annotations).To(gomega.HaveKeyWithValue(DeprecatedFieldsAnnotation, `{"sidecarVersion":2,"pendingRemovals":{"storage-1":"127.0.0.1"}}`))
+
+	generalSettings := hub.Spec.Processes["general"]
+	g.Expect(*generalSettings.CustomParameters).To(gomega.Equal([]string{"knob_disable_posix_kernel_aio=1", "knob_test=1"}))
+	g.Expect(generalSettings.PodTemplate.Labels).To(gomega.Equal(map[string]string{"fdb-label": "value"}))
+	g.Expect(generalSettings.PodTemplate.Spec.InitContainers).To(gomega.Equal([]corev1.Container{
+		{Name: "foundationdb-kubernetes-init", Image: "fdb/sidecar"},
+	}))
+	g.Expect(generalSettings.PodTemplate.Spec.Containers).To(gomega.Equal([]corev1.Container{
+		{Name: "foundationdb", Env: []corev1.EnvVar{{Name: "FDB_TLS_CA_FILE", Value: "/tmp/ca.pem"}}},
+		{Name: "foundationdb-kubernetes-sidecar", Image: "fdb/sidecar"},
+	}))
+	g.Expect(generalSettings.VolumeClaimTemplate.Labels).To(gomega.Equal(map[string]string{"fdb-label": "value"}))
+	g.Expect(generalSettings.VolumeClaimTemplate.Spec.Resources.Requests).To(gomega.Equal(corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("16G"),
+	}))
+	g.Expect(*generalSettings.VolumeClaimTemplate.Spec.StorageClassName).To(gomega.Equal("ebs"))
+
+	storageSettings := hub.Spec.Processes["storage"]
+	g.Expect(storageSettings.PodTemplate).To(gomega.Equal(generalSettings.PodTemplate))
+	g.Expect(storageSettings.CustomParameters).To(gomega.BeNil())
+	g.Expect(storageSettings.VolumeClaimTemplate.Labels).To(gomega.Equal(map[string]string{"fdb-label": "value"}))
+	g.Expect(storageSettings.VolumeClaimTemplate.Spec.Resources.Requests).To(gomega.Equal(corev1.ResourceList{
+		corev1.ResourceStorage: resource.MustParse("16G"),
+	}))
+	g.Expect(*storageSettings.VolumeClaimTemplate.Spec.StorageClassName).To(gomega.Equal("ebs"))
+
+	g.Expect(hub.Spec.ConfigMap.Labels).To(gomega.Equal(map[string]string{"fdb-label": "value"}))
+
+	converted := &FoundationDBCluster{}
+	err = converted.ConvertFrom(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted.Annotations).NotTo(gomega.HaveKey(DeprecatedFieldsAnnotation))
+	g.Expect(converted.Spec.SidecarVersion).To(gomega.Equal(2))
+	g.Expect(converted.Spec.PendingRemovals).To(gomega.Equal(map[string]string{"storage-1": "127.0.0.1"}))
+	g.Expect(converted.Spec.PodLabels).To(gomega.BeNil())
+	g.Expect(converted.Spec.VolumeSize).To(gomega.BeEmpty())
+	g.Expect(converted.Spec.MainContainer).To(gomega.Equal(ContainerOverrides{EnableTLS: true}))
+	g.Expect(converted.Spec.Processes["storage"].VolumeClaim).To(gomega.BeNil())
+	g.Expect(converted.Spec.Processes["storage"].VolumeClaimTemplate).To(gomega.Equal(storageSettings.VolumeClaimTemplate))
+	g.Expect(converted.Status).To(gomega.Equal(cluster.Status))
+}
+
+func TestConvertingClusterWithDeprecatedPodFields(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := createConversionCluster()
+	automountServiceAccountToken := false
+	runAsUser := int64(1000)
+	cluster.Spec.Resources = &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+	}
+	cluster.Spec.Containers = []corev1.Container{{Name: "logger"}}
+	cluster.Spec.Volumes = []corev1.Volume{{Name: "logs"}}
+	cluster.Spec.PodSecurityContext = &corev1.PodSecurityContext{RunAsUser: &runAsUser}
+	cluster.Spec.AutomountServiceAccountToken = &automountServiceAccountToken
+	cluster.Spec.MainContainer.Env = []corev1.EnvVar{{Name: "FDB_TLS_CA_FILE", Value: "/tmp/ca.pem"}}
+	cluster.Spec.MainContainer.VolumeMounts = []corev1.VolumeMount{{Name: "logs", MountPath: "/var/logs"}}
+	cluster.Spec.Processes["log"] = ProcessSettings{
+		PodTemplate: &corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: "foundationdb",
+					Env: []corev1.EnvVar{
+						{Name: "FDB_TLS_CA_FILE", Value: "/var/ca.pem"},
+						{Name: "FDB_TLS_VERIFY_PEERS", Value: "S.CN=test"},
+					},
+				}},
+			},
+		},
+	}
+
+	hub := &v1beta2.FoundationDBCluster{}
+	err := cluster.ConvertTo(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(hub.Annotations).NotTo(gomega.HaveKey(DeprecatedFieldsAnnotation))
+	g.Expect(hub.Spec.Processes["general"].VolumeClaimTemplate).To(gomega.BeNil())
+	g.Expect(hub.Spec.ConfigMap).To(gomega.BeNil())
+
+	for _, processClass := range []string{"general", "log"} {
+		podSpec := hub.Spec.Processes[processClass].PodTemplate.Spec
+		g.Expect(podSpec.Containers).To(gomega.HaveLen(2))
+		g.Expect(podSpec.Containers[0].Name).To(gomega.Equal("foundationdb"))
+		g.Expect(podSpec.Containers[0].Resources).To(gomega.Equal(*cluster.Spec.Resources))
+		g.Expect(podSpec.Containers[0].VolumeMounts).To(gomega.Equal(cluster.Spec.MainContainer.VolumeMounts))
+		g.Expect(podSpec.Containers[1]).To(gomega.Equal(corev1.Container{Name: "logger"}))
+		g.Expect(podSpec.Volumes).To(gomega.Equal(cluster.Spec.Volumes))
+		g.Expect(podSpec.SecurityContext).To(gomega.Equal(cluster.Spec.PodSecurityContext))
+		g.Expect(*podSpec.AutomountServiceAccountToken).To(gomega.BeFalse())
+	}
+
+	g.Expect(hub.Spec.Processes["general"].PodTemplate.Spec.Containers[0].Env).To(gomega.Equal([]corev1.EnvVar{
+		{Name: "FDB_TLS_CA_FILE", Value: "/tmp/ca.pem"},
+	}))
+	g.Expect(hub.Spec.Processes["log"].PodTemplate.Spec.Containers[0].Env).To(gomega.Equal([]corev1.EnvVar{
+		{Name: "FDB_TLS_CA_FILE", Value: "/tmp/ca.pem"},
+		{Name: "FDB_TLS_VERIFY_PEERS", Value: "S.CN=test"},
+	}))
+	g.Expect(cluster.Spec.Processes["log"].PodTemplate.Spec.Containers[0].Env[0].Value).To(gomega.Equal("/var/ca.pem"))
+
+	converted := &FoundationDBCluster{}
+	err = converted.ConvertFrom(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted.Spec.Resources).To(gomega.BeNil())
+	g.Expect(converted.Spec.Containers).To(gomega.BeNil())
+	g.Expect(converted.Spec.Volumes).To(gomega.BeNil())
+	g.Expect(converted.Spec.PodSecurityContext).To(gomega.BeNil())
+	g.Expect(converted.Spec.AutomountServiceAccountToken).To(gomega.BeNil())
+	g.Expect(converted.Spec.Processes["general"].PodTemplate).To(gomega.Equal(hub.Spec.Processes["general"].PodTemplate))
+}
+
+func TestConvertingClusterWithInvalidVolumeSize(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := createConversionCluster()
+	cluster.Spec.VolumeSize = "large"
+
+	hub := &v1beta2.FoundationDBCluster{}
+	err := cluster.ConvertTo(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(hub.Annotations).To(gomega.HaveKeyWithValue(DeprecatedFieldsAnnotation, `{"volumeSize":"large"}`))
+	g.Expect(hub.Spec.Processes["general"].VolumeClaimTemplate).To(gomega.BeNil())
+
+	converted := &FoundationDBCluster{}
+	err = converted.ConvertFrom(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted.Spec).To(gomega.Equal(cluster.Spec))
+	g.Expect(converted.Status).To(gomega.Equal(cluster.Status))
+}
+
+func TestConvertingClusterWithPodTemplate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := createConversionCluster()
+	cluster.Spec.Processes = nil
+	cluster.Spec.PodTemplate = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "foundationdb"}},
+		},
+	}
+
+	hub := &v1beta2.FoundationDBCluster{}
+	err := cluster.ConvertTo(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(hub.Annotations).NotTo(gomega.HaveKey(DeprecatedFieldsAnnotation))
+	g.Expect(hub.Spec.Processes["general"].PodTemplate).To(gomega.Equal(cluster.Spec.PodTemplate))
+
+	converted := &FoundationDBCluster{}
+	err = converted.ConvertFrom(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted.Spec.PodTemplate).To(gomega.BeNil())
+	g.Expect(converted.Spec.Processes["general"].PodTemplate).To(gomega.Equal(cluster.Spec.PodTemplate))
+}
@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"html/template"
 	"math/rand"
+	"net"
 	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +36,7 @@ import (
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:shortName=fdb
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.processCounts.storage,statuspath=.status.processCounts.storage,selectorpath=.status.storageProcessSelector
 // +kubebuilder:printcolumn:name="Generation",type="integer",JSONPath=".metadata.generation",description="Latest generation of the spec",priority=0
 // +kubebuilder:printcolumn:name="Reconciled",type="integer",JSONPath=".status.generations.reconciled",description="Last reconciled generation of the spec",priority=0
 // +kubebuilder:printcolumn:name="Healthy",type="boolean",JSONPath=".status.health.healthy",description="Database health",priority=0
@@ -145,6 +148,16 @@ type FoundationDBClusterSpec struct {
 	// by replacing the pods rather than deleting them.
 	UpdatePodsByReplacement bool `json:"updatePodsByReplacement,omitempty"`
 
+	// BounceStrategy defines how the operator restarts processes when their
+	// configuration changes.
+	BounceStrategy BounceStrategyConfig `json:"bounceStrategy,omitempty"`
+
+	// CanaryUpgrade defines a set of processes that the operator should
+	// upgrade first when the cluster moves to a new patch release, so that
+	// the new version can be tested on a small part of the cluster before it
+	// is rolled out everywhere.
+	CanaryUpgrade CanaryUpgradeConfig `json:"canaryUpgrade,omitempty"`
+
 	// LockOptions allows customizing how we manage locks for global operations.
 	LockOptions LockOptions `json:"lockOptions,omitempty"`
 
@@ -152,10 +165,46 @@ type FoundationDBClusterSpec struct {
 	// pods.
 	Services ServiceConfig `json:"services,omitempty"`
 
+	// PodIPFamily defines the family of the IP addresses that the processes
+	// use as their public IPs. This can be 4 for IPv4 or 6 for IPv6.
+	//
+	// When this is 6, the operator wraps the public IPs in brackets when it
+	// builds the addresses for the processes. In a dual-stack environment,
+	// this must match the family of the primary IP for the pods.
+	//
+	// The default is 4.
+	PodIPFamily *int `json:"podIPFamily,omitempty"`
+
+	// PodDisruptionBudgets defines the configuration for the pod disruption
+	// budget that the operator manages for the cluster.
+	PodDisruptionBudgets PodDisruptionBudgetConfig `json:"podDisruptionBudgets,omitempty"`
+
+	// CoordinatorSelection defines how the operator chooses the processes
+	// that serve as coordinators.
+	CoordinatorSelection CoordinatorSelectionConfig `json:"coordinatorSelection,omitempty"`
+
+	// Autoscaling defines the configuration for automatically scaling the
+	// storage processes based on disk utilization.
+	Autoscaling AutoscalingConfig `json:"autoscaling,omitempty"`
+
 	// IgnoreUpgradabilityChecks determines whether we should skip the check for
 	// client compatibility when performing an upgrade.
 	IgnoreUpgradabilityChecks bool `json:"ignoreUpgradabilityChecks,omitempty"`
 
+	// AllowIncompatibleDowngrade determines whether we should allow
+	// downgrading the cluster to a version that is not protocol-compatible
+	// with the running version.
+	//
+	// Downgrades between protocol-compatible versions, such as from 6.2.20 to
+	// 6.2.19, are always allowed.
+	AllowIncompatibleDowngrade bool `json:"allowIncompatibleDowngrade,omitempty"`
+
+	// UseNativeAdminClient determines whether the operator should use the
+	// FoundationDB client library to run administrative operations on this
+	// cluster, rather than running fdbcli. If this is omitted, the operator
+	// will use its global default.
+	UseNativeAdminClient *bool `json:"useNativeAdminClient,omitempty"`
+
 	// SidecarVersion defines the build version of the sidecar to use.
 	//
 	// Deprecated: Use SidecarVersions instead.
@@ -291,6 +340,11 @@ type FoundationDBClusterStatus struct {
 	// reconciled, or to reach other stages at which reconciliation can halt.
 	Generations ClusterGenerationStatus `json:"generations,omitempty"`
 
+	// Conditions provides the standard conditions describing the state of the
+	// cluster. These are updated whenever we check the reconciliation of the
+	// cluster.
+	Conditions []ClusterCondition `json:"conditions,omitempty"`
+
 	// Health provides information about the health of the database.
 	Health ClusterHealth `json:"health,omitempty"`
 
@@ -324,10 +378,111 @@ type FoundationDBClusterStatus struct {
 	// This maps the instance ID to its removal state.
 	PendingRemovals map[string]PendingRemovalState `json:"pendingRemovals,omitempty"`
 
+	// ProcessGroups provides the state of the processes for each instance
+	// ID, including any problems we have observed with them.
+	ProcessGroups []ProcessGroupStatus `json:"processGroups,omitempty"`
+
 	// NeedsSidecarConfInConfigMap determines whether we need to include the
 	// sidecar conf in the config map even when the latest version should not
 	// require it.
 	NeedsSidecarConfInConfigMap bool `json:"needsSidecarConfInConfigMap,omitempty"`
+
+	// MaintenanceZone provides the zone that the operator has put into
+	// maintenance mode while it updates the processes in that zone.
+	MaintenanceZone string `json:"maintenanceZone,omitempty"`
+
+	// Autoscaling provides information about the storage autoscaling
+	// decisions the operator has made.
+	Autoscaling AutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// StorageProcessSelector provides the label selector for the storage
+	// pods, in the serialized form used by the scale subresource.
+	StorageProcessSelector string `json:"storageProcessSelector,omitempty"`
+
+	// Bounce provides information about a bounce that the operator is doing
+	// in multiple batches.
+	Bounce BounceStatus `json:"bounce,omitempty"`
+
+	// UpgradeLaggingPods provides the pods that do not yet have the binaries
+	// for the version that the cluster is upgrading to. The operator will not
+	// bounce the processes for the upgrade until this is empty.
+	//
+	// This will contain the name of the pod.
+	UpgradeLaggingPods []string `json:"upgradeLaggingPods,omitempty"`
+
+	// CanaryUpgrade provides information about the canary processes for the
+	// upgrade that is in progress.
+	CanaryUpgrade CanaryUpgradeStatus `json:"canaryUpgrade,omitempty"`
+}
+
+// BounceStatus records the progress of a bounce that the operator is doing
+// in multiple batches.
+type BounceStatus struct {
+	// Strategy provides the strategy that the operator is using for the
+	// bounce.
+	Strategy BounceStrategyType `json:"strategy,omitempty"`
+
+	// CurrentBatch provides the IDs of the instances that the operator
+	// bounced most recently. The operator will wait for these instances to
+	// rejoin the cluster before it bounces any more instances.
+	CurrentBatch []string `json:"currentBatch,omitempty"`
+
+	// BouncedInstances provides the IDs of all of the instances that the
+	// operator has bounced as part of this bounce.
+	BouncedInstances []string `json:"bouncedInstances,omitempty"`
+}
+
+// CanaryUpgradeStatus records the progress of the canary processes for an
+// upgrade.
+type CanaryUpgradeStatus struct {
+	// Version provides the version that the canary processes were upgraded
+	// to.
+	Version string `json:"version,omitempty"`
+
+	// Instances provides the IDs of the instances that the operator chose as
+	// canaries.
+	Instances []string `json:"instances,omitempty"`
+
+	// State provides the stage of the canary upgrade.
+	State CanaryUpgradeState `json:"state,omitempty"`
+
+	// StartTimestamp provides the time when the canary processes entered
+	// their current state, as a Unix timestamp.
+	StartTimestamp int64 `json:"startTimestamp,omitempty"`
+
+	// Message provides the reason that the operator halted the upgrade.
+	Message string `json:"message,omitempty"`
+}
+
+// CanaryUpgradeState describes the stage of a canary upgrade.
+type CanaryUpgradeState string
+
+const (
+	// CanaryUpgradeStarting indicates that the canary processes have been
+	// bounced, and the operator is waiting for them to rejoin the cluster.
+	CanaryUpgradeStarting CanaryUpgradeState = "starting"
+
+	// CanaryUpgradeSoaking indicates that the canary processes are running
+	// the new version, and the operator is watching the health of the
+	// cluster before it upgrades the rest of the processes.
+	CanaryUpgradeSoaking CanaryUpgradeState = "soaking"
+
+	// CanaryUpgradePromoted indicates that the cluster stayed healthy for
+	// the soak period, and the operator is upgrading the rest of the
+	// processes.
+	CanaryUpgradePromoted CanaryUpgradeState = "promoted"
+
+	// CanaryUpgradeHalted indicates that the operator has stopped the
+	// upgrade because the canary processes or the cluster were not healthy.
+	CanaryUpgradeHalted CanaryUpgradeState = "halted"
+)
+
+// AutoscalingStatus records information about the storage autoscaling
+// decisions the operator has made.
+type AutoscalingStatus struct {
+	// LastScaleTimestamp provides the time when the operator last changed the
+	// storage process count, as a Unix timestamp.
+	LastScaleTimestamp int64 `json:"lastScaleTimestamp,omitempty"`
 }
 
 // ClusterGenerationStatus stores information on which generations have reached
@@ -396,6 +551,55 @@ type ClusterGenerationStatus struct {
 	HasFailingPods int64 `json:"hasFailingPods,omitempty"`
 }
 
+// ClusterConditionType defines a kind of condition that we report on a
+// cluster.
+type ClusterConditionType string
+
+const (
+	// ClusterConditionAvailable indicates whether the database is accepting
+	// reads and writes.
+	ClusterConditionAvailable ClusterConditionType = "Available"
+
+	// ClusterConditionFullyReplicated indicates whether all data in the
+	// database is fully replicated.
+	ClusterConditionFullyReplicated ClusterConditionType = "FullyReplicated"
+
+	// ClusterConditionReconciled indicates whether the latest generation of
+	// the spec has been fully reconciled.
+	ClusterConditionReconciled ClusterConditionType = "Reconciled"
+
+	// ClusterConditionUpgradeInProgress indicates whether the cluster is
+	// running a different version from the one in the spec.
+	ClusterConditionUpgradeInProgress ClusterConditionType = "UpgradeInProgress"
+
+	// ClusterConditionCoordinatorsValid indicates whether the current
+	// coordinators meet the fault tolerance requirements for the cluster.
+	ClusterConditionCoordinatorsValid ClusterConditionType = "CoordinatorsValid"
+
+	// ClusterConditionPodsFailing indicates whether any pods in the cluster
+	// are failing to start.
+	ClusterConditionPodsFailing ClusterConditionType = "PodsFailing"
+)
+
+// ClusterCondition describes one aspect of the state of the cluster.
+type ClusterCondition struct {
+	// Type provides the kind of condition.
+	Type ClusterConditionType `json:"type"`
+
+	// Status provides whether the condition holds. This will be True, False,
+	// or Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+
+	// Reason provides a machine-readable reason for the current status.
+	Reason string `json:"reason,omitempty"`
+
+	// Message provides a human-readable description of the current status.
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime provides the last time that the status changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
 // ClusterHealth represents different views into health in the cluster status.
 type ClusterHealth struct {
 	// Available reports whether the database is accepting reads and writes.
@@ -419,6 +623,10 @@ type PendingRemovalState struct {
 	PodName string `json:"podName,omitempty"`
 
 	// The public address of the process.
+	//
+	// On versions that support locality-based exclusions, the exclusion is
+	// tracked by instance ID, and this is only used to clean up exclusions
+	// that were made by address.
 	Address string `json:"address,omitempty"`
 
 	// Whether we have started the exclusion.
@@ -428,6 +636,148 @@ type PendingRemovalState struct {
 	ExclusionComplete bool `json:"exclusionComplete,omitempty"`
 }
 
+// ProcessGroupStatus describes the state of the processes for a single
+// instance ID.
+type ProcessGroupStatus struct {
+	// InstanceID provides the instance ID for the processes.
+	InstanceID string `json:"instanceID"`
+
+	// ProcessClass provides the process class for the processes.
+	ProcessClass string `json:"processClass,omitempty"`
+
+	// Addresses provides the addresses that the processes were last seen
+	// at.
+	Addresses []string `json:"addresses,omitempty"`
+
+	// Conditions provides the problems and lifecycle stages that currently
+	// apply to the processes.
+	Conditions []ProcessGroupCondition `json:"conditions,omitempty"`
+}
+
+// ProcessGroupConditionType defines a kind of condition that can apply to a
+// process group.
+type ProcessGroupConditionType string
+
+const (
+	// MissingProcess indicates that the process is not reporting to the
+	// database.
+	MissingProcess ProcessGroupConditionType = "MissingProcess"
+
+	// IncorrectCommandLine indicates that the process is running with a
+	// command line or version that does not match the spec.
+	IncorrectCommandLine ProcessGroupConditionType = "IncorrectCommandLine"
+
+	// IncorrectPodSpec indicates that the pod or its volume claim does not
+	// match the spec.
+	IncorrectPodSpec ProcessGroupConditionType = "IncorrectPodSpec"
+
+	// PodFailing indicates that some of the containers in the pod are not
+	// ready.
+	PodFailing ProcessGroupConditionType = "PodFailing"
+
+	// Excluded indicates that the exclusion of the process has completed.
+	Excluded ProcessGroupConditionType = "Excluded"
+
+	// MarkedForRemoval indicates that the process is pending removal.
+	MarkedForRemoval ProcessGroupConditionType = "MarkedForRemoval"
+)
+
+// ProcessGroupCondition records a condition that applies to a process group.
+type ProcessGroupCondition struct {
+	// Type provides the kind of condition.
+	Type ProcessGroupConditionType `json:"type"`
+
+	// Timestamp provides the Unix timestamp when we first observed the
+	// condition.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// GetConditionTime gets the time when we first observed a condition on the
+// process group.
+//
+// This will return nil if the condition does not currently apply.
+func (processGroup *ProcessGroupStatus) GetConditionTime(conditionType ProcessGroupConditionType) *int64 {
+	for _, condition := range processGroup.Conditions {
+		if condition.Type == conditionType {
+			timestamp := condition.Timestamp
+			return &timestamp
+		}
+	}
+	return nil
+}
+
+// HasCondition determines whether a condition currently applies to the
+// process group.
+func (processGroup *ProcessGroupStatus) HasCondition(conditionType ProcessGroupConditionType) bool {
+	return processGroup.GetConditionTime(conditionType) != nil
+}
+
+// UpdateCondition adds or removes a condition on the process group.
+//
+// If the condition already applies, this will keep the time when we first
+// observed it.
+func (processGroup *ProcessGroupStatus) UpdateCondition(conditionType ProcessGroupConditionType, present bool) {
+	if present {
+		if !processGroup.HasCondition(conditionType) {
+			processGroup.Conditions = append(processGroup.Conditions, ProcessGroupCondition{
+				Type:      conditionType,
+				Timestamp: time.Now().Unix(),
+			})
+		}
+		return
+	}
+
+	for index, condition := range processGroup.Conditions {
+		if condition.Type == conditionType {
+			processGroup.Conditions = append(processGroup.Conditions[:index], processGroup.Conditions[index+1:]...)
+			break
+		}
+	}
+	if len(processGroup.Conditions) == 0 {
+		processGroup.Conditions = nil
+	}
+}
+
+// GetProcessGroup gets the status for the process group with an instance ID.
+//
+// This will return nil if there is no status for the instance.
+func (status *FoundationDBClusterStatus) GetProcessGroup(instanceID string) *ProcessGroupStatus {
+	for index := range status.ProcessGroups {
+		if status.ProcessGroups[index].InstanceID == instanceID {
+			return &status.ProcessGroups[index]
+		}
+	}
+	return nil
+}
+
+// UpdateProcessGroupRemovals updates the MarkedForRemoval and Excluded
+// conditions on the process groups to match the pending removals.
+//
+// This will add process groups for any pending removals that do not have
+// one.
+func (status *FoundationDBClusterStatus) UpdateProcessGroupRemovals() {
+	for instanceID, state := range status.PendingRemovals {
+		if status.GetProcessGroup(instanceID) == nil {
+			status.ProcessGroups = append(status.ProcessGroups, ProcessGroupStatus{InstanceID: instanceID})
+		}
+		processGroup := status.GetProcessGroup(instanceID)
+		if len(processGroup.Addresses) == 0 && state.Address != "" {
+			processGroup.Addresses = []string{state.Address}
+		}
+	}
+
+	for index := range status.ProcessGroups {
+		processGroup := &status.ProcessGroups[index]
+		state, pendingRemoval := status.PendingRemovals[processGroup.InstanceID]
+		processGroup.UpdateCondition(MarkedForRemoval, pendingRemoval)
+		processGroup.UpdateCondition(Excluded, pendingRemoval && state.ExclusionComplete)
+	}
+
+	sort.Slice(status.ProcessGroups, func(i, j int) bool {
+		return status.ProcessGroups[i].InstanceID < status.ProcessGroups[j].InstanceID
+	})
+}
+
 // RoleCounts represents the roles whose counts can be customized.
 type RoleCounts struct {
 	Storage    int `json:"storage,omitempty"`
@@ -580,6 +930,28 @@ type FoundationDBClusterAutomationOptions struct {
 	// DeletePods defines whether the operator is allowed to delete pods in
 	// order to recreate them.
 	DeletePods *bool `json:"deletePods,omitempty"`
+
+	// Replacements configures automatic replacement of processes that have
+	// stopped reporting to the database.
+	Replacements AutomaticReplacementOptions `json:"replacements,omitempty"`
+}
+
+// AutomaticReplacementOptions controls options for automatically replacing
+// processes that have stopped reporting to the database.
+type AutomaticReplacementOptions struct {
+	// Enabled controls whether automatic replacements are enabled.
+	// The default is false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// FailureDetectionTimeSeconds controls how long a process must be
+	// missing from the database status before we replace it.
+	// The default is 1800.
+	FailureDetectionTimeSeconds *int `json:"failureDetectionTimeSeconds,omitempty"`
+
+	// MaxConcurrentReplacements controls how many processes can be pending
+	// removal before we stop starting new automatic replacements.
+	// The default is 1.
+	MaxConcurrentReplacements *int `json:"maxConcurrentReplacements,omitempty"`
 }
 
 // ProcessSettings defines process-level settings.
@@ -848,6 +1220,7 @@ func (cluster *FoundationDBCluster) CheckReconciliation() (bool, error) {
 	var reconciled = true
 	if !cluster.Status.Configured {
 		cluster.Status.Generations.NeedsConfigurationChange = cluster.ObjectMeta.Generation
+		cluster.updateConditions(false)
 		return false, nil
 	}
 
@@ -944,9 +1317,108 @@ func (cluster *FoundationDBCluster) CheckReconciliation() (bool, error) {
 	if reconciled {
 		cluster.Status.Generations.Reconciled = cluster.ObjectMeta.Generation
 	}
+	cluster.updateConditions(reconciled)
 	return reconciled, nil
 }
 
+// updateConditions updates the conditions in the cluster status based on
+// the rest of the status.
+func (cluster *FoundationDBCluster) updateConditions(reconciled bool) {
+	status := &cluster.Status
+
+	if status.Health.Available {
+		status.SetCondition(ClusterConditionAvailable, true, "DatabaseAvailable", "The database is accepting reads and writes")
+	} else {
+		status.SetCondition(ClusterConditionAvailable, false, "DatabaseUnavailable", "The database is not accepting reads and writes")
+	}
+
+	if status.Health.FullReplication {
+		status.SetCondition(ClusterConditionFullyReplicated, true, "FullyReplicated", "All data is fully replicated")
+	} else {
+		status.SetCondition(ClusterConditionFullyReplicated, false, "NotFullyReplicated", "Some data is not fully replicated")
+	}
+
+	if reconciled {
+		status.SetCondition(ClusterConditionReconciled, true, "Reconciled", fmt.Sprintf("Generation %d has been reconciled", cluster.ObjectMeta.Generation))
+	} else if !status.Configured {
+		status.SetCondition(ClusterConditionReconciled, false, "DatabaseNotConfigured", "The database has not been configured")
+	} else {
+		status.SetCondition(ClusterConditionReconciled, false, "ReconciliationPending", fmt.Sprintf("Reconciliation is pending on: %s", strings.Join(status.Generations.pendingStages(), ", ")))
+	}
+
+	if status.RunningVersion != "" && status.RunningVersion != cluster.Spec.Version {
+		status.SetCondition(ClusterConditionUpgradeInProgress, true, "VersionMismatch", fmt.Sprintf("Upgrading from version %s to version %s", status.RunningVersion, cluster.Spec.Version))
+	} else {
+		status.SetCondition(ClusterConditionUpgradeInProgress, false, "VersionMatches", fmt.Sprintf("The cluster is running version %s", cluster.Spec.Version))
+	}
+
+	if status.NeedsNewCoordinators {
+		status.SetCondition(ClusterConditionCoordinatorsValid, false, "CoordinatorsInvalid", "The coordinators do not meet the fault tolerance requirements")
+	} else {
+		status.SetCondition(ClusterConditionCoordinatorsValid, true, "CoordinatorsValid", "The coordinators meet the fault tolerance requirements")
+	}
+
+	if len(status.FailingPods) > 0 {
+		status.SetCondition(ClusterConditionPodsFailing, true, "PodsFailing", fmt.Sprintf("Pods are failing to start: %s", strings.Join(status.FailingPods, ", ")))
+	} else {
+		status.SetCondition(ClusterConditionPodsFailing, false, "NoPodsFailing", "All pods have started")
+	}
+}
+
+// GetCondition gets the condition of a given type from the cluster status.
+//
+// This will return nil if the status does not have a condition of that type.
+func (status *FoundationDBClusterStatus) GetCondition(conditionType ClusterConditionType) *ClusterCondition {
+	for index := range status.Conditions {
+		if status.Conditions[index].Type == conditionType {
+			return &status.Conditions[index]
+		}
+	}
+	return nil
+}
+
+// SetCondition sets the status, reason, and message for a condition in the
+// cluster status.
+//
+// The transition time will only be updated if the status of the condition
+// has changed.
+func (status *FoundationDBClusterStatus) SetCondition(conditionType ClusterConditionType, value bool, reason string, message string) {
+	conditionStatus := corev1.ConditionFalse
+	if value {
+		conditionStatus = corev1.ConditionTrue
+	}
+
+	condition := status.GetCondition(conditionType)
+	if condition == nil {
+		status.Conditions = append(status.Conditions, ClusterCondition{Type: conditionType})
+		condition = &status.Conditions[len(status.Conditions)-1]
+	}
+
+	if condition.Status != conditionStatus {
+		condition.Status = conditionStatus
+		condition.LastTransitionTime = metav1.Now()
+	}
+	condition.Reason = reason
+	condition.Message = message
+}
+
+// pendingStages gets the names of the stages of reconciliation that have not
+// been completed for the latest generation.
+func (generations ClusterGenerationStatus) pendingStages() []string {
+	stages := make([]string, 0)
+	value := reflect.ValueOf(generations)
+	for index := 0; index < value.NumField(); index++ {
+		name := value.Type().Field(index).Name
+		if name == "Reconciled" || name == "HasPendingRemoval" {
+			continue
+		}
+		if value.Field(index).Int() > 0 {
+			stages = append(stages, name)
+		}
+	}
+	return stages
+}
+
 // CountsAreSatisfied checks whether the current counts of processes satisfy
 // a desired set of counts.
 func (counts ProcessCounts) CountsAreSatisfied(currentCounts ProcessCounts) bool {
@@ -1029,6 +1501,26 @@ type FoundationDBStatusClusterInfo struct {
 	// Layers provides information about layers that are running against the
 	// cluster.
 	Layers FoundationDBStatusLayerInfo `json:"layers,omitempty"`
+
+	// MaintenanceZone provides the zone that is currently in maintenance
+	// mode, if any.
+	MaintenanceZone string `json:"maintenance_zone,omitempty"`
+
+	// FaultTolerance provides information about how many fault domains the
+	// database can lose.
+	FaultTolerance FoundationDBStatusFaultTolerance `json:"fault_tolerance,omitempty"`
+}
+
+// FoundationDBStatusFaultTolerance describes the fault tolerance of the
+// database, as reported in the cluster status.
+type FoundationDBStatusFaultTolerance struct {
+	// MaxZoneFailuresWithoutLosingData provides the number of fault domains
+	// that can fail without losing data.
+	MaxZoneFailuresWithoutLosingData int `json:"max_zone_failures_without_losing_data,omitempty"`
+
+	// MaxZoneFailuresWithoutLosingAvailability provides the number of fault
+	// domains that can fail without making the database unavailable.
+	MaxZoneFailuresWithoutLosingAvailability int `json:"max_zone_failures_without_losing_availability,omitempty"`
 }
 
 // FoundationDBStatusProcessInfo describes the "processes" portion of the
@@ -1054,6 +1546,29 @@ type FoundationDBStatusProcessInfo struct {
 
 	// The time that the process has been up for.
 	UptimeSeconds float64 `json:"uptime_seconds,omitempty"`
+
+	// Disk provides information about the disk the process is using.
+	Disk FoundationDBStatusProcessDiskInfo `json:"disk,omitempty"`
+
+	// Roles provides the roles that the process is currently serving.
+	Roles []FoundationDBStatusProcessRoleInfo `json:"roles,omitempty"`
+}
+
+// FoundationDBStatusProcessDiskInfo contains the minimal information about
+// the disk a process is using.
+type FoundationDBStatusProcessDiskInfo struct {
+	// FreeBytes provides the number of bytes that are free on the disk.
+	FreeBytes int `json:"free_bytes,omitempty"`
+
+	// TotalBytes provides the total size of the disk.
+	TotalBytes int `json:"total_bytes,omitempty"`
+}
+
+// FoundationDBStatusProcessRoleInfo contains the minimal information about a
+// process role.
+type FoundationDBStatusProcessRoleInfo struct {
+	// Role provides the name of the role.
+	Role string `json:"role,omitempty"`
 }
 
 // FoundationDBStatusDataStatistics provides information about the data in
@@ -1143,25 +1658,49 @@ type ProcessAddress struct {
 
 // ParseProcessAddress parses a structured address from its string
 // representation.
+//
+// IPv6 addresses must be wrapped in brackets, as in `[fd00::1]:4500:tls`.
+// The IP address in the result will not have the brackets, and IPv6
+// addresses will be converted to their canonical form.
 func ParseProcessAddress(address string) (ProcessAddress, error) {
 	result := ProcessAddress{}
-	components := strings.Split(address, ":")
 
-	if len(components) < 2 {
-		return result, fmt.Errorf("Invalid address: %s", address)
-	}
+	var components []string
+	if strings.HasPrefix(address, "[") {
+		end := strings.Index(address, "]")
+		if end < 0 || !strings.HasPrefix(address[end+1:], ":") {
+			return result, fmt.Errorf("Invalid address: %s", address)
+		}
+
+		ip := net.ParseIP(address[1:end])
+		if ip == nil {
+			return result, fmt.Errorf("Invalid address: %s", address)
+		}
 
-	result.IPAddress = components[0]
+		result.IPAddress = ip.String()
+		components = strings.Split(address[end+2:], ":")
+	} else {
+		components = strings.Split(address, ":")
+		if len(components) > 3 {
+			return result, fmt.Errorf("Invalid address: %s, IPv6 addresses must be wrapped in brackets", address)
+		}
+		if len(components) < 2 {
+			return result, fmt.Errorf("Invalid address: %s", address)
+		}
 
-	port, err := strconv.Atoi(components[1])
+		result.IPAddress = components[0]
+		components = components[1:]
+	}
+
+	port, err := strconv.Atoi(components[0])
 	if err != nil {
 		return result, err
 	}
 	result.Port = port
 
-	if len(components) > 2 {
-		result.Flags = make(map[string]bool, len(components)-2)
-		for _, flag := range components[2:] {
+	if len(components) > 1 {
+		result.Flags = make(map[string]bool, len(components)-1)
+		for _, flag := range components[1:] {
 			result.Flags[flag] = true
 		}
 	}
@@ -1171,7 +1710,7 @@ func ParseProcessAddress(address string) (ProcessAddress, error) {
 
 // String gets the string representation of an address.
 func (address ProcessAddress) String() string {
-	result := address.IPAddress + ":" + strconv.Itoa(address.Port)
+	result := formatIPAddress(address.IPAddress, 4) + ":" + strconv.Itoa(address.Port)
 
 	flags := make([]string, 0, len(address.Flags))
 	for flag, set := range address.Flags {
@@ -1206,6 +1745,8 @@ func (cluster *FoundationDBCluster) GetFullAddress(ipAddress string) string {
 // separated by commas. If you pass false for primaryOnly, this will return only
 // the primary address.
 func (cluster *FoundationDBCluster) GetFullAddressList(ipAddress string, primaryOnly bool) string {
+	ipAddress = formatIPAddress(ipAddress, cluster.GetPodIPFamily())
+
 	addressMap := make(map[string]bool)
 	if cluster.Status.RequiredAddresses.TLS {
 		addressMap[fmt.Sprintf("%s:4500:tls", ipAddress)] = cluster.Spec.MainContainer.EnableTLS
@@ -1226,6 +1767,31 @@ func (cluster *FoundationDBCluster) GetFullAddressList(ipAddress string, primary
 	return strings.Join(addresses, ",")
 }
 
+// formatIPAddress formats an IP address so that it can be combined with a
+// port, by wrapping IPv6 addresses in brackets.
+//
+// If the IP address is not a literal address, such as when it is a variable
+// for the sidecar to substitute, it will be wrapped in brackets when the IP
+// family is 6.
+func formatIPAddress(ipAddress string, ipFamily int) string {
+	if ipAddress == "" || strings.HasPrefix(ipAddress, "[") {
+		return ipAddress
+	}
+
+	ip := net.ParseIP(ipAddress)
+	if ip != nil {
+		if ip.To4() != nil {
+			return ipAddress
+		}
+		return "[" + ipAddress + "]"
+	}
+
+	if ipFamily == 6 {
+		return "[" + ipAddress + "]"
+	}
+	return ipAddress
+}
+
 // GetFullSidecarVersion gets the version of the image for the sidecar,
 // including the main FoundationDB version and the sidecar version suffix.
 func (cluster *FoundationDBCluster) GetFullSidecarVersion(useRunningVersion bool) string {
@@ -1291,8 +1857,34 @@ type FoundationDBClusterFaultDomain struct {
 	// KCs in the data center. This is only used in the `kubernetes-cluster`
 	// fault domain strategy.
 	ZoneIndex int `json:"zoneIndex,omitempty"`
+
+	// SpreadStrategy determines how the operator asks the scheduler to spread
+	// the pods for each process class across the fault domains. This has no
+	// effect on pods that define their own pod anti-affinity or topology
+	// spread constraints. The default is PreferredAntiAffinity.
+	SpreadStrategy PodSpreadStrategy `json:"spreadStrategy,omitempty"`
 }
 
+// PodSpreadStrategy describes how pods are spread across fault domains.
+type PodSpreadStrategy string
+
+const (
+	// PodSpreadStrategyPreferredAntiAffinity adds a preferred pod
+	// anti-affinity for the pods in the same process class.
+	PodSpreadStrategyPreferredAntiAffinity PodSpreadStrategy = "PreferredAntiAffinity"
+
+	// PodSpreadStrategyRequiredAntiAffinity adds a required pod
+	// anti-affinity for the pods in the same process class.
+	PodSpreadStrategyRequiredAntiAffinity PodSpreadStrategy = "RequiredAntiAffinity"
+
+	// PodSpreadStrategyTopologySpread adds a topology spread constraint for
+	// the pods in the same process class.
+	PodSpreadStrategyTopologySpread PodSpreadStrategy = "TopologySpreadConstraints"
+
+	// PodSpreadStrategyNone does not add any rules for spreading pods.
+	PodSpreadStrategyNone PodSpreadStrategy = "None"
+)
+
 // DatabaseConfiguration represents the configuration of the database
 type DatabaseConfiguration struct {
 	// RedundancyMode defines the core replication factor for the database.
@@ -1574,6 +2166,157 @@ func (cluster *FoundationDBCluster) ShouldUseLocks() bool {
 	return disabled == nil || !*disabled
 }
 
+// ShouldUseNativeAdminClient determines whether we should use the native
+// admin client for this cluster. The default value is used when the cluster
+// spec does not specify a preference.
+func (cluster *FoundationDBCluster) ShouldUseNativeAdminClient(defaultValue bool) bool {
+	if cluster.Spec.UseNativeAdminClient == nil {
+		return defaultValue
+	}
+	return *cluster.Spec.UseNativeAdminClient
+}
+
+// GetPodSpreadStrategy gets the strategy for spreading pods across fault
+// domains.
+func (cluster *FoundationDBCluster) GetPodSpreadStrategy() PodSpreadStrategy {
+	if cluster.Spec.FaultDomain.SpreadStrategy == "" {
+		return PodSpreadStrategyPreferredAntiAffinity
+	}
+	return cluster.Spec.FaultDomain.SpreadStrategy
+}
+
+// GetPodIPFamily gets the family of the IP addresses that the processes use
+// as their public IPs.
+func (cluster *FoundationDBCluster) GetPodIPFamily() int {
+	if cluster.Spec.PodIPFamily == nil {
+		return 4
+	}
+	return *cluster.Spec.PodIPFamily
+}
+
+// GetPublicIPSource gets the source that the processes should use for their
+// public IPs.
+func (cluster *FoundationDBCluster) GetPublicIPSource() PublicIPSource {
+	if cluster.Spec.Services.PublicIPSource == "" {
+		return PublicIPSourcePod
+	}
+	return cluster.Spec.Services.PublicIPSource
+}
+
+// GetBounceStrategyType gets the strategy that the operator should use for
+// choosing which processes to bounce together.
+func (cluster *FoundationDBCluster) GetBounceStrategyType() BounceStrategyType {
+	if cluster.Spec.BounceStrategy.Type == "" {
+		return BounceStrategyAll
+	}
+	return cluster.Spec.BounceStrategy.Type
+}
+
+// UsesCanaryUpgrades determines whether the operator should upgrade a set of
+// canary processes before the rest of the cluster.
+func (cluster *FoundationDBCluster) UsesCanaryUpgrades() bool {
+	return len(cluster.Spec.CanaryUpgrade.ProcessClasses) > 0 || cluster.Spec.CanaryUpgrade.PodSelector != nil
+}
+
+// GetCanaryMaxProcesses gets the maximum number of processes that the
+// operator will upgrade as canaries.
+func (cluster *FoundationDBCluster) GetCanaryMaxProcesses() int {
+	maxProcesses := cluster.Spec.CanaryUpgrade.MaxProcesses
+	if maxProcesses == nil {
+		return 1
+	}
+	return *maxProcesses
+}
+
+// GetCanarySoakPeriodSeconds gets the time that the cluster must stay
+// healthy with the canary processes upgraded before the operator upgrades
+// the rest of the processes.
+func (cluster *FoundationDBCluster) GetCanarySoakPeriodSeconds() int {
+	soakPeriod := cluster.Spec.CanaryUpgrade.SoakPeriodSeconds
+	if soakPeriod == nil {
+		return 600
+	}
+	return *soakPeriod
+}
+
+// ShouldManagePodDisruptionBudgets determines whether the operator should
+// manage pod disruption budgets for the cluster.
+func (cluster *FoundationDBCluster) ShouldManagePodDisruptionBudgets() bool {
+	enabled := cluster.Spec.PodDisruptionBudgets.Enabled
+	return enabled != nil && *enabled
+}
+
+// ShouldPreserveHealthyCoordinators determines whether the operator should
+// keep the healthy coordinators when it changes coordinators.
+func (cluster *FoundationDBCluster) ShouldPreserveHealthyCoordinators() bool {
+	preserve := cluster.Spec.CoordinatorSelection.PreserveHealthyCoordinators
+	return preserve != nil && *preserve
+}
+
+// ShouldAutoscaleStorage determines whether we should change the storage
+// process count based on disk utilization.
+func (cluster *FoundationDBCluster) ShouldAutoscaleStorage() bool {
+	enabled := cluster.Spec.Autoscaling.Enabled
+	return enabled != nil && *enabled
+}
+
+// GetTargetDiskUtilizationPercent gets the percentage of the disk space on
+// the storage processes that autoscaling aims to have in use.
+func (cluster *FoundationDBCluster) GetTargetDiskUtilizationPercent() int {
+	target := cluster.Spec.Autoscaling.TargetDiskUtilizationPercent
+	if target == nil {
+		return 60
+	}
+	return *target
+}
+
+// GetAutoscalingTolerancePercent gets how far the disk utilization can drift
+// from the target before autoscaling changes the storage process count.
+func (cluster *FoundationDBCluster) GetAutoscalingTolerancePercent() int {
+	tolerance := cluster.Spec.Autoscaling.TolerancePercent
+	if tolerance == nil {
+		return 10
+	}
+	return *tolerance
+}
+
+// GetAutoscalingCooldownSeconds gets the minimum time between changes to the
+// storage process count from autoscaling.
+func (cluster *FoundationDBCluster) GetAutoscalingCooldownSeconds() int {
+	cooldown := cluster.Spec.Autoscaling.CooldownSeconds
+	if cooldown == nil {
+		return 1800
+	}
+	return *cooldown
+}
+
+// ShouldReplaceMissingProcesses determines whether we should automatically
+// replace processes that have stopped reporting to the database.
+func (cluster *FoundationDBCluster) ShouldReplaceMissingProcesses() bool {
+	enabled := cluster.Spec.AutomationOptions.Replacements.Enabled
+	return enabled != nil && *enabled
+}
+
+// GetFailureDetectionTimeSeconds gets how long a process must be missing
+// before we automatically replace it.
+func (cluster *FoundationDBCluster) GetFailureDetectionTimeSeconds() int {
+	detectionTime := cluster.Spec.AutomationOptions.Replacements.FailureDetectionTimeSeconds
+	if detectionTime == nil {
+		return 1800
+	}
+	return *detectionTime
+}
+
+// GetMaxConcurrentReplacements gets how many processes can be pending
+// removal before we stop starting new automatic replacements.
+func (cluster *FoundationDBCluster) GetMaxConcurrentReplacements() int {
+	maxReplacements := cluster.Spec.AutomationOptions.Replacements.MaxConcurrentReplacements
+	if maxReplacements == nil {
+		return 1
+	}
+	return *maxReplacements
+}
+
 // GetLockPrefix gets the prefix for the keys where we store locking
 // information.
 func (cluster *FoundationDBCluster) GetLockPrefix() string {
@@ -1952,6 +2695,185 @@ type ServiceConfig struct {
 	// Headless determines whether we want to run a headless service for the
 	// cluster.
 	Headless *bool `json:"headless,omitempty"`
+
+	// PublicIPSource specifies where the processes in the cluster should get
+	// their public IPs from. This supports the values `pod` and `service`.
+	//
+	// When this is `service`, the operator creates a ClusterIP service for
+	// each instance, and the processes use the service IP as their public
+	// IP. This allows the processes to keep the same address when their pods
+	// are recreated.
+	//
+	// The default is `pod`.
+	PublicIPSource PublicIPSource `json:"publicIPSource,omitempty"`
+}
+
+// BounceStrategyConfig allows configuring how the operator restarts
+// processes when their configuration changes.
+type BounceStrategyConfig struct {
+	// Type defines the strategy for choosing which processes to bounce
+	// together.
+	//
+	// When this is `all`, the operator bounces all of the processes that need
+	// to be restarted at once. When this is `faultDomain`, the operator
+	// bounces the processes one fault domain at a time, and waits for the
+	// processes to rejoin the cluster and for the cluster to be healthy
+	// before moving on to the next fault domain.
+	//
+	// The default is `all`.
+	Type BounceStrategyType `json:"type,omitempty"`
+
+	// MaxProcessesPerBatch defines the maximum number of processes that the
+	// operator will bounce at once. When the operator has to split a bounce
+	// into multiple batches, it waits for the processes in each batch to
+	// rejoin the cluster and for the cluster to be healthy before bouncing
+	// the next batch.
+	//
+	// If this is 0, there is no limit.
+	MaxProcessesPerBatch int `json:"maxProcessesPerBatch,omitempty"`
+}
+
+// BounceStrategyType describes how the operator chooses which processes to
+// bounce together.
+type BounceStrategyType string
+
+const (
+	// BounceStrategyAll bounces all of the processes at once.
+	BounceStrategyAll BounceStrategyType = "all"
+
+	// BounceStrategyFaultDomain bounces the processes one fault domain at a
+	// time.
+	BounceStrategyFaultDomain BounceStrategyType = "faultDomain"
+)
+
+// CanaryUpgradeConfig allows configuring a set of processes that the
+// operator upgrades first.
+//
+// Canaries are only used for upgrades between protocol-compatible versions,
+// since processes in a cluster cannot run incompatible versions at the same
+// time. Other upgrades will bounce all of the processes at once.
+type CanaryUpgradeConfig struct {
+	// ProcessClasses defines the process classes that the canary processes
+	// can be chosen from.
+	//
+	// If this is empty, processes from any class can be chosen.
+	ProcessClasses []string `json:"processClasses,omitempty"`
+
+	// PodSelector defines a selector for the pods whose processes can be
+	// chosen as canaries.
+	//
+	// If this is empty, processes from any pod can be chosen.
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// MaxProcesses defines the maximum number of processes that the operator
+	// will upgrade as canaries. The default is 1.
+	MaxProcesses *int `json:"maxProcesses,omitempty"`
+
+	// SoakPeriodSeconds defines how long the cluster must stay healthy with
+	// the canary processes running the new version before the operator
+	// upgrades the rest of the processes. The default is 600.
+	SoakPeriodSeconds *int `json:"soakPeriodSeconds,omitempty"`
+}
+
+// PublicIPSource describes where a process gets its public IP from.
+type PublicIPSource string
+
+const (
+	// PublicIPSourcePod uses the IP of the pod as the public IP.
+	PublicIPSourcePod PublicIPSource = "pod"
+
+	// PublicIPSourceService uses the IP of a service for the instance as the
+	// public IP.
+	PublicIPSourceService PublicIPSource = "service"
+)
+
+// CoordinatorSelectionConfig allows configuring how the operator chooses the
+// processes that serve as coordinators.
+type CoordinatorSelectionConfig struct {
+	// ProcessClasses defines the process classes that can serve as
+	// coordinators, along with their priorities. Processes in classes with a
+	// higher priority will be chosen before processes in classes with a lower
+	// priority.
+	//
+	// If this is empty, all of the stateful process classes will be
+	// eligible, with equal priority.
+	ProcessClasses []CoordinatorSelectionSetting `json:"processClasses,omitempty"`
+
+	// LocalityFields defines the locality fields that the coordinators should
+	// be spread across, in addition to the zone ID and the data center ID.
+	// The operator will never choose more than one coordinator in the same
+	// zone.
+	LocalityFields []string `json:"localityFields,omitempty"`
+
+	// ExcludedPodSelector defines a selector for pods whose processes should
+	// never serve as coordinators.
+	ExcludedPodSelector *metav1.LabelSelector `json:"excludedPodSelector,omitempty"`
+
+	// PreserveHealthyCoordinators determines whether the operator should keep
+	// the current coordinators that are still healthy and eligible when it
+	// changes coordinators, rather than choosing a new set of coordinators
+	// from scratch.
+	PreserveHealthyCoordinators *bool `json:"preserveHealthyCoordinators,omitempty"`
+}
+
+// CoordinatorSelectionSetting defines the priority of a process class for
+// serving as coordinators.
+type CoordinatorSelectionSetting struct {
+	// ProcessClass defines the process class.
+	ProcessClass string `json:"processClass"`
+
+	// Priority defines the priority of the process class. Higher values are
+	// chosen first.
+	Priority int `json:"priority,omitempty"`
+}
+
+// PodDisruptionBudgetConfig allows configuring the pod disruption budget
+// that the operator manages for the cluster.
+type PodDisruptionBudgetConfig struct {
+	// Enabled determines whether the operator should manage a pod disruption
+	// budget for the cluster.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MaxUnavailable provides the number of pods in the cluster, across all
+	// process classes, that can be voluntarily disrupted at once. If this is
+	// not set, it will be derived from the desired fault tolerance and the
+	// fault domain configuration.
+	MaxUnavailable *int `json:"maxUnavailable,omitempty"`
+}
+
+// AutoscalingConfig provides settings for automatically scaling the storage
+// processes based on disk utilization.
+type AutoscalingConfig struct {
+	// Enabled determines whether the operator should change the storage
+	// process count based on disk utilization.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// MinStorageProcesses provides the smallest number of storage processes
+	// the operator will scale down to.
+	MinStorageProcesses int `json:"minStorageProcesses,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// MaxStorageProcesses provides the largest number of storage processes
+	// the operator will scale up to.
+	MaxStorageProcesses int `json:"maxStorageProcesses,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// TargetDiskUtilizationPercent provides the percentage of the disk space
+	// on the storage processes that we want to be in use. The default is 60.
+	TargetDiskUtilizationPercent *int `json:"targetDiskUtilizationPercent,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// TolerancePercent provides how far the disk utilization can drift from
+	// the target, in percentage points, before we change the storage process
+	// count. The default is 10.
+	TolerancePercent *int `json:"tolerancePercent,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// CooldownSeconds provides the minimum time between changes to the
+	// storage process count. The default is 1800.
+	CooldownSeconds *int `json:"cooldownSeconds,omitempty"`
 }
 
 // RequiredAddressSet provides settings for which addresses we need to listen
@@ -2087,3 +3009,102 @@ func (version FdbVersion) HasSidecarCrashOnEmpty() bool {
 func (version FdbVersion) HasNonBlockingExcludes() bool {
 	return version.IsAtLeast(FdbVersion{Major: 6, Minor: 3, Patch: 5})
 }
+
+// SupportsLocalityBasedExclusions determines if a version has support for
+// excluding processes by locality, such as
+// `exclude locality_instance_id:storage-1`.
+func (version FdbVersion) SupportsLocalityBasedExclusions() bool {
+	return version.IsAtLeast(FdbVersion{Major: 6, Minor: 3, Patch: 0})
+}
+
+// FdbVersionSeries represents a release series of FoundationDB, which covers
+// all of the patch releases for a major and minor version.
+type FdbVersionSeries struct {
+	// Major provides the major version.
+	Major int
+
+	// Minor provides the minor version.
+	Minor int
+
+	// MinimumPatch provides the earliest patch release in the series that the
+	// operator supports.
+	MinimumPatch int
+}
+
+// SupportedVersionSeries provides the release series of FoundationDB that the
+// operator supports, in the order that clusters must be upgraded through
+// them.
+//
+// A cluster can be upgraded to any version in its current series, or to a
+// version in the next series in this list. Upgrading any further requires
+// going through the series in between.
+var SupportedVersionSeries = []FdbVersionSeries{
+	{Major: 6, Minor: 1, MinimumPatch: 12},
+	{Major: 6, Minor: 2},
+	{Major: 6, Minor: 3},
+	{Major: 7, Minor: 0},
+}
+
+// String gets the string representation of a release series.
+func (series FdbVersionSeries) String() string {
+	return fmt.Sprintf("%d.%d", series.Major, series.Minor)
+}
+
+// Contains determines whether a version is a supported patch release in this
+// series.
+func (series FdbVersionSeries) Contains(version FdbVersion) bool {
+	return version.Major == series.Major && version.Minor == series.Minor && version.Patch >= series.MinimumPatch
+}
+
+// getSupportedSeriesIndex gets the index of the release series for a version
+// in SupportedVersionSeries.
+//
+// This will return -1 if the version is not supported.
+func getSupportedSeriesIndex(version FdbVersion) int {
+	for index, series := range SupportedVersionSeries {
+		if series.Contains(version) {
+			return index
+		}
+	}
+	return -1
+}
+
+// IsSupported determines whether the operator supports running clusters on
+// a version.
+func (version FdbVersion) IsSupported() bool {
+	return getSupportedSeriesIndex(version) >= 0
+}
+
+// GetSupportedVersionDescription gets a description of the versions that the
+// operator supports, for use in error messages.
+func GetSupportedVersionDescription() string {
+	descriptions := make([]string, len(SupportedVersionSeries))
+	for index, series := range SupportedVersionSeries {
+		descriptions[index] = fmt.Sprintf("%d.%d.%d+", series.Major, series.Minor, series.MinimumPatch)
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// CheckUpgradePath checks whether a cluster running one version can be
+// upgraded directly to another version.
+//
+// If the upgrade skips any of the supported release series, this will return
+// an error naming the series that the cluster must be upgraded through first.
+// Downgrades are not checked here.
+func CheckUpgradePath(current FdbVersion, target FdbVersion) error {
+	targetIndex := getSupportedSeriesIndex(target)
+	if targetIndex < 0 {
+		return fmt.Errorf("version %s is not supported, the supported versions are %s", target, GetSupportedVersionDescription())
+	}
+
+	currentIndex := getSupportedSeriesIndex(current)
+	if currentIndex < 0 || targetIndex <= currentIndex+1 {
+		return nil
+	}
+
+	intermediateSeries := make([]string, 0, targetIndex-currentIndex-1)
+	for _, series := range SupportedVersionSeries[currentIndex+1 : targetIndex] {
+		intermediateSeries = append(intermediateSeries, series.String())
+	}
+	return fmt.Errorf("cannot upgrade directly from version %s to %s, upgrade through these versions in order first: %s", current, target, strings.Join(intermediateSeries, ", "))
+}
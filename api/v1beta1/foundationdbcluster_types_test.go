@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/onsi/gomega"
 
@@ -498,6 +499,8 @@ func TestParsingClusterStatusWithSixOneCluster(t *testing.T) {
 					},
 					Version:       "6.1.12",
 					UptimeSeconds: 160.009,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7177306112, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "proxy"}, {Role: "storage"}},
 				},
 				"f9efa90fc104f4e277b140baf89aab66": {
 					Address:      "10.1.38.82:4501",
@@ -512,6 +515,8 @@ func TestParsingClusterStatusWithSixOneCluster(t *testing.T) {
 					},
 					Version:       "6.1.12",
 					UptimeSeconds: 160.008,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7177306112, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "cluster_controller"}, {Role: "ratekeeper"}, {Role: "storage"}},
 				},
 				"5a633d7f4e98a6c938c84b97ec4aedbf": {
 					Address:      "10.1.38.89:4501",
@@ -526,6 +531,8 @@ func TestParsingClusterStatusWithSixOneCluster(t *testing.T) {
 					},
 					Version:       "6.1.12",
 					UptimeSeconds: 160.009,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7177306112, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "log"}},
 				},
 				"5c1b68147a0ef34ce005a38245851270": {
 					Address:      "10.1.38.88:4501",
@@ -540,6 +547,8 @@ func TestParsingClusterStatusWithSixOneCluster(t *testing.T) {
 					},
 					Version:       "6.1.12",
 					UptimeSeconds: 160.008,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7177306112, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "proxy"}},
 				},
 				"653defde43cf1fdef131e2fb82bd192d": {
 					Address:      "10.1.38.87:4501",
@@ -554,6 +563,8 @@ func TestParsingClusterStatusWithSixOneCluster(t *testing.T) {
 					},
 					Version:       "6.1.12",
 					UptimeSeconds: 160.01,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7177306112, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "log"}},
 				},
 				"9c93d3b70118f16c72f7cb3f53e49f4c": {
 					Address:      "10.1.38.86:4501",
@@ -568,6 +579,8 @@ func TestParsingClusterStatusWithSixOneCluster(t *testing.T) {
 					},
 					Version:       "6.1.12",
 					UptimeSeconds: 160.008,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7177306112, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "storage"}, {Role: "resolver"}},
 				},
 				"b9c25278c0fa207bc2a73bda2300d0a9": {
 					Address:      "10.1.38.90:4501",
@@ -582,6 +595,8 @@ func TestParsingClusterStatusWithSixOneCluster(t *testing.T) {
 					},
 					Version:       "6.1.12",
 					UptimeSeconds: 160.01,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7177306112, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "master"}, {Role: "data_distributor"}, {Role: "log"}},
 				},
 			},
 			Data: FoundationDBStatusDataStatistics{
@@ -671,6 +686,39 @@ func TestParsingClusterStatusWithSixOneCluster(t *testing.T) {
 	}))
 }
 
+func TestParsingClusterStatusWithIPv6Addresses(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	statusFile, err := os.OpenFile(filepath.Join("testdata", "fdb_status_6_2_ipv6.json"), os.O_RDONLY, os.ModePerm)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer statusFile.Close()
+	statusDecoder := json.NewDecoder(statusFile)
+	status := FoundationDBStatus{}
+	err = statusDecoder.Decode(&status)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(status.Client.Coordinators.Coordinators).To(gomega.Equal([]FoundationDBStatusCoordinator{
+		{Address: "[fd00::10:1:38:94]:4501", Reachable: true},
+		{Address: "[fd00::10:1:38:102]:4501", Reachable: true},
+		{Address: "[fd00::10:1:38:104]:4501", Reachable: true},
+	}))
+
+	coordinator, err := ParseProcessAddress(status.Client.Coordinators.Coordinators[0].Address)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(coordinator).To(gomega.Equal(ProcessAddress{IPAddress: "fd00::10:1:38:94", Port: 4501}))
+
+	process := status.Cluster.Processes["b9c25278c0fa207bc2a73bda2300d0a9"]
+	g.Expect(process.Address).To(gomega.Equal("[fd00::10:1:38:93]:4501"))
+	g.Expect(process.Locality["instance_id"]).To(gomega.Equal("log-3"))
+
+	g.Expect(len(status.Cluster.Processes)).To(gomega.Equal(7))
+	for _, process := range status.Cluster.Processes {
+		address, err := ParseProcessAddress(process.Address)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(address.Port).To(gomega.Equal(4501))
+		g.Expect(address.String()).To(gomega.Equal(process.Address))
+	}
+}
+
 func TestParsingClusterStatusWithSixTwoCluster(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	statusFile, err := os.OpenFile(filepath.Join("testdata", "fdb_status_6_2.json"), os.O_RDONLY, os.ModePerm)
@@ -723,6 +771,8 @@ func TestParsingClusterStatusWithSixTwoCluster(t *testing.T) {
 					},
 					Version:       "6.2.15",
 					UptimeSeconds: 2955.58,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7176683520, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "log"}},
 				},
 				"c813e585043a7ab55a4905f465c4aa52": {
 					Address:      "10.1.38.95:4501",
@@ -737,6 +787,8 @@ func TestParsingClusterStatusWithSixTwoCluster(t *testing.T) {
 					},
 					Version:       "6.2.15",
 					UptimeSeconds: 2475.33,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7176683520, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "proxy"}, {Role: "storage"}},
 				},
 				"f9efa90fc104f4e277b140baf89aab66": {
 					Address:      "10.1.38.92:4501",
@@ -751,6 +803,8 @@ func TestParsingClusterStatusWithSixTwoCluster(t *testing.T) {
 					},
 					Version:       "6.2.15",
 					UptimeSeconds: 2951.17,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7176683520, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "proxy"}, {Role: "storage"}},
 				},
 				"5a633d7f4e98a6c938c84b97ec4aedbf": {
 					Address:      "10.1.38.105:4501",
@@ -765,6 +819,8 @@ func TestParsingClusterStatusWithSixTwoCluster(t *testing.T) {
 					},
 					Version:       "6.2.15",
 					UptimeSeconds: 710.119,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7176683520, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "cluster_controller"}, {Role: "log"}},
 				},
 				"5c1b68147a0ef34ce005a38245851270": {
 					Address:      "10.1.38.102:4501",
@@ -779,6 +835,8 @@ func TestParsingClusterStatusWithSixTwoCluster(t *testing.T) {
 					},
 					Version:       "6.2.15",
 					UptimeSeconds: 1095.18,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7176683520, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "coordinator"}, {Role: "resolver"}},
 				},
 				"653defde43cf1fdef131e2fb82bd192d": {
 					Address:      "10.1.38.104:4501",
@@ -793,6 +851,8 @@ func TestParsingClusterStatusWithSixTwoCluster(t *testing.T) {
 					},
 					Version:       "6.2.15",
 					UptimeSeconds: 880.18,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7176683520, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "master"}, {Role: "data_distributor"}, {Role: "ratekeeper"}, {Role: "coordinator"}, {Role: "log"}},
 				},
 				"9c93d3b70118f16c72f7cb3f53e49f4c": {
 					Address:      "10.1.38.94:4501",
@@ -807,6 +867,8 @@ func TestParsingClusterStatusWithSixTwoCluster(t *testing.T) {
 					},
 					Version:       "6.2.15",
 					UptimeSeconds: 2650.5,
+					Disk:          FoundationDBStatusProcessDiskInfo{FreeBytes: 7176683520, TotalBytes: 8396963840},
+					Roles:         []FoundationDBStatusProcessRoleInfo{{Role: "coordinator"}, {Role: "proxy"}, {Role: "storage"}},
 				},
 			},
 			Data: FoundationDBStatusDataStatistics{
@@ -814,6 +876,10 @@ func TestParsingClusterStatusWithSixTwoCluster(t *testing.T) {
 				MovingData: FoundationDBStatusMovingData{HighestPriority: 0, InFlightBytes: 0, InQueueBytes: 0},
 			},
 			FullReplication: true,
+			FaultTolerance: FoundationDBStatusFaultTolerance{
+				MaxZoneFailuresWithoutLosingData:         1,
+				MaxZoneFailuresWithoutLosingAvailability: 1,
+			},
 			Clients: FoundationDBStatusClusterClientInfo{
 				Count: 8,
 				SupportedVersions: []FoundationDBStatusSupportedVersion{
@@ -1191,10 +1257,18 @@ func TestFeatureFlagsForFdbVersion(t *testing.T) {
 	version := FdbVersion{Major: 6, Minor: 2, Patch: 0}
 	g.Expect(version.HasInstanceIDInSidecarSubstitutions()).To(gomega.BeFalse())
 	g.Expect(version.PrefersCommandLineArgumentsInSidecar()).To(gomega.BeFalse())
+	g.Expect(version.SupportsLocalityBasedExclusions()).To(gomega.BeFalse())
+
+	version = FdbVersion{Major: 6, Minor: 2, Patch: 20}
+	g.Expect(version.SupportsLocalityBasedExclusions()).To(gomega.BeFalse())
+
+	version = FdbVersion{Major: 6, Minor: 3, Patch: 0}
+	g.Expect(version.SupportsLocalityBasedExclusions()).To(gomega.BeTrue())
 
 	version = FdbVersion{Major: 7, Minor: 0, Patch: 0}
 	g.Expect(version.HasInstanceIDInSidecarSubstitutions()).To(gomega.BeTrue())
 	g.Expect(version.PrefersCommandLineArgumentsInSidecar()).To(gomega.BeTrue())
+	g.Expect(version.SupportsLocalityBasedExclusions()).To(gomega.BeTrue())
 }
 
 func TestGetNextConfigurationChangeWithSimpleChange(t *testing.T) {
@@ -2789,6 +2863,63 @@ func TestParseProcessAddress(t *testing.T) {
 	address, err = ParseProcessAddress("127.0.0.1:bad")
 	g.Expect(err).To(gomega.HaveOccurred())
 	g.Expect(err.Error()).To(gomega.Equal("strconv.Atoi: parsing \"bad\": invalid syntax"))
+
+	address, err = ParseProcessAddress("[fd00::1]:4500:tls")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(address).To(gomega.Equal(ProcessAddress{
+		IPAddress: "fd00::1",
+		Port:      4500,
+		Flags:     map[string]bool{"tls": true},
+	}))
+	g.Expect(address.String()).To(gomega.Equal("[fd00::1]:4500:tls"))
+
+	address, err = ParseProcessAddress("[fd00:0:0:0:0:0:0:2]:4501")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(address).To(gomega.Equal(ProcessAddress{
+		IPAddress: "fd00::2",
+		Port:      4501,
+	}))
+	g.Expect(address.String()).To(gomega.Equal("[fd00::2]:4501"))
+
+	_, err = ParseProcessAddress("fd00::1:4501")
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal("Invalid address: fd00::1:4501, IPv6 addresses must be wrapped in brackets"))
+
+	_, err = ParseProcessAddress("[fd00::1:4501")
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal("Invalid address: [fd00::1:4501"))
+
+	_, err = ParseProcessAddress("[bad]:4501")
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal("Invalid address: [bad]:4501"))
+}
+
+func TestGettingFullAddressList(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := &FoundationDBCluster{
+		Status: FoundationDBClusterStatus{
+			RequiredAddresses: RequiredAddressSet{
+				TLS:    true,
+				NonTLS: true,
+			},
+		},
+	}
+
+	g.Expect(cluster.GetFullAddressList("1.1.1.1", false)).To(gomega.Equal("1.1.1.1:4501,1.1.1.1:4500:tls"))
+	g.Expect(cluster.GetFullAddress("1.1.1.1")).To(gomega.Equal("1.1.1.1:4501"))
+	g.Expect(cluster.GetFullAddressList("fd00::1", false)).To(gomega.Equal("[fd00::1]:4501,[fd00::1]:4500:tls"))
+	g.Expect(cluster.GetFullAddress("fd00::1")).To(gomega.Equal("[fd00::1]:4501"))
+	g.Expect(cluster.GetFullAddress("$FDB_PUBLIC_IP")).To(gomega.Equal("$FDB_PUBLIC_IP:4501"))
+
+	cluster.Spec.MainContainer.EnableTLS = true
+	g.Expect(cluster.GetFullAddress("fd00::1")).To(gomega.Equal("[fd00::1]:4500:tls"))
+
+	ipFamily := 6
+	cluster.Spec.PodIPFamily = &ipFamily
+	g.Expect(cluster.GetFullAddress("$FDB_PUBLIC_IP")).To(gomega.Equal("[$FDB_PUBLIC_IP]:4500:tls"))
+	g.Expect(cluster.GetFullAddress("fd00::1")).To(gomega.Equal("[fd00::1]:4500:tls"))
+	g.Expect(cluster.GetFullAddress("1.1.1.1")).To(gomega.Equal("1.1.1.1:4500:tls"))
 }
 
 func TestInstanceIsBeingRemoved(t *testing.T) {
@@ -3063,6 +3194,108 @@ func TestCheckingReconciliationForCluster(t *testing.T) {
 	}))
 }
 
+func TestUpdatingConditionsForCluster(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := &FoundationDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "sample-cluster",
+			Namespace:  "default",
+			Generation: 2,
+		},
+		Spec: FoundationDBClusterSpec{
+			Version: Versions.Default.String(),
+		},
+		Status: FoundationDBClusterStatus{
+			Health: ClusterHealth{
+				Available:       true,
+				Healthy:         true,
+				FullReplication: true,
+			},
+			RequiredAddresses: RequiredAddressSet{
+				NonTLS: true,
+			},
+			DatabaseConfiguration: DatabaseConfiguration{
+				RedundancyMode: "double",
+				StorageEngine:  "ssd-2",
+				UsableRegions:  1,
+				RoleCounts: RoleCounts{
+					Logs:       3,
+					Proxies:    3,
+					Resolvers:  1,
+					LogRouters: -1,
+					RemoteLogs: -1,
+				},
+			},
+			ProcessCounts: ProcessCounts{
+				Storage:   3,
+				Stateless: 9,
+				Log:       4,
+			},
+			RunningVersion: Versions.Default.String(),
+			Configured:     true,
+		},
+	}
+
+	getStatuses := func() map[ClusterConditionType]corev1.ConditionStatus {
+		statuses := make(map[ClusterConditionType]corev1.ConditionStatus)
+		for _, condition := range cluster.Status.Conditions {
+			statuses[condition.Type] = condition.Status
+		}
+		return statuses
+	}
+
+	result, err := cluster.CheckReconciliation()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(result).To(gomega.BeTrue())
+	g.Expect(getStatuses()).To(gomega.Equal(map[ClusterConditionType]corev1.ConditionStatus{
+		ClusterConditionAvailable:         corev1.ConditionTrue,
+		ClusterConditionFullyReplicated:   corev1.ConditionTrue,
+		ClusterConditionReconciled:        corev1.ConditionTrue,
+		ClusterConditionUpgradeInProgress: corev1.ConditionFalse,
+		ClusterConditionCoordinatorsValid: corev1.ConditionTrue,
+		ClusterConditionPodsFailing:       corev1.ConditionFalse,
+	}))
+	g.Expect(cluster.Status.GetCondition(ClusterConditionReconciled).Reason).To(gomega.Equal("Reconciled"))
+
+	transitionTime := metav1.NewTime(time.Now().Add(-1 * time.Hour))
+	for index := range cluster.Status.Conditions {
+		cluster.Status.Conditions[index].LastTransitionTime = transitionTime
+	}
+
+	cluster.Spec.Version = Versions.NextMajorVersion.String()
+	cluster.Status.FailingPods = []string{"storage-1"}
+	cluster.Status.NeedsNewCoordinators = true
+	result, err = cluster.CheckReconciliation()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(result).To(gomega.BeFalse())
+	g.Expect(getStatuses()).To(gomega.Equal(map[ClusterConditionType]corev1.ConditionStatus{
+		ClusterConditionAvailable:         corev1.ConditionTrue,
+		ClusterConditionFullyReplicated:   corev1.ConditionTrue,
+		ClusterConditionReconciled:        corev1.ConditionFalse,
+		ClusterConditionUpgradeInProgress: corev1.ConditionTrue,
+		ClusterConditionCoordinatorsValid: corev1.ConditionFalse,
+		ClusterConditionPodsFailing:       corev1.ConditionTrue,
+	}))
+
+	condition := cluster.Status.GetCondition(ClusterConditionReconciled)
+	g.Expect(condition.Reason).To(gomega.Equal("ReconciliationPending"))
+	g.Expect(condition.Message).To(gomega.Equal("Reconciliation is pending on: NeedsCoordinatorChange, HasFailingPods"))
+	g.Expect(condition.LastTransitionTime).NotTo(gomega.Equal(transitionTime))
+
+	condition = cluster.Status.GetCondition(ClusterConditionPodsFailing)
+	g.Expect(condition.Message).To(gomega.Equal("Pods are failing to start: storage-1"))
+
+	condition = cluster.Status.GetCondition(ClusterConditionAvailable)
+	g.Expect(condition.LastTransitionTime).To(gomega.Equal(transitionTime))
+
+	cluster.Status.Configured = false
+	result, err = cluster.CheckReconciliation()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(result).To(gomega.BeFalse())
+	g.Expect(cluster.Status.GetCondition(ClusterConditionReconciled).Reason).To(gomega.Equal("DatabaseNotConfigured"))
+}
+
 func TestGettingProcessSettings(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
@@ -3110,6 +3343,63 @@ func TestVersionsAreProtocolCompatible(t *testing.T) {
 	g.Expect(version.IsProtocolCompatible(FdbVersion{Major: 7, Minor: 2, Patch: 20})).To(gomega.BeFalse())
 }
 
+func TestCheckingSupportedVersions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(FdbVersion{Major: 6, Minor: 1, Patch: 12}.IsSupported()).To(gomega.BeTrue())
+	g.Expect(FdbVersion{Major: 6, Minor: 1, Patch: 8}.IsSupported()).To(gomega.BeFalse())
+	g.Expect(FdbVersion{Major: 6, Minor: 2, Patch: 20}.IsSupported()).To(gomega.BeTrue())
+	g.Expect(FdbVersion{Major: 7, Minor: 0, Patch: 0}.IsSupported()).To(gomega.BeTrue())
+	g.Expect(FdbVersion{Major: 5, Minor: 2, Patch: 21}.IsSupported()).To(gomega.BeFalse())
+	g.Expect(FdbVersion{Major: 8, Minor: 0, Patch: 0}.IsSupported()).To(gomega.BeFalse())
+
+	g.Expect(GetSupportedVersionDescription()).To(gomega.Equal("6.1.12+, 6.2.0+, 6.3.0+, 7.0.0+"))
+}
+
+func TestCheckingUpgradePaths(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	current := FdbVersion{Major: 6, Minor: 2, Patch: 20}
+	g.Expect(CheckUpgradePath(current, FdbVersion{Major: 6, Minor: 2, Patch: 22})).To(gomega.Succeed())
+	g.Expect(CheckUpgradePath(current, FdbVersion{Major: 6, Minor: 3, Patch: 9})).To(gomega.Succeed())
+	g.Expect(CheckUpgradePath(current, FdbVersion{Major: 6, Minor: 1, Patch: 12})).To(gomega.Succeed())
+
+	err := CheckUpgradePath(current, FdbVersion{Major: 7, Minor: 0, Patch: 0})
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal("cannot upgrade directly from version 6.2.20 to 7.0.0, upgrade through these versions in order first: 6.3"))
+
+	err = CheckUpgradePath(FdbVersion{Major: 6, Minor: 1, Patch: 12}, FdbVersion{Major: 7, Minor: 0, Patch: 0})
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal("cannot upgrade directly from version 6.1.12 to 7.0.0, upgrade through these versions in order first: 6.2, 6.3"))
+
+	err = CheckUpgradePath(current, FdbVersion{Major: 6, Minor: 1, Patch: 8})
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal("version 6.1.8 is not supported, the supported versions are 6.1.12+, 6.2.0+, 6.3.0+, 7.0.0+"))
+}
+
+func TestGettingCanaryUpgradeSettings(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := &FoundationDBCluster{}
+	g.Expect(cluster.UsesCanaryUpgrades()).To(gomega.BeFalse())
+	g.Expect(cluster.GetCanaryMaxProcesses()).To(gomega.Equal(1))
+	g.Expect(cluster.GetCanarySoakPeriodSeconds()).To(gomega.Equal(600))
+
+	cluster.Spec.CanaryUpgrade.ProcessClasses = []string{"stateless"}
+	g.Expect(cluster.UsesCanaryUpgrades()).To(gomega.BeTrue())
+
+	cluster.Spec.CanaryUpgrade.ProcessClasses = nil
+	cluster.Spec.CanaryUpgrade.PodSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}
+	g.Expect(cluster.UsesCanaryUpgrades()).To(gomega.BeTrue())
+
+	maxProcesses := 3
+	soakPeriod := 0
+	cluster.Spec.CanaryUpgrade.MaxProcesses = &maxProcesses
+	cluster.Spec.CanaryUpgrade.SoakPeriodSeconds = &soakPeriod
+	g.Expect(cluster.GetCanaryMaxProcesses()).To(gomega.Equal(3))
+	g.Expect(cluster.GetCanarySoakPeriodSeconds()).To(gomega.Equal(0))
+}
+
 func TestGettingLockOptions(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
@@ -3128,3 +3418,109 @@ func TestGettingLockOptions(t *testing.T) {
 	cluster.Spec.LockOptions.LockKeyPrefix = "\xfe/locks"
 	g.Expect(cluster.GetLockPrefix()).To(gomega.Equal("\xfe/locks"))
 }
+
+func TestShouldUseNativeAdminClient(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := &FoundationDBCluster{}
+	g.Expect(cluster.ShouldUseNativeAdminClient(false)).To(gomega.BeFalse())
+	g.Expect(cluster.ShouldUseNativeAdminClient(true)).To(gomega.BeTrue())
+
+	var enabled = true
+	cluster.Spec.UseNativeAdminClient = &enabled
+	g.Expect(cluster.ShouldUseNativeAdminClient(false)).To(gomega.BeTrue())
+
+	enabled = false
+	g.Expect(cluster.ShouldUseNativeAdminClient(true)).To(gomega.BeFalse())
+}
+
+func TestUpdatingProcessGroupConditions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	processGroup := &ProcessGroupStatus{InstanceID: "storage-1", ProcessClass: "storage"}
+	g.Expect(processGroup.HasCondition(MissingProcess)).To(gomega.BeFalse())
+	g.Expect(processGroup.GetConditionTime(MissingProcess)).To(gomega.BeNil())
+
+	processGroup.UpdateCondition(MissingProcess, true)
+	g.Expect(processGroup.HasCondition(MissingProcess)).To(gomega.BeTrue())
+
+	processGroup.Conditions[0].Timestamp = 100
+	processGroup.UpdateCondition(MissingProcess, true)
+	processGroup.UpdateCondition(PodFailing, true)
+	g.Expect(*processGroup.GetConditionTime(MissingProcess)).To(gomega.Equal(int64(100)))
+	g.Expect(processGroup.HasCondition(PodFailing)).To(gomega.BeTrue())
+
+	processGroup.UpdateCondition(MissingProcess, false)
+	g.Expect(processGroup.HasCondition(MissingProcess)).To(gomega.BeFalse())
+	g.Expect(processGroup.HasCondition(PodFailing)).To(gomega.BeTrue())
+
+	processGroup.UpdateCondition(PodFailing, false)
+	g.Expect(processGroup.Conditions).To(gomega.BeNil())
+}
+
+func TestUpdatingProcessGroupRemovals(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	status := &FoundationDBClusterStatus{
+		ProcessGroups: []ProcessGroupStatus{
+			{InstanceID: "storage-1", ProcessClass: "storage", Addresses: []string{"1.1.1.1:4501"}},
+			{InstanceID: "storage-2", ProcessClass: "storage", Addresses: []string{"1.1.1.2:4501"}},
+			{InstanceID: "storage-3", ProcessClass: "storage", Conditions: []ProcessGroupCondition{
+				{Type: MarkedForRemoval, Timestamp: 100},
+			}},
+		},
+		PendingRemovals: map[string]PendingRemovalState{
+			"storage-1": {PodName: "sample-cluster-storage-1", Address: "1.1.1.1"},
+			"storage-2": {PodName: "sample-cluster-storage-2", Address: "1.1.1.2", ExclusionStarted: true, ExclusionComplete: true},
+			"log-1":     {PodName: "sample-cluster-log-1", Address: "1.1.1.3"},
+		},
+	}
+
+	status.UpdateProcessGroupRemovals()
+
+	ids := make([]string, 0, len(status.ProcessGroups))
+	for _, processGroup := range status.ProcessGroups {
+		ids = append(ids, processGroup.InstanceID)
+	}
+	g.Expect(ids).To(gomega.Equal([]string{"log-1", "storage-1", "storage-2", "storage-3"}))
+
+	processGroup := status.GetProcessGroup("log-1")
+	g.Expect(processGroup.Addresses).To(gomega.Equal([]string{"1.1.1.3"}))
+	g.Expect(processGroup.HasCondition(MarkedForRemoval)).To(gomega.BeTrue())
+	g.Expect(processGroup.HasCondition(Excluded)).To(gomega.BeFalse())
+
+	processGroup = status.GetProcessGroup("storage-1")
+	g.Expect(processGroup.Addresses).To(gomega.Equal([]string{"1.1.1.1:4501"}))
+	g.Expect(processGroup.HasCondition(MarkedForRemoval)).To(gomega.BeTrue())
+	g.Expect(processGroup.HasCondition(Excluded)).To(gomega.BeFalse())
+
+	processGroup = status.GetProcessGroup("storage-2")
+	g.Expect(processGroup.HasCondition(MarkedForRemoval)).To(gomega.BeTrue())
+	g.Expect(processGroup.HasCondition(Excluded)).To(gomega.BeTrue())
+
+	processGroup = status.GetProcessGroup("storage-3")
+	g.Expect(processGroup.Conditions).To(gomega.BeNil())
+
+	g.Expect(status.GetProcessGroup("storage-4")).To(gomega.BeNil())
+}
+
+func TestGettingReplacementOptions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := &FoundationDBCluster{}
+	g.Expect(cluster.ShouldReplaceMissingProcesses()).To(gomega.BeFalse())
+	g.Expect(cluster.GetFailureDetectionTimeSeconds()).To(gomega.Equal(1800))
+	g.Expect(cluster.GetMaxConcurrentReplacements()).To(gomega.Equal(1))
+
+	enabled := true
+	detectionTime := 300
+	maxReplacements := 3
+	cluster.Spec.AutomationOptions.Replacements = AutomaticReplacementOptions{
+		Enabled:                     &enabled,
+		FailureDetectionTimeSeconds: &detectionTime,
+		MaxConcurrentReplacements:   &maxReplacements,
+	}
+	g.Expect(cluster.ShouldReplaceMissingProcesses()).To(gomega.BeTrue())
+	g.Expect(cluster.GetFailureDetectionTimeSeconds()).To(gomega.Equal(300))
+	g.Expect(cluster.GetMaxConcurrentReplacements()).To(gomega.Equal(3))
+}
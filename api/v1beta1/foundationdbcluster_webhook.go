@@ -0,0 +1,593 @@
+/*
+ * foundationdbcluster_webhook.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-apps-foundationdb-org-v1beta1-foundationdbcluster,mutating=true,failurePolicy=fail,groups=apps.foundationdb.org,resources=foundationdbclusters,versions=v1beta1,name=mfoundationdbcluster.kb.io
+// +kubebuilder:webhook:verbs=create;update,path=/validate-apps-foundationdb-org-v1beta1-foundationdbcluster,mutating=false,failurePolicy=fail,groups=apps.foundationdb.org,resources=foundationdbclusters,versions=v1beta1,name=vfoundationdbcluster.kb.io
+
+var _ webhook.Defaulter = &FoundationDBCluster{}
+var _ webhook.Validator = &FoundationDBCluster{}
+
+// WebhookDefaults controls how the defaulting webhook fills in defaults that
+// are changing between major versions of the operator.
+var WebhookDefaults DefaultsSelection
+
+// validRedundancyModes provides the redundancy modes that can be used in the
+// database configuration.
+var validRedundancyModes = []string{
+	"single", "double", "triple",
+	"three_data_hall", "three_datacenter", "three_datacenter_fallback",
+}
+
+// validStorageEngines provides the storage engines that can be used in the
+// database configuration.
+var validStorageEngines = []string{
+	"ssd", "ssd-1", "ssd-2", "ssd-redwood-experimental",
+	"memory", "memory-1", "memory-2", "memory-radixtree-beta",
+}
+
+// validPodSpreadStrategies provides the strategies that can be used for
+// spreading pods across fault domains.
+var validPodSpreadStrategies = []string{
+	string(PodSpreadStrategyPreferredAntiAffinity),
+	string(PodSpreadStrategyRequiredAntiAffinity),
+	string(PodSpreadStrategyTopologySpread),
+	string(PodSpreadStrategyNone),
+}
+
+// validPublicIPSources provides the sources that processes can use for their
+// public IPs.
+var validPublicIPSources = []string{
+	string(PublicIPSourcePod),
+	string(PublicIPSourceService),
+}
+
+// validBounceStrategyTypes provides the strategies that the operator can use
+// for bouncing processes.
+var validBounceStrategyTypes = []string{
+	string(BounceStrategyAll),
+	string(BounceStrategyFaultDomain),
+}
+
+// SetupWebhookWithManager registers the webhooks for clusters with the
+// manager.
+func (cluster *FoundationDBCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(cluster).Complete()
+}
+
+// Default fills in the defaults for a cluster spec and moves configuration
+// from deprecated fields into the fields that replace them.
+func (cluster *FoundationDBCluster) Default() {
+	NormalizeClusterSpec(&cluster.Spec, WebhookDefaults)
+}
+
+// ValidateCreate checks that a new cluster has a valid spec.
+func (cluster *FoundationDBCluster) ValidateCreate() error {
+	return newValidationError("FoundationDBCluster", cluster.Name, cluster.validateSpec())
+}
+
+// ValidateUpdate checks that an updated cluster has a valid spec, and that
+// the change to the spec does not downgrade the cluster.
+func (cluster *FoundationDBCluster) ValidateUpdate(old runtime.Object) error {
+	oldCluster, canCast := old.(*FoundationDBCluster)
+	if !canCast {
+		return fmt.Errorf("expected a FoundationDBCluster but got a %T", old)
+	}
+
+	allErrs := cluster.validateSpec()
+	allErrs = append(allErrs, cluster.validateVersionChange(oldCluster)...)
+	return newValidationError("FoundationDBCluster", cluster.Name, allErrs)
+}
+
+// ValidateDelete checks whether a cluster can be deleted.
+//
+// We do not place any restrictions on deleting clusters.
+func (cluster *FoundationDBCluster) ValidateDelete() error {
+	return nil
+}
+
+// validateSpec checks the cluster spec for values that the operator will not
+// be able to reconcile.
+func (cluster *FoundationDBCluster) validateSpec() field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateVersion(specPath.Child("version"), cluster.Spec.Version)...)
+
+	version, err := ParseFdbVersion(cluster.Spec.Version)
+	if err == nil && !version.IsSupported() {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("version"), cluster.Spec.Version,
+			fmt.Sprintf("is not supported, the supported versions are %s", GetSupportedVersionDescription())))
+	}
+
+	configurationPath := specPath.Child("databaseConfiguration")
+	if cluster.Spec.RedundancyMode != "" && !containsString(validRedundancyModes, cluster.Spec.RedundancyMode) {
+		allErrs = append(allErrs, field.NotSupported(configurationPath.Child("redundancy_mode"), cluster.Spec.RedundancyMode, validRedundancyModes))
+	}
+	if cluster.Spec.StorageEngine != "" && !containsString(validStorageEngines, cluster.Spec.StorageEngine) {
+		allErrs = append(allErrs, field.NotSupported(configurationPath.Child("storage_engine"), cluster.Spec.StorageEngine, validStorageEngines))
+	}
+
+	spreadStrategy := string(cluster.Spec.FaultDomain.SpreadStrategy)
+	if spreadStrategy != "" && !containsString(validPodSpreadStrategies, spreadStrategy) {
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("faultDomain", "spreadStrategy"), spreadStrategy, validPodSpreadStrategies))
+	}
+
+	publicIPSource := string(cluster.Spec.Services.PublicIPSource)
+	if publicIPSource != "" && !containsString(validPublicIPSources, publicIPSource) {
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("services", "publicIPSource"), publicIPSource, validPublicIPSources))
+	}
+
+	if cluster.Spec.PodIPFamily != nil && *cluster.Spec.PodIPFamily != 4 && *cluster.Spec.PodIPFamily != 6 {
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("podIPFamily"), *cluster.Spec.PodIPFamily, []string{"4", "6"}))
+	}
+
+	bounceStrategyType := string(cluster.Spec.BounceStrategy.Type)
+	if bounceStrategyType != "" && !containsString(validBounceStrategyTypes, bounceStrategyType) {
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("bounceStrategy", "type"), bounceStrategyType, validBounceStrategyTypes))
+	}
+
+	if cluster.Spec.BounceStrategy.MaxProcessesPerBatch < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("bounceStrategy", "maxProcessesPerBatch"), cluster.Spec.BounceStrategy.MaxProcessesPerBatch, "must not be negative"))
+	}
+
+	allErrs = append(allErrs, cluster.validateProcessCounts(specPath.Child("processCounts"))...)
+	allErrs = append(allErrs, cluster.validateRegions(specPath)...)
+	allErrs = append(allErrs, cluster.validateAutoscaling(specPath.Child("autoscaling"))...)
+	allErrs = append(allErrs, cluster.validateCoordinatorSelection(specPath.Child("coordinatorSelection"))...)
+	allErrs = append(allErrs, cluster.validateCanaryUpgrade(specPath.Child("canaryUpgrade"))...)
+
+	return allErrs
+}
+
+// validateCanaryUpgrade checks that the canary settings refer to known
+// process classes, provide a valid pod selector, and choose at least one
+// process.
+func (cluster *FoundationDBCluster) validateCanaryUpgrade(canaryPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	classesPath := canaryPath.Child("processClasses")
+	for index, processClass := range cluster.Spec.CanaryUpgrade.ProcessClasses {
+		if !containsString(ProcessClasses, processClass) {
+			allErrs = append(allErrs, field.NotSupported(classesPath.Index(index), processClass, ProcessClasses))
+		}
+	}
+
+	selector := cluster.Spec.CanaryUpgrade.PodSelector
+	if selector != nil {
+		_, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(canaryPath.Child("podSelector"), selector, err.Error()))
+		}
+	}
+
+	maxProcesses := cluster.GetCanaryMaxProcesses()
+	if maxProcesses < 1 {
+		allErrs = append(allErrs, field.Invalid(canaryPath.Child("maxProcesses"), maxProcesses, "must be at least 1"))
+	}
+
+	soakPeriod := cluster.GetCanarySoakPeriodSeconds()
+	if soakPeriod < 0 {
+		allErrs = append(allErrs, field.Invalid(canaryPath.Child("soakPeriodSeconds"), soakPeriod, "must not be negative"))
+	}
+
+	return allErrs
+}
+
+// validateCoordinatorSelection checks that the coordinator selection settings
+// refer to known process classes and provide a valid pod selector.
+func (cluster *FoundationDBCluster) validateCoordinatorSelection(selectionPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	classesPath := selectionPath.Child("processClasses")
+	seenClasses := make(map[string]bool, len(cluster.Spec.CoordinatorSelection.ProcessClasses))
+	for index, setting := range cluster.Spec.CoordinatorSelection.ProcessClasses {
+		classPath := classesPath.Index(index).Child("processClass")
+		if !containsString(ProcessClasses, setting.ProcessClass) {
+			allErrs = append(allErrs, field.NotSupported(classPath, setting.ProcessClass, ProcessClasses))
+		} else if seenClasses[setting.ProcessClass] {
+			allErrs = append(allErrs, field.Duplicate(classPath, setting.ProcessClass))
+		}
+		seenClasses[setting.ProcessClass] = true
+	}
+
+	selector := cluster.Spec.CoordinatorSelection.ExcludedPodSelector
+	if selector != nil {
+		_, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(selectionPath.Child("excludedPodSelector"), selector, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateProcessCounts checks that the process counts in the spec provide
+// enough processes to meet the replication requirements of the redundancy
+// mode.
+//
+// Counts that are left at their default values are not checked, since the
+// operator will fill those in based on the fault tolerance.
+func (cluster *FoundationDBCluster) validateProcessCounts(countsPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if cluster.Spec.FaultDomain.Key == "foundationdb.org/kubernetes-cluster" {
+		// The processes are spread across multiple Kubernetes clusters, so a
+		// single cluster spec does not tell us the total count.
+		return allErrs
+	}
+
+	minimumCount := cluster.MinimumFaultDomains()
+	redundancyMode := cluster.Spec.RedundancyMode
+	if redundancyMode == "" {
+		redundancyMode = "double"
+	}
+	message := fmt.Sprintf("must be at least %d to support redundancy mode %s", minimumCount, redundancyMode)
+
+	if cluster.Spec.ProcessCounts.Storage > 0 && cluster.Spec.ProcessCounts.Storage < minimumCount {
+		allErrs = append(allErrs, field.Invalid(countsPath.Child("storage"), cluster.Spec.ProcessCounts.Storage, message))
+	}
+
+	if cluster.Spec.ProcessCounts.Log > 0 && cluster.Spec.ProcessCounts.Log < minimumCount && !cluster.isSatelliteOnly() {
+		allErrs = append(allErrs, field.Invalid(countsPath.Child("log"), cluster.Spec.ProcessCounts.Log, message))
+	}
+
+	return allErrs
+}
+
+// ValidateAutoscaling checks that the autoscaling configuration provides a
+// usable range of storage process counts and a reachable target.
+//
+// The operator uses this to skip autoscaling when the webhooks are disabled
+// and the configuration has not been validated on admission.
+func (cluster *FoundationDBCluster) ValidateAutoscaling() error {
+	return newValidationError("FoundationDBCluster", cluster.Name, cluster.validateAutoscaling(field.NewPath("spec", "autoscaling")))
+}
+
+// validateAutoscaling checks that the autoscaling configuration provides a
+// usable range of storage process counts and a reachable target.
+func (cluster *FoundationDBCluster) validateAutoscaling(autoscalingPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if !cluster.ShouldAutoscaleStorage() {
+		return allErrs
+	}
+
+	minimumCount := 1
+	if cluster.Spec.FaultDomain.Key != "foundationdb.org/kubernetes-cluster" {
+		minimumCount = cluster.MinimumFaultDomains()
+	}
+
+	autoscaling := cluster.Spec.Autoscaling
+	if autoscaling.MinStorageProcesses < minimumCount {
+		allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minStorageProcesses"), autoscaling.MinStorageProcesses, fmt.Sprintf("must be at least %d", minimumCount)))
+	}
+	if autoscaling.MaxStorageProcesses < autoscaling.MinStorageProcesses {
+		allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("maxStorageProcesses"), autoscaling.MaxStorageProcesses, "must be at least minStorageProcesses"))
+	}
+
+	target := cluster.GetTargetDiskUtilizationPercent()
+	if target <= 0 || target > 100 {
+		allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("targetDiskUtilizationPercent"), target, "must be between 1 and 100"))
+	}
+
+	tolerance := cluster.GetAutoscalingTolerancePercent()
+	if tolerance < 0 || tolerance >= target {
+		allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("tolerancePercent"), tolerance, "must be at least 0 and less than targetDiskUtilizationPercent"))
+	}
+
+	return allErrs
+}
+
+// isSatelliteOnly determines whether the data center for this cluster is
+// only used as a satellite in the region configuration.
+func (cluster *FoundationDBCluster) isSatelliteOnly() bool {
+	isSatellite := false
+	for _, region := range cluster.Spec.Regions {
+		for _, dataCenter := range region.DataCenters {
+			if dataCenter.ID != cluster.Spec.DataCenter {
+				continue
+			}
+			if dataCenter.Satellite == 0 {
+				return false
+			}
+			isSatellite = true
+		}
+	}
+	return isSatellite
+}
+
+// validateRegions checks that the data center for the cluster is one of the
+// data centers in the region configuration.
+func (cluster *FoundationDBCluster) validateRegions(specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(cluster.Spec.Regions) == 0 {
+		return allErrs
+	}
+
+	dataCenterIDs := make([]string, 0)
+	for _, region := range cluster.Spec.Regions {
+		for _, dataCenter := range region.DataCenters {
+			if !containsString(dataCenterIDs, dataCenter.ID) {
+				dataCenterIDs = append(dataCenterIDs, dataCenter.ID)
+			}
+		}
+	}
+
+	dataCenterPath := specPath.Child("dataCenter")
+	if cluster.Spec.DataCenter == "" {
+		allErrs = append(allErrs, field.Required(dataCenterPath, "must be set when regions are configured"))
+	} else if !containsString(dataCenterIDs, cluster.Spec.DataCenter) {
+		allErrs = append(allErrs, field.Invalid(dataCenterPath, cluster.Spec.DataCenter,
+			fmt.Sprintf("must match one of the data centers in the regions: %s", strings.Join(dataCenterIDs, ", "))))
+	}
+
+	return allErrs
+}
+
+// validateVersionChange checks that an update to the spec does not downgrade
+// the version of FoundationDB running on the cluster to a version that is not
+// protocol-compatible, unless the spec explicitly allows it.
+func (cluster *FoundationDBCluster) validateVersionChange(oldCluster *FoundationDBCluster) field.ErrorList {
+	var allErrs field.ErrorList
+	if oldCluster.Status.RunningVersion == "" {
+		return allErrs
+	}
+
+	runningVersion, err := ParseFdbVersion(oldCluster.Status.RunningVersion)
+	if err != nil {
+		return allErrs
+	}
+
+	version, err := ParseFdbVersion(cluster.Spec.Version)
+	if err != nil {
+		// This is reported by validateSpec.
+		return allErrs
+	}
+
+	if !version.IsAtLeast(runningVersion) && !version.IsProtocolCompatible(runningVersion) && !cluster.Spec.AllowIncompatibleDowngrade {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "version"),
+			fmt.Sprintf("cannot downgrade a cluster running version %s to a version that is not protocol-compatible without setting allowIncompatibleDowngrade", runningVersion)))
+	}
+
+	if version.IsSupported() {
+		err = CheckUpgradePath(runningVersion, version)
+		if err != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "version"), err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateVersion checks that a version in a spec can be parsed.
+func validateVersion(versionPath *field.Path, version string) field.ErrorList {
+	var allErrs field.ErrorList
+	_, err := ParseFdbVersion(version)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(versionPath, version, "must be in the format major.minor.patch"))
+	}
+	return allErrs
+}
+
+// newValidationError builds the error to return from a webhook for a list of
+// validation failures. This will return nil if there are no failures.
+func newValidationError(kind string, name string, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return k8serrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: kind}, name, allErrs)
+}
+
+// containsString determines whether a list of strings contains a value.
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureContainerPresent looks for a container by name from a list, and adds
+// an empty container with that name if none is present.
+func ensureContainerPresent(containers []corev1.Container, name string, insertIndex int) ([]corev1.Container, int) {
+	for index, container := range containers {
+		if container.Name == name {
+			return containers, index
+		}
+	}
+
+	if insertIndex < 0 || insertIndex >= len(containers) {
+		containers = append(containers, corev1.Container{Name: name})
+		return containers, len(containers) - 1
+	}
+	containerCount := 1 + len(containers)
+	newContainers := make([]corev1.Container, 0, containerCount)
+	for indexToCopy := 0; indexToCopy < len(containers); indexToCopy++ {
+		if indexToCopy == insertIndex {
+			newContainers = append(newContainers, corev1.Container{
+				Name: name,
+			})
+		}
+		newContainers = append(newContainers, containers[indexToCopy])
+	}
+
+	return newContainers, insertIndex
+}
+
+// customizeContainerFromList finds a container by name and runs a customization
+// function on the container.
+func customizeContainerFromList(containers []corev1.Container, name string, customizer func(*corev1.Container)) []corev1.Container {
+	containers, index := ensureContainerPresent(containers, name, -1)
+	container := containers[index]
+	customizer(&container)
+	containers[index] = container
+	return containers
+}
+
+// moveDeprecatedPodTemplate moves the pod template from the deprecated
+// PodTemplate field into the general process settings.
+//
+// If the general process settings already have a pod template, the deprecated
+// pod template will be discarded.
+func moveDeprecatedPodTemplate(spec *FoundationDBClusterSpec) {
+	if spec.PodTemplate == nil {
+		return
+	}
+
+	if spec.Processes == nil {
+		spec.Processes = make(map[string]ProcessSettings)
+	}
+	generalSettings := spec.Processes["general"]
+	if generalSettings.PodTemplate == nil {
+		generalSettings.PodTemplate = spec.PodTemplate
+	}
+	spec.Processes["general"] = generalSettings
+	spec.PodTemplate = nil
+}
+
+// DefaultsSelection controls how defaults that are changing get applied to our
+// specs.
+// +kubebuilder:object:generate=false
+type DefaultsSelection struct {
+	// Whether we should apply the latest defaults rather than the defaults that
+	// were initially established for this major version.
+	UseFutureDefaults bool
+
+	// Whether we should only fill in defaults that have changes between major
+	// versions of the operator.
+	OnlyShowChanges bool
+}
+
+// NormalizeClusterSpec converts a cluster spec into an unambiguous,
+// future-proof form, by applying any implicit defaults and moving configuration
+// from deprecated fields into fully-supported fields.
+func NormalizeClusterSpec(spec *FoundationDBClusterSpec, defaults DefaultsSelection) {
+	moveDeprecatedPodTemplate(spec)
+
+	if !defaults.OnlyShowChanges {
+		// Set up resource requirements for the main container.
+
+		if spec.Processes == nil {
+			spec.Processes = make(map[string]ProcessSettings)
+		}
+		_, present := spec.Processes["general"]
+		if !present {
+			spec.Processes["general"] = ProcessSettings{}
+		}
+
+		for processClass, settings := range spec.Processes {
+			if settings.PodTemplate == nil {
+				settings.PodTemplate = &corev1.PodTemplateSpec{}
+			}
+
+			settings.PodTemplate.Spec.Containers, _ = ensureContainerPresent(settings.PodTemplate.Spec.Containers, "foundationdb", 0)
+
+			settings.PodTemplate.Spec.Containers = customizeContainerFromList(settings.PodTemplate.Spec.Containers, "foundationdb", func(container *corev1.Container) {
+				if container.Resources.Requests == nil {
+					container.Resources.Requests = corev1.ResourceList{
+						"cpu":    resource.MustParse("1"),
+						"memory": resource.MustParse("1Gi"),
+					}
+				}
+
+				if container.Resources.Limits == nil {
+					container.Resources.Limits = container.Resources.Requests
+				}
+			})
+
+			spec.Processes[processClass] = settings
+		}
+	}
+
+	// Apply changes between old and new defaults.
+	// When we update the defaults in the next release, the following sections
+	// should be moved under the `!OnlyShowChanges` section, and we should use
+	// the latest defaults as the active defaults.
+
+	// Set up sidecar resource requirements
+	if spec.Processes == nil {
+		spec.Processes = make(map[string]ProcessSettings)
+	}
+	_, present := spec.Processes["general"]
+	if !present {
+		spec.Processes["general"] = ProcessSettings{}
+	}
+
+	for processClass, settings := range spec.Processes {
+		if settings.PodTemplate == nil {
+			settings.PodTemplate = &corev1.PodTemplateSpec{}
+		}
+
+		sidecarUpdater := func(container *corev1.Container) {
+			if defaults.UseFutureDefaults {
+				if container.Resources.Requests == nil {
+					container.Resources.Requests = corev1.ResourceList{
+						"cpu":    resource.MustParse("100m"),
+						"memory": resource.MustParse("256Mi"),
+					}
+				}
+				if container.Resources.Limits == nil {
+					container.Resources.Limits = container.Resources.Requests
+				}
+			} else {
+				if container.Resources.Requests == nil {
+					container.Resources.Requests = corev1.ResourceList{}
+				}
+				if container.Resources.Limits == nil {
+					container.Resources.Limits = corev1.ResourceList{}
+				}
+			}
+		}
+
+		settings.PodTemplate.Spec.InitContainers = customizeContainerFromList(settings.PodTemplate.Spec.InitContainers, "foundationdb-kubernetes-init", sidecarUpdater)
+		settings.PodTemplate.Spec.Containers = customizeContainerFromList(settings.PodTemplate.Spec.Containers, "foundationdb-kubernetes-sidecar", sidecarUpdater)
+
+		spec.Processes[processClass] = settings
+	}
+
+	// Set up pod disruption budgets
+	if spec.PodDisruptionBudgets.Enabled == nil {
+		enabled := defaults.UseFutureDefaults
+		spec.PodDisruptionBudgets.Enabled = &enabled
+	}
+
+	// Set up coordinator selection
+	if spec.CoordinatorSelection.PreserveHealthyCoordinators == nil {
+		preserve := defaults.UseFutureDefaults
+		spec.CoordinatorSelection.PreserveHealthyCoordinators = &preserve
+	}
+}
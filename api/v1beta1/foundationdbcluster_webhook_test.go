@@ -0,0 +1,436 @@
+/*
+ * foundationdbcluster_webhook_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createValidationCluster() *FoundationDBCluster {
+	return &FoundationDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+		},
+		Spec: FoundationDBClusterSpec{
+			Version: Versions.Default.String(),
+			DatabaseConfiguration: DatabaseConfiguration{
+				RedundancyMode: "double",
+				StorageEngine:  "ssd",
+			},
+		},
+	}
+}
+
+func TestDefaultingCluster(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := createValidationCluster()
+	cluster.Spec.PodTemplate = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "foundationdb",
+				Image: "foundationdb/foundationdb:6.2.20",
+			}},
+		},
+	}
+	cluster.Default()
+
+	g.Expect(cluster.Spec.PodTemplate).To(gomega.BeNil())
+	generalSettings := cluster.Spec.Processes["general"]
+	g.Expect(generalSettings.PodTemplate).NotTo(gomega.BeNil())
+	containers := generalSettings.PodTemplate.Spec.Containers
+	g.Expect(containers).To(gomega.HaveLen(2))
+	g.Expect(containers[0].Name).To(gomega.Equal("foundationdb"))
+	g.Expect(containers[0].Image).To(gomega.Equal("foundationdb/foundationdb:6.2.20"))
+	g.Expect(containers[0].Resources.Requests).To(gomega.Equal(corev1.ResourceList{
+		"cpu":    resource.MustParse("1"),
+		"memory": resource.MustParse("1Gi"),
+	}))
+	g.Expect(containers[1].Name).To(gomega.Equal("foundationdb-kubernetes-sidecar"))
+	g.Expect(containers[1].Resources.Requests).To(gomega.Equal(corev1.ResourceList{}))
+
+	normalizedSpec := cluster.Spec.DeepCopy()
+	cluster.Default()
+	g.Expect(cluster.Spec).To(gomega.Equal(*normalizedSpec))
+
+	WebhookDefaults = DefaultsSelection{UseFutureDefaults: true}
+	defer func() { WebhookDefaults = DefaultsSelection{} }()
+
+	cluster = createValidationCluster()
+	cluster.Default()
+	containers = cluster.Spec.Processes["general"].PodTemplate.Spec.Containers
+	g.Expect(containers[1].Name).To(gomega.Equal("foundationdb-kubernetes-sidecar"))
+	g.Expect(containers[1].Resources.Requests).To(gomega.Equal(corev1.ResourceList{
+		"cpu":    resource.MustParse("100m"),
+		"memory": resource.MustParse("256Mi"),
+	}))
+}
+
+func TestValidatingNewCluster(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := createValidationCluster()
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster = createValidationCluster()
+	cluster.Spec.RedundancyMode = ""
+	cluster.Spec.StorageEngine = ""
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster = createValidationCluster()
+	cluster.Spec.Version = "6.2"
+	err := cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.version"))
+
+	cluster = createValidationCluster()
+	cluster.Spec.Version = "8.0.0"
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("is not supported"))
+
+	cluster = createValidationCluster()
+	cluster.Spec.RedundancyMode = "quadruple"
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.databaseConfiguration.redundancy_mode"))
+
+	cluster = createValidationCluster()
+	cluster.Spec.StorageEngine = "rocksdb"
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.databaseConfiguration.storage_engine"))
+
+	cluster = createValidationCluster()
+	cluster.Spec.FaultDomain.SpreadStrategy = PodSpreadStrategyTopologySpread
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster = createValidationCluster()
+	cluster.Spec.FaultDomain.SpreadStrategy = "RandomPlacement"
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.faultDomain.spreadStrategy"))
+
+	cluster = createValidationCluster()
+	cluster.Spec.Services.PublicIPSource = PublicIPSourceService
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster = createValidationCluster()
+	cluster.Spec.Services.PublicIPSource = "node"
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.services.publicIPSource"))
+
+	ipFamily := 6
+	cluster = createValidationCluster()
+	cluster.Spec.PodIPFamily = &ipFamily
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	ipFamily = 5
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.podIPFamily"))
+
+	cluster = createValidationCluster()
+	cluster.Spec.BounceStrategy = BounceStrategyConfig{Type: BounceStrategyFaultDomain, MaxProcessesPerBatch: 2}
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster = createValidationCluster()
+	cluster.Spec.BounceStrategy.Type = "random"
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.bounceStrategy.type"))
+
+	cluster = createValidationCluster()
+	cluster.Spec.BounceStrategy.MaxProcessesPerBatch = -1
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.bounceStrategy.maxProcessesPerBatch"))
+}
+
+func TestValidatingProcessCounts(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cluster := createValidationCluster()
+	cluster.Spec.ProcessCounts.Storage = 2
+	cluster.Spec.ProcessCounts.Log = 2
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster.Spec.ProcessCounts.Storage = -1
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster = createValidationCluster()
+	cluster.Spec.ProcessCounts.Storage = 1
+	err := cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.processCounts.storage"))
+
+	cluster = createValidationCluster()
+	cluster.Spec.RedundancyMode = "triple"
+	cluster.Spec.ProcessCounts.Log = 2
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.processCounts.log"))
+
+	cluster = createValidationCluster()
+	cluster.Spec.FaultDomain = FoundationDBClusterFaultDomain{
+		Key:       "foundationdb.org/kubernetes-cluster",
+		Value:     "kc2",
+		ZoneIndex: 1,
+		ZoneCount: 3,
+	}
+	cluster.Spec.ProcessCounts.Storage = 1
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+}
+
+func TestValidatingRegions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	createRegionCluster := func() *FoundationDBCluster {
+		cluster := createValidationCluster()
+		cluster.Spec.UsableRegions = 2
+		cluster.Spec.DataCenter = "dc1"
+		cluster.Spec.Regions = []Region{
+			{
+				DataCenters: []DataCenter{
+					{ID: "dc1", Priority: 1},
+					{ID: "dc2", Priority: 1, Satellite: 1},
+				},
+				SatelliteLogs: 2,
+			},
+			{
+				DataCenters: []DataCenter{
+					{ID: "dc3", Priority: 0},
+				},
+			},
+		}
+		return cluster
+	}
+
+	cluster := createRegionCluster()
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster = createRegionCluster()
+	cluster.Spec.DataCenter = "dc2"
+	cluster.Spec.ProcessCounts.Log = 1
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster = createRegionCluster()
+	cluster.Spec.DataCenter = "dc4"
+	err := cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("must match one of the data centers in the regions: dc1, dc2, dc3"))
+
+	cluster = createRegionCluster()
+	cluster.Spec.DataCenter = ""
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.dataCenter"))
+}
+
+func TestValidatingAutoscaling(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	createAutoscalingCluster := func() *FoundationDBCluster {
+		cluster := createValidationCluster()
+		enabled := true
+		cluster.Spec.Autoscaling = AutoscalingConfig{
+			Enabled:             &enabled,
+			MinStorageProcesses: 3,
+			MaxStorageProcesses: 10,
+		}
+		return cluster
+	}
+
+	cluster := createAutoscalingCluster()
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster = createAutoscalingCluster()
+	cluster.Spec.Autoscaling.MinStorageProcesses = 1
+	err := cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.autoscaling.minStorageProcesses"))
+
+	cluster = createAutoscalingCluster()
+	g.Expect(cluster.ValidateAutoscaling()).To(gomega.Succeed())
+	cluster.Spec.Autoscaling.MinStorageProcesses = 0
+	cluster.Spec.Autoscaling.MaxStorageProcesses = 0
+	err = cluster.ValidateAutoscaling()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.autoscaling.minStorageProcesses"))
+
+	cluster = createAutoscalingCluster()
+	cluster.Spec.Autoscaling.MaxStorageProcesses = 2
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.autoscaling.maxStorageProcesses"))
+
+	cluster = createAutoscalingCluster()
+	target := 120
+	cluster.Spec.Autoscaling.TargetDiskUtilizationPercent = &target
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.autoscaling.targetDiskUtilizationPercent"))
+
+	cluster = createAutoscalingCluster()
+	tolerance := 60
+	cluster.Spec.Autoscaling.TolerancePercent = &tolerance
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.autoscaling.tolerancePercent"))
+
+	cluster = createAutoscalingCluster()
+	disabled := false
+	cluster.Spec.Autoscaling.Enabled = &disabled
+	cluster.Spec.Autoscaling.MinStorageProcesses = 0
+	cluster.Spec.Autoscaling.MaxStorageProcesses = 0
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+}
+
+func TestValidatingCoordinatorSelection(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	createSelectionCluster := func() *FoundationDBCluster {
+		cluster := createValidationCluster()
+		cluster.Spec.CoordinatorSelection = CoordinatorSelectionConfig{
+			ProcessClasses: []CoordinatorSelectionSetting{
+				{ProcessClass: "log", Priority: 10},
+				{ProcessClass: "storage", Priority: 5},
+			},
+			LocalityFields: []string{"data_hall"},
+			ExcludedPodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"coordinator-excluded": "true"},
+			},
+		}
+		return cluster
+	}
+
+	cluster := createSelectionCluster()
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster = createSelectionCluster()
+	cluster.Spec.CoordinatorSelection.ProcessClasses[1].ProcessClass = "bad"
+	err := cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.coordinatorSelection.processClasses[1].processClass"))
+
+	cluster = createSelectionCluster()
+	cluster.Spec.CoordinatorSelection.ProcessClasses[1].ProcessClass = "log"
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("Duplicate value"))
+
+	cluster = createSelectionCluster()
+	cluster.Spec.CoordinatorSelection.ExcludedPodSelector.MatchExpressions = []metav1.LabelSelectorRequirement{
+		{Key: "coordinator-excluded", Operator: "Bogus"},
+	}
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.coordinatorSelection.excludedPodSelector"))
+}
+
+func TestValidatingCanaryUpgrade(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	maxProcesses := 2
+	soakPeriod := 1800
+	cluster := createValidationCluster()
+	cluster.Spec.CanaryUpgrade = CanaryUpgradeConfig{
+		ProcessClasses:    []string{"stateless"},
+		PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+		MaxProcesses:      &maxProcesses,
+		SoakPeriodSeconds: &soakPeriod,
+	}
+	g.Expect(cluster.ValidateCreate()).To(gomega.Succeed())
+
+	cluster.Spec.CanaryUpgrade.ProcessClasses = []string{"stateless", "bogus"}
+	err := cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.canaryUpgrade.processClasses[1]"))
+	cluster.Spec.CanaryUpgrade.ProcessClasses = []string{"stateless"}
+
+	cluster.Spec.CanaryUpgrade.PodSelector.MatchExpressions = []metav1.LabelSelectorRequirement{
+		{Key: "canary", Operator: "Bogus"},
+	}
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.canaryUpgrade.podSelector"))
+	cluster.Spec.CanaryUpgrade.PodSelector.MatchExpressions = nil
+
+	maxProcesses = 0
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.canaryUpgrade.maxProcesses"))
+	maxProcesses = 1
+
+	soakPeriod = -1
+	err = cluster.ValidateCreate()
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.canaryUpgrade.soakPeriodSeconds"))
+}
+
+func TestValidatingClusterUpdate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	oldCluster := createValidationCluster()
+	oldCluster.Status.RunningVersion = Versions.Default.String()
+
+	cluster := oldCluster.DeepCopy()
+	cluster.Spec.Version = Versions.NextMinorVersion.String()
+	g.Expect(cluster.ValidateUpdate(oldCluster)).To(gomega.Succeed())
+
+	cluster = oldCluster.DeepCopy()
+	cluster.Spec.Version = Versions.NextMajorVersion.String()
+	err := cluster.ValidateUpdate(oldCluster)
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("upgrade through these versions in order first: 6.3"))
+
+	cluster = oldCluster.DeepCopy()
+	cluster.Spec.Version = Versions.WithoutSidecarCrashOnEmpty.String()
+	g.Expect(cluster.ValidateUpdate(oldCluster)).To(gomega.Succeed())
+
+	cluster = oldCluster.DeepCopy()
+	cluster.Spec.Version = Versions.WithoutRatekeeperRole.String()
+	err = cluster.ValidateUpdate(oldCluster)
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("cannot downgrade a cluster running version 6.2.20"))
+
+	cluster.Spec.AllowIncompatibleDowngrade = true
+	g.Expect(cluster.ValidateUpdate(oldCluster)).To(gomega.Succeed())
+
+	oldCluster.Status.RunningVersion = ""
+	g.Expect(cluster.ValidateUpdate(oldCluster)).To(gomega.Succeed())
+
+	cluster = oldCluster.DeepCopy()
+	cluster.Spec.StorageEngine = "rocksdb"
+	err = cluster.ValidateUpdate(oldCluster)
+	g.Expect(k8serrors.IsInvalid(err)).To(gomega.BeTrue())
+
+	g.Expect(cluster.ValidateUpdate(&FoundationDBBackup{})).NotTo(gomega.Succeed())
+}
@@ -0,0 +1,68 @@
+/*
+ * foundationdbrestore_conversion.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+var _ conversion.Convertible = &FoundationDBRestore{}
+
+// ConvertTo converts this restore to the hub version.
+func (restore *FoundationDBRestore) ConvertTo(dstRaw conversion.Hub) error {
+	dst, canCast := dstRaw.(*v1beta2.FoundationDBRestore)
+	if !canCast {
+		return fmt.Errorf("cannot convert a FoundationDBRestore to a %T", dstRaw)
+	}
+
+	dst.ObjectMeta = *restore.ObjectMeta.DeepCopy()
+
+	dst.Spec = v1beta2.FoundationDBRestoreSpec{}
+	err := convertThroughJSON(restore.Spec, &dst.Spec)
+	if err != nil {
+		return err
+	}
+
+	dst.Status = v1beta2.FoundationDBRestoreStatus{}
+	return convertThroughJSON(restore.Status, &dst.Status)
+}
+
+// ConvertFrom converts a restore from the hub version into this version.
+func (restore *FoundationDBRestore) ConvertFrom(srcRaw conversion.Hub) error {
+	src, canCast := srcRaw.(*v1beta2.FoundationDBRestore)
+	if !canCast {
+		return fmt.Errorf("cannot convert a %T to a FoundationDBRestore", srcRaw)
+	}
+
+	restore.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	restore.Spec = FoundationDBRestoreSpec{}
+	err := convertThroughJSON(src.Spec, &restore.Spec)
+	if err != nil {
+		return err
+	}
+
+	restore.Status = FoundationDBRestoreStatus{}
+	return convertThroughJSON(src.Status, &restore.Status)
+}
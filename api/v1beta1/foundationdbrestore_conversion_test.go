@@ -0,0 +1,59 @@
+/*
+ * foundationdbrestore_conversion_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConvertingRestore(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	restore := &FoundationDBRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sample-restore",
+			Namespace: "default",
+		},
+		Spec: FoundationDBRestoreSpec{
+			DestinationClusterName: "sample-cluster",
+			BackupURL:              "blobstore://test@test-service/sample-backup?bucket=fdb-backups",
+		},
+		Status: FoundationDBRestoreStatus{
+			Running: true,
+		},
+	}
+
+	hub := &v1beta2.FoundationDBRestore{}
+	err := restore.ConvertTo(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(hub.Spec.DestinationClusterName).To(gomega.Equal("sample-cluster"))
+	g.Expect(hub.Spec.BackupURL).To(gomega.Equal(restore.Spec.BackupURL))
+	g.Expect(hub.Status.Running).To(gomega.BeTrue())
+
+	converted := &FoundationDBRestore{}
+	err = converted.ConvertFrom(hub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted).To(gomega.Equal(restore))
+}
@@ -0,0 +1,98 @@
+/*
+ * foundationdbrestore_webhook.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	ctx "context"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-apps-foundationdb-org-v1beta1-foundationdbrestore,mutating=false,failurePolicy=fail,groups=apps.foundationdb.org,resources=foundationdbrestores,versions=v1beta1,name=vfoundationdbrestore.kb.io
+
+var _ webhook.Validator = &FoundationDBRestore{}
+
+// restoreWebhookClient provides the client the restore webhook uses to look
+// up the destination cluster.
+var restoreWebhookClient client.Reader
+
+// SetupWebhookWithManager registers the webhooks for restores with the
+// manager.
+func (restore *FoundationDBRestore) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	restoreWebhookClient = mgr.GetClient()
+	return ctrl.NewWebhookManagedBy(mgr).For(restore).Complete()
+}
+
+// ValidateCreate checks that a new restore has a valid spec.
+func (restore *FoundationDBRestore) ValidateCreate() error {
+	allErrs, err := restore.validateSpec(restoreWebhookClient)
+	if err != nil {
+		return err
+	}
+	return newValidationError("FoundationDBRestore", restore.Name, allErrs)
+}
+
+// ValidateUpdate checks that an updated restore has a valid spec.
+func (restore *FoundationDBRestore) ValidateUpdate(old runtime.Object) error {
+	return restore.ValidateCreate()
+}
+
+// ValidateDelete checks whether a restore can be deleted.
+//
+// We do not place any restrictions on deleting restores.
+func (restore *FoundationDBRestore) ValidateDelete() error {
+	return nil
+}
+
+// validateSpec checks the restore spec for values that the operator will not
+// be able to reconcile.
+//
+// If the reader is nil, this will skip the check that the destination cluster
+// exists.
+func (restore *FoundationDBRestore) validateSpec(reader client.Reader) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if restore.Spec.BackupURL == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("backupURL"), "must provide the URL of the backup to restore"))
+	}
+
+	clusterPath := specPath.Child("destinationClusterName")
+	if restore.Spec.DestinationClusterName == "" {
+		allErrs = append(allErrs, field.Required(clusterPath, "must provide the cluster to restore into"))
+	} else if reader != nil {
+		cluster := &FoundationDBCluster{}
+		err := reader.Get(ctx.TODO(), types.NamespacedName{Namespace: restore.Namespace, Name: restore.Spec.DestinationClusterName}, cluster)
+		if k8serrors.IsNotFound(err) {
+			allErrs = append(allErrs, field.NotFound(clusterPath, restore.Spec.DestinationClusterName))
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	return allErrs, nil
+}
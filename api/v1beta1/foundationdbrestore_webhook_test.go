@@ -0,0 +1,85 @@
+/*
+ * foundationdbrestore_webhook_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidatingRestore(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(gomega.Succeed())
+
+	cluster := createValidationCluster()
+	reader := fake.NewFakeClientWithScheme(scheme, cluster)
+
+	createRestore := func() *FoundationDBRestore {
+		return &FoundationDBRestore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-restore",
+				Namespace: "default",
+			},
+			Spec: FoundationDBRestoreSpec{
+				DestinationClusterName: "foo",
+				BackupURL:              "blobstore://test@test-service/foo?bucket=fdb-backups",
+			},
+		}
+	}
+
+	restore := createRestore()
+	allErrs, err := restore.validateSpec(reader)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(allErrs).To(gomega.BeEmpty())
+
+	restore = createRestore()
+	restore.Spec.DestinationClusterName = "bar"
+	allErrs, err = restore.validateSpec(reader)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(allErrs).To(gomega.HaveLen(1))
+	g.Expect(allErrs[0].Error()).To(gomega.Equal("spec.destinationClusterName: Not found: \"bar\""))
+
+	restore = createRestore()
+	restore.Namespace = "other"
+	allErrs, err = restore.validateSpec(reader)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(allErrs).To(gomega.HaveLen(1))
+
+	restore = createRestore()
+	restore.Spec.DestinationClusterName = "bar"
+	allErrs, err = restore.validateSpec(nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(allErrs).To(gomega.BeEmpty())
+
+	restore = createRestore()
+	restore.Spec.BackupURL = ""
+	allErrs, err = restore.validateSpec(reader)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(allErrs).To(gomega.HaveLen(1))
+	g.Expect(allErrs[0].Field).To(gomega.Equal("spec.backupURL"))
+}
@@ -17,7 +17,7 @@ limitations under the License.
 package v1beta1
 
 var Versions = struct {
-	NextMajorVersion,
+	NextMajorVersion, NextMinorVersion,
 	WithSidecarInstanceIDSubstitution, WithoutSidecarInstanceIDSubstitution,
 	WithCommandLineVariablesForSidecar, WithEnvironmentVariablesForSidecar,
 	WithBinariesFromMainContainer, WithoutBinariesFromMainContainer,
@@ -27,6 +27,7 @@ var Versions = struct {
 }{
 	Default:                              FdbVersion{Major: 6, Minor: 2, Patch: 20},
 	NextMajorVersion:                     FdbVersion{Major: 7, Minor: 0, Patch: 0},
+	NextMinorVersion:                     FdbVersion{Major: 6, Minor: 3, Patch: 0},
 	WithSidecarInstanceIDSubstitution:    FdbVersion{Major: 6, Minor: 2, Patch: 15},
 	WithoutSidecarInstanceIDSubstitution: FdbVersion{Major: 6, Minor: 2, Patch: 11},
 	WithCommandLineVariablesForSidecar:   FdbVersion{Major: 6, Minor: 2, Patch: 15},
@@ -26,6 +26,86 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutomaticReplacementOptions) DeepCopyInto(out *AutomaticReplacementOptions) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FailureDetectionTimeSeconds != nil {
+		in, out := &in.FailureDetectionTimeSeconds, &out.FailureDetectionTimeSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxConcurrentReplacements != nil {
+		in, out := &in.MaxConcurrentReplacements, &out.MaxConcurrentReplacements
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomaticReplacementOptions.
+func (in *AutomaticReplacementOptions) DeepCopy() *AutomaticReplacementOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(AutomaticReplacementOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingConfig) DeepCopyInto(out *AutoscalingConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TargetDiskUtilizationPercent != nil {
+		in, out := &in.TargetDiskUtilizationPercent, &out.TargetDiskUtilizationPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.TolerancePercent != nil {
+		in, out := &in.TolerancePercent, &out.TolerancePercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.CooldownSeconds != nil {
+		in, out := &in.CooldownSeconds, &out.CooldownSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingConfig.
+func (in *AutoscalingConfig) DeepCopy() *AutoscalingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingStatus) DeepCopyInto(out *AutoscalingStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingStatus.
+func (in *AutoscalingStatus) DeepCopy() *AutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupGenerationStatus) DeepCopyInto(out *BackupGenerationStatus) {
 	*out = *in
@@ -41,6 +121,117 @@ func (in *BackupGenerationStatus) DeepCopy() *BackupGenerationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BounceStatus) DeepCopyInto(out *BounceStatus) {
+	*out = *in
+	if in.CurrentBatch != nil {
+		in, out := &in.CurrentBatch, &out.CurrentBatch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BouncedInstances != nil {
+		in, out := &in.BouncedInstances, &out.BouncedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BounceStatus.
+func (in *BounceStatus) DeepCopy() *BounceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BounceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BounceStrategyConfig) DeepCopyInto(out *BounceStrategyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BounceStrategyConfig.
+func (in *BounceStrategyConfig) DeepCopy() *BounceStrategyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BounceStrategyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryUpgradeConfig) DeepCopyInto(out *CanaryUpgradeConfig) {
+	*out = *in
+	if in.ProcessClasses != nil {
+		in, out := &in.ProcessClasses, &out.ProcessClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxProcesses != nil {
+		in, out := &in.MaxProcesses, &out.MaxProcesses
+		*out = new(int)
+		**out = **in
+	}
+	if in.SoakPeriodSeconds != nil {
+		in, out := &in.SoakPeriodSeconds, &out.SoakPeriodSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryUpgradeConfig.
+func (in *CanaryUpgradeConfig) DeepCopy() *CanaryUpgradeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryUpgradeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryUpgradeStatus) DeepCopyInto(out *CanaryUpgradeStatus) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryUpgradeStatus.
+func (in *CanaryUpgradeStatus) DeepCopy() *CanaryUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCondition.
+func (in *ClusterCondition) DeepCopy() *ClusterCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterGenerationStatus) DeepCopyInto(out *ClusterGenerationStatus) {
 	*out = *in
@@ -125,6 +316,56 @@ func (in *ContainerOverrides) DeepCopy() *ContainerOverrides {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoordinatorSelectionConfig) DeepCopyInto(out *CoordinatorSelectionConfig) {
+	*out = *in
+	if in.ProcessClasses != nil {
+		in, out := &in.ProcessClasses, &out.ProcessClasses
+		*out = make([]CoordinatorSelectionSetting, len(*in))
+		copy(*out, *in)
+	}
+	if in.LocalityFields != nil {
+		in, out := &in.LocalityFields, &out.LocalityFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedPodSelector != nil {
+		in, out := &in.ExcludedPodSelector, &out.ExcludedPodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreserveHealthyCoordinators != nil {
+		in, out := &in.PreserveHealthyCoordinators, &out.PreserveHealthyCoordinators
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoordinatorSelectionConfig.
+func (in *CoordinatorSelectionConfig) DeepCopy() *CoordinatorSelectionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CoordinatorSelectionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoordinatorSelectionSetting) DeepCopyInto(out *CoordinatorSelectionSetting) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoordinatorSelectionSetting.
+func (in *CoordinatorSelectionSetting) DeepCopy() *CoordinatorSelectionSetting {
+	if in == nil {
+		return nil
+	}
+	out := new(CoordinatorSelectionSetting)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataCenter) DeepCopyInto(out *DataCenter) {
 	*out = *in
@@ -179,6 +420,21 @@ func (in *FdbVersion) DeepCopy() *FdbVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FdbVersionSeries) DeepCopyInto(out *FdbVersionSeries) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FdbVersionSeries.
+func (in *FdbVersionSeries) DeepCopy() *FdbVersionSeries {
+	if in == nil {
+		return nil
+	}
+	out := new(FdbVersionSeries)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBBackup) DeepCopyInto(out *FoundationDBBackup) {
 	*out = *in
@@ -354,6 +610,7 @@ func (in *FoundationDBClusterAutomationOptions) DeepCopyInto(out *FoundationDBCl
 		*out = new(bool)
 		**out = **in
 	}
+	in.Replacements.DeepCopyInto(&out.Replacements)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterAutomationOptions.
@@ -461,8 +718,23 @@ func (in *FoundationDBClusterSpec) DeepCopyInto(out *FoundationDBClusterSpec) {
 		copy(*out, *in)
 	}
 	in.AutomationOptions.DeepCopyInto(&out.AutomationOptions)
+	out.BounceStrategy = in.BounceStrategy
+	in.CanaryUpgrade.DeepCopyInto(&out.CanaryUpgrade)
 	in.LockOptions.DeepCopyInto(&out.LockOptions)
 	in.Services.DeepCopyInto(&out.Services)
+	if in.PodIPFamily != nil {
+		in, out := &in.PodIPFamily, &out.PodIPFamily
+		*out = new(int)
+		**out = **in
+	}
+	in.PodDisruptionBudgets.DeepCopyInto(&out.PodDisruptionBudgets)
+	in.CoordinatorSelection.DeepCopyInto(&out.CoordinatorSelection)
+	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
+	if in.UseNativeAdminClient != nil {
+		in, out := &in.UseNativeAdminClient, &out.UseNativeAdminClient
+		*out = new(bool)
+		**out = **in
+	}
 	if in.PodLabels != nil {
 		in, out := &in.PodLabels, &out.PodLabels
 		*out = make(map[string]string, len(*in))
@@ -575,6 +847,13 @@ func (in *FoundationDBClusterStatus) DeepCopyInto(out *FoundationDBClusterStatus
 	}
 	in.DatabaseConfiguration.DeepCopyInto(&out.DatabaseConfiguration)
 	out.Generations = in.Generations
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.Health = in.Health
 	out.RequiredAddresses = in.RequiredAddresses
 	if in.PendingRemovals != nil {
@@ -584,6 +863,21 @@ func (in *FoundationDBClusterStatus) DeepCopyInto(out *FoundationDBClusterStatus
 			(*out)[key] = val
 		}
 	}
+	if in.ProcessGroups != nil {
+		in, out := &in.ProcessGroups, &out.ProcessGroups
+		*out = make([]ProcessGroupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Autoscaling = in.Autoscaling
+	in.Bounce.DeepCopyInto(&out.Bounce)
+	if in.UpgradeLaggingPods != nil {
+		in, out := &in.UpgradeLaggingPods, &out.UpgradeLaggingPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.CanaryUpgrade.DeepCopyInto(&out.CanaryUpgrade)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterStatus.
@@ -821,6 +1115,7 @@ func (in *FoundationDBStatusClusterInfo) DeepCopyInto(out *FoundationDBStatusClu
 	out.Data = in.Data
 	in.Clients.DeepCopyInto(&out.Clients)
 	in.Layers.DeepCopyInto(&out.Layers)
+	out.FaultTolerance = in.FaultTolerance
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusClusterInfo.
@@ -899,6 +1194,21 @@ func (in *FoundationDBStatusDataStatistics) DeepCopy() *FoundationDBStatusDataSt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusFaultTolerance) DeepCopyInto(out *FoundationDBStatusFaultTolerance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusFaultTolerance.
+func (in *FoundationDBStatusFaultTolerance) DeepCopy() *FoundationDBStatusFaultTolerance {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusFaultTolerance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusLayerInfo) DeepCopyInto(out *FoundationDBStatusLayerInfo) {
 	*out = *in
@@ -947,6 +1257,21 @@ func (in *FoundationDBStatusMovingData) DeepCopy() *FoundationDBStatusMovingData
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusProcessDiskInfo) DeepCopyInto(out *FoundationDBStatusProcessDiskInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessDiskInfo.
+func (in *FoundationDBStatusProcessDiskInfo) DeepCopy() *FoundationDBStatusProcessDiskInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusProcessDiskInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusProcessInfo) DeepCopyInto(out *FoundationDBStatusProcessInfo) {
 	*out = *in
@@ -957,6 +1282,12 @@ func (in *FoundationDBStatusProcessInfo) DeepCopyInto(out *FoundationDBStatusPro
 			(*out)[key] = val
 		}
 	}
+	out.Disk = in.Disk
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]FoundationDBStatusProcessRoleInfo, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessInfo.
@@ -969,6 +1300,21 @@ func (in *FoundationDBStatusProcessInfo) DeepCopy() *FoundationDBStatusProcessIn
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusProcessRoleInfo) DeepCopyInto(out *FoundationDBStatusProcessRoleInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessRoleInfo.
+func (in *FoundationDBStatusProcessRoleInfo) DeepCopy() *FoundationDBStatusProcessRoleInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusProcessRoleInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusSupportedVersion) DeepCopyInto(out *FoundationDBStatusSupportedVersion) {
 	*out = *in
@@ -1029,6 +1375,31 @@ func (in *PendingRemovalState) DeepCopy() *PendingRemovalState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetConfig) DeepCopyInto(out *PodDisruptionBudgetConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetConfig.
+func (in *PodDisruptionBudgetConfig) DeepCopy() *PodDisruptionBudgetConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProcessAddress) DeepCopyInto(out *ProcessAddress) {
 	*out = *in
@@ -1066,6 +1437,46 @@ func (in *ProcessCounts) DeepCopy() *ProcessCounts {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessGroupCondition) DeepCopyInto(out *ProcessGroupCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupCondition.
+func (in *ProcessGroupCondition) DeepCopy() *ProcessGroupCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessGroupCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessGroupStatus) DeepCopyInto(out *ProcessGroupStatus) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ProcessGroupCondition, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupStatus.
+func (in *ProcessGroupStatus) DeepCopy() *ProcessGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProcessSettings) DeepCopyInto(out *ProcessSettings) {
 	*out = *in
@@ -0,0 +1,30 @@
+/*
+ * conversion.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+// Hub marks this type as a conversion hub.
+func (*FoundationDBCluster) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*FoundationDBBackup) Hub() {}
+
+// Hub marks this type as a conversion hub.
+func (*FoundationDBRestore) Hub() {}
@@ -0,0 +1,1001 @@
+/*
+ * foundationdbcluster_types.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fdb
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.processCounts.storage,statuspath=.status.processCounts.storage,selectorpath=.status.storageProcessSelector
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Generation",type="integer",JSONPath=".metadata.generation",description="Latest generation of the spec",priority=0
+// +kubebuilder:printcolumn:name="Reconciled",type="integer",JSONPath=".status.generations.reconciled",description="Last reconciled generation of the spec",priority=0
+// +kubebuilder:printcolumn:name="Healthy",type="boolean",JSONPath=".status.health.healthy",description="Database health",priority=0
+
+// FoundationDBCluster is the Schema for the foundationdbclusters API
+type FoundationDBCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FoundationDBClusterSpec   `json:"spec,omitempty"`
+	Status FoundationDBClusterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FoundationDBClusterList contains a list of FoundationDBCluster
+type FoundationDBClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FoundationDBCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&FoundationDBCluster{}, &FoundationDBClusterList{},
+		&FoundationDBBackup{}, &FoundationDBBackupList{},
+		&FoundationDBRestore{}, &FoundationDBRestoreList{},
+	)
+}
+
+// FoundationDBClusterSpec defines the desired state of a cluster.
+type FoundationDBClusterSpec struct {
+	// Version defines the version of FoundationDB the cluster should run.
+	Version string `json:"version"`
+
+	// SidecarVersions defines the build version of the sidecar to run. This
+	// maps an FDB version to the corresponding sidecar build version.
+	SidecarVersions map[string]int `json:"sidecarVersions,omitempty"`
+
+	// DatabaseConfiguration defines the database configuration.
+	DatabaseConfiguration DatabaseConfiguration `json:"databaseConfiguration,omitempty"`
+
+	// Processes defines process-level settings.
+	Processes map[string]ProcessSettings `json:"processes,omitempty"`
+
+	// ProcessCounts defines the number of processes to configure for each
+	// process class. You can generally omit this, to allow the operator to
+	// infer the process counts based on the database configuration.
+	ProcessCounts ProcessCounts `json:"processCounts,omitempty"`
+
+	// SeedConnectionString provides a connection string for the initial
+	// reconciliation.
+	//
+	// After the initial reconciliation, this will not be used.
+	SeedConnectionString string `json:"seedConnectionString,omitempty"`
+
+	// FaultDomain defines the rules for what fault domain to replicate across.
+	FaultDomain FoundationDBClusterFaultDomain `json:"faultDomain,omitempty"`
+
+	// InstancesToRemove defines the instances that we should remove from the
+	// cluster. This list contains the instance IDs.
+	InstancesToRemove []string `json:"instancesToRemove,omitempty"`
+
+	// InstancesToRemoveWithoutExclusion defines the instances that we should
+	// remove from the cluster without excluding them. This list contains the
+	// instance IDs.
+	//
+	// This should be used for cases where a pod does not have an IP address and
+	// you want to remove it and destroy its volume without confirming the data
+	// is fully replicated.
+	InstancesToRemoveWithoutExclusion []string `json:"instancesToRemoveWithoutExclusion,omitempty"`
+
+	// ConfigMap allows customizing the config map the operator creates.
+	ConfigMap *corev1.ConfigMap `json:"configMap,omitempty"`
+
+	// MainContainer defines customization for the foundationdb container.
+	MainContainer ContainerOverrides `json:"mainContainer,omitempty"`
+
+	// SidecarContainer defines customization for the
+	// foundationdb-kubernetes-sidecar container.
+	SidecarContainer ContainerOverrides `json:"sidecarContainer,omitempty"`
+
+	// TrustedCAs defines a list of root CAs the cluster should trust, in PEM
+	// format.
+	TrustedCAs []string `json:"trustedCAs,omitempty"`
+
+	// SidecarVariables defines custom variables that the sidecar should make
+	// available for substitution in the monitor conf file.
+	SidecarVariables []string `json:"sidecarVariables,omitempty"`
+
+	// LogGroup defines the log group to use for the trace logs for the cluster.
+	LogGroup string `json:"logGroup,omitempty"`
+
+	// DataCenter defines the data center where these processes are running.
+	DataCenter string `json:"dataCenter,omitempty"`
+
+	// DataHall defines the data hall where these processes are running.
+	DataHall string `json:"dataHall,omitempty"`
+
+	// AutomationOptions defines customization for enabling or disabling certain
+	// operations in the operator.
+	AutomationOptions FoundationDBClusterAutomationOptions `json:"automationOptions,omitempty"`
+
+	// InstanceIDPrefix defines a prefix to append to the instance IDs in the
+	// locality fields.
+	InstanceIDPrefix string `json:"instanceIDPrefix,omitempty"`
+
+	// UpdatePodsByReplacement determines whether we should update pod config
+	// by replacing the pods rather than deleting them.
+	UpdatePodsByReplacement bool `json:"updatePodsByReplacement,omitempty"`
+
+	// BounceStrategy defines how the operator restarts processes when their
+	// configuration changes.
+	BounceStrategy BounceStrategyConfig `json:"bounceStrategy,omitempty"`
+
+	// CanaryUpgrade defines a set of processes that the operator should
+	// upgrade first when the cluster moves to a new patch release, so that
+	// the new version can be tested on a small part of the cluster before it
+	// is rolled out everywhere.
+	CanaryUpgrade CanaryUpgradeConfig `json:"canaryUpgrade,omitempty"`
+
+	// LockOptions allows customizing how we manage locks for global operations.
+	LockOptions LockOptions `json:"lockOptions,omitempty"`
+
+	// Services defines the configuration for services that sit in front of our
+	// pods.
+	Services ServiceConfig `json:"services,omitempty"`
+
+	// PodIPFamily defines the family of the IP addresses that the processes
+	// use as their public IPs. This can be 4 for IPv4 or 6 for IPv6.
+	//
+	// When this is 6, the operator wraps the public IPs in brackets when it
+	// builds the addresses for the processes. In a dual-stack environment,
+	// this must match the family of the primary IP for the pods.
+	//
+	// The default is 4.
+	PodIPFamily *int `json:"podIPFamily,omitempty"`
+
+	// PodDisruptionBudgets defines the configuration for the pod disruption
+	// budget that the operator manages for the cluster.
+	PodDisruptionBudgets PodDisruptionBudgetConfig `json:"podDisruptionBudgets,omitempty"`
+
+	// CoordinatorSelection defines how the operator chooses the processes
+	// that serve as coordinators.
+	CoordinatorSelection CoordinatorSelectionConfig `json:"coordinatorSelection,omitempty"`
+
+	// Autoscaling defines the configuration for automatically scaling the
+	// storage processes based on disk utilization.
+	Autoscaling AutoscalingConfig `json:"autoscaling,omitempty"`
+
+	// IgnoreUpgradabilityChecks determines whether we should skip the check for
+	// client compatibility when performing an upgrade.
+	IgnoreUpgradabilityChecks bool `json:"ignoreUpgradabilityChecks,omitempty"`
+
+	// AllowIncompatibleDowngrade determines whether we should allow
+	// downgrading the cluster to a version that is not protocol-compatible
+	// with the running version.
+	//
+	// Downgrades between protocol-compatible versions, such as from 6.2.20 to
+	// 6.2.19, are always allowed.
+	AllowIncompatibleDowngrade bool `json:"allowIncompatibleDowngrade,omitempty"`
+
+	// UseNativeAdminClient determines whether the operator should use the
+	// FoundationDB client library to run administrative operations on this
+	// cluster, rather than running fdbcli. If this is omitted, the operator
+	// will use its global default.
+	UseNativeAdminClient *bool `json:"useNativeAdminClient,omitempty"`
+}
+
+// FoundationDBClusterStatus defines the observed state of FoundationDBCluster
+type FoundationDBClusterStatus struct {
+	// ProcessCounts defines the number of processes that are currently running
+	// in the cluster.
+	ProcessCounts ProcessCounts `json:"processCounts,omitempty"`
+
+	// IncorrectProcesses provides the processes that do not have the correct
+	// configuration.
+	//
+	// This will map the instance ID to the timestamp when we observed the
+	// incorrect configuration.
+	IncorrectProcesses map[string]int64 `json:"incorrectProcesses,omitempty"`
+
+	// IncorrectPods provides the pods that do not have the correct
+	// spec.
+	//
+	// This will contain the name of the pod.
+	IncorrectPods []string `json:"incorrectPods,omitempty"`
+
+	// FailingPods provides the pods that are not starting correctly.
+	//
+	// This will contain the name of the pod.
+	FailingPods []string `json:"failingPods,omitempty"`
+
+	// MissingProcesses provides the processes that are not reporting to the
+	// cluster.
+	// This will map the names of the pod to the timestamp when we observed
+	// that the process was missing.
+	MissingProcesses map[string]int64 `json:"missingProcesses,omitempty"`
+
+	// DatabaseConfiguration provides the running configuration of the database.
+	DatabaseConfiguration DatabaseConfiguration `json:"databaseConfiguration,omitempty"`
+
+	// Generations provides information about the latest generation to be
+	// reconciled, or to reach other stages at which reconciliation can halt.
+	Generations ClusterGenerationStatus `json:"generations,omitempty"`
+
+	// Conditions provides the standard conditions describing the state of the
+	// cluster. These are updated whenever we check the reconciliation of the
+	// cluster.
+	Conditions []ClusterCondition `json:"conditions,omitempty"`
+
+	// Health provides information about the health of the database.
+	Health ClusterHealth `json:"health,omitempty"`
+
+	// RequiredAddresses define that addresses that we need to enable for the
+	// processes in the cluster.
+	RequiredAddresses RequiredAddressSet `json:"requiredAddresses,omitempty"`
+
+	// HasIncorrectConfigMap indicates whether the latest config map is out
+	// of date with the cluster spec.
+	HasIncorrectConfigMap bool `json:"hasIncorrectConfigMap,omitempty"`
+
+	// HasIncorrectServiceConfig indicates whether the cluster has service
+	// config that is out of date with the cluster spec.
+	HasIncorrectServiceConfig bool `json:"hasIncorrectServiceConfig,omitempty"`
+
+	// NeedsNewCoordinators indicates whether the cluster needs to recruit
+	// new coordinators to fulfill its fault tolerance requirements.
+	NeedsNewCoordinators bool `json:"needsNewCoordinators,omitempty"`
+
+	// RunningVersion defines the version of FoundationDB that the cluster is
+	// currently running.
+	RunningVersion string `json:"runningVersion,omitempty"`
+
+	// ConnectionString defines the contents of the cluster file.
+	ConnectionString string `json:"connectionString,omitempty"`
+
+	// Configured defines whether we have configured the database yet.
+	Configured bool `json:"configured,omitempty"`
+
+	// PendingRemovals defines the processes that are pending removal.
+	// This maps the instance ID to its removal state.
+	PendingRemovals map[string]PendingRemovalState `json:"pendingRemovals,omitempty"`
+
+	// ProcessGroups provides the state of the processes for each instance
+	// ID, including any problems we have observed with them.
+	ProcessGroups []ProcessGroupStatus `json:"processGroups,omitempty"`
+
+	// NeedsSidecarConfInConfigMap determines whether we need to include the
+	// sidecar conf in the config map even when the latest version should not
+	// require it.
+	NeedsSidecarConfInConfigMap bool `json:"needsSidecarConfInConfigMap,omitempty"`
+
+	// MaintenanceZone provides the zone that the operator has put into
+	// maintenance mode while it updates the processes in that zone.
+	MaintenanceZone string `json:"maintenanceZone,omitempty"`
+
+	// Autoscaling provides information about the storage autoscaling
+	// decisions the operator has made.
+	Autoscaling AutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// StorageProcessSelector provides the label selector for the storage
+	// pods, in the serialized form used by the scale subresource.
+	StorageProcessSelector string `json:"storageProcessSelector,omitempty"`
+
+	// Bounce provides information about a bounce that the operator is doing
+	// in multiple batches.
+	Bounce BounceStatus `json:"bounce,omitempty"`
+
+	// UpgradeLaggingPods provides the pods that do not yet have the binaries
+	// for the version that the cluster is upgrading to. The operator will not
+	// bounce the processes for the upgrade until this is empty.
+	//
+	// This will contain the name of the pod.
+	UpgradeLaggingPods []string `json:"upgradeLaggingPods,omitempty"`
+
+	// CanaryUpgrade provides information about the canary processes for the
+	// upgrade that is in progress.
+	CanaryUpgrade CanaryUpgradeStatus `json:"canaryUpgrade,omitempty"`
+}
+
+// BounceStatus records the progress of a bounce that the operator is doing
+// in multiple batches.
+type BounceStatus struct {
+	// Strategy provides the strategy that the operator is using for the
+	// bounce.
+	Strategy BounceStrategyType `json:"strategy,omitempty"`
+
+	// CurrentBatch provides the IDs of the instances that the operator
+	// bounced most recently. The operator will wait for these instances to
+	// rejoin the cluster before it bounces any more instances.
+	CurrentBatch []string `json:"currentBatch,omitempty"`
+
+	// BouncedInstances provides the IDs of all of the instances that the
+	// operator has bounced as part of this bounce.
+	BouncedInstances []string `json:"bouncedInstances,omitempty"`
+}
+
+// CanaryUpgradeStatus records the progress of the canary processes for an
+// upgrade.
+type CanaryUpgradeStatus struct {
+	// Version provides the version that the canary processes were upgraded
+	// to.
+	Version string `json:"version,omitempty"`
+
+	// Instances provides the IDs of the instances that the operator chose as
+	// canaries.
+	Instances []string `json:"instances,omitempty"`
+
+	// State provides the stage of the canary upgrade.
+	State CanaryUpgradeState `json:"state,omitempty"`
+
+	// StartTimestamp provides the time when the canary processes entered
+	// their current state, as a Unix timestamp.
+	StartTimestamp int64 `json:"startTimestamp,omitempty"`
+
+	// Message provides the reason that the operator halted the upgrade.
+	Message string `json:"message,omitempty"`
+}
+
+// CanaryUpgradeState describes the stage of a canary upgrade.
+type CanaryUpgradeState string
+
+const (
+	// CanaryUpgradeStarting indicates that the canary processes have been
+	// bounced, and the operator is waiting for them to rejoin the cluster.
+	CanaryUpgradeStarting CanaryUpgradeState = "starting"
+
+	// CanaryUpgradeSoaking indicates that the canary processes are running
+	// the new version, and the operator is watching the health of the
+	// cluster before it upgrades the rest of the processes.
+	CanaryUpgradeSoaking CanaryUpgradeState = "soaking"
+
+	// CanaryUpgradePromoted indicates that the cluster stayed healthy for
+	// the soak period, and the operator is upgrading the rest of the
+	// processes.
+	CanaryUpgradePromoted CanaryUpgradeState = "promoted"
+
+	// CanaryUpgradeHalted indicates that the operator has stopped the
+	// upgrade because the canary processes or the cluster were not healthy.
+	CanaryUpgradeHalted CanaryUpgradeState = "halted"
+)
+
+// AutoscalingStatus records information about the storage autoscaling
+// decisions the operator has made.
+type AutoscalingStatus struct {
+	// LastScaleTimestamp provides the time when the operator last changed the
+	// storage process count, as a Unix timestamp.
+	LastScaleTimestamp int64 `json:"lastScaleTimestamp,omitempty"`
+}
+
+// ClusterGenerationStatus stores information on which generations have reached
+// different stages in reconciliation for the cluster.
+type ClusterGenerationStatus struct {
+	// Reconciled provides the last generation that was fully reconciled.
+	Reconciled int64 `json:"reconciled,omitempty"`
+
+	// NeedsConfigurationChange provides the last generation that is pending
+	// a change to configuration.
+	NeedsConfigurationChange int64 `json:"needsConfigurationChange,omitempty"`
+
+	// NeedsCoordinatorChange provides the last generation that is pending
+	// a change to its coordinators.
+	NeedsCoordinatorChange int64 `json:"needsCoordinatorChange,omitempty"`
+
+	// NeedsBounce provides the last generation that is pending a bounce of
+	// fdbserver.
+	NeedsBounce int64 `json:"needsBounce,omitempty"`
+
+	// NeedsPodDeletion provides the last generation that is pending pods being
+	// deleted and recreated.
+	NeedsPodDeletion int64 `json:"needsPodDeletion,omitempty"`
+
+	// NeedsShrink provides the last generation that is pending pods being
+	// excluded and removed.
+	NeedsShrink int64 `json:"needsShrink,omitempty"`
+
+	// NeedsGrow provides the last generation that is pending pods being
+	// added.
+	NeedsGrow int64 `json:"needsGrow,omitempty"`
+
+	// NeedsMonitorConfUpdate provides the last generation that needs an update
+	// through the fdbmonitor conf.
+	NeedsMonitorConfUpdate int64 `json:"needsMonitorConfUpdate,omitempty"`
+
+	// DatabaseUnavailable provides the last generation that could not
+	// complete reconciliation due to the database being unavailable.
+	DatabaseUnavailable int64 `json:"databaseUnavailable,omitempty"`
+
+	// HasExtraListeners provides the last generation that could not
+	// complete reconciliation because it has more listeners than it is supposed
+	// to.
+	HasExtraListeners int64 `json:"hasExtraListeners,omitempty"`
+
+	// NeedsServiceUpdate provides the last generation that needs an update
+	// to the service config.
+	NeedsServiceUpdate int64 `json:"needsServiceUpdate,omitempty"`
+
+	// HasPendingRemoval provides the last generation that has pods that have
+	// been excluded but are pending being removed.
+	//
+	// A cluster in this state is considered reconciled, but we track this in
+	// the status to allow users of the operator to track when the removal
+	// is fully complete.
+	HasPendingRemoval int64 `json:"hasPendingRemoval,omitempty"`
+
+	// HasFailingPods provides the last generation that has pods that are
+	// failing to start.
+	HasFailingPods int64 `json:"hasFailingPods,omitempty"`
+}
+
+// ClusterConditionType defines a kind of condition that we report on a
+// cluster.
+type ClusterConditionType string
+
+const (
+	// ClusterConditionAvailable indicates whether the database is accepting
+	// reads and writes.
+	ClusterConditionAvailable ClusterConditionType = "Available"
+
+	// ClusterConditionFullyReplicated indicates whether all data in the
+	// database is fully replicated.
+	ClusterConditionFullyReplicated ClusterConditionType = "FullyReplicated"
+
+	// ClusterConditionReconciled indicates whether the latest generation of
+	// the spec has been fully reconciled.
+	ClusterConditionReconciled ClusterConditionType = "Reconciled"
+
+	// ClusterConditionUpgradeInProgress indicates whether the cluster is
+	// running a different version from the one in the spec.
+	ClusterConditionUpgradeInProgress ClusterConditionType = "UpgradeInProgress"
+
+	// ClusterConditionCoordinatorsValid indicates whether the current
+	// coordinators meet the fault tolerance requirements for the cluster.
+	ClusterConditionCoordinatorsValid ClusterConditionType = "CoordinatorsValid"
+
+	// ClusterConditionPodsFailing indicates whether any pods in the cluster
+	// are failing to start.
+	ClusterConditionPodsFailing ClusterConditionType = "PodsFailing"
+)
+
+// ClusterCondition describes one aspect of the state of the cluster.
+type ClusterCondition struct {
+	// Type provides the kind of condition.
+	Type ClusterConditionType `json:"type"`
+
+	// Status provides whether the condition holds. This will be True, False,
+	// or Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+
+	// Reason provides a machine-readable reason for the current status.
+	Reason string `json:"reason,omitempty"`
+
+	// Message provides a human-readable description of the current status.
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime provides the last time that the status changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ClusterHealth represents different views into health in the cluster status.
+type ClusterHealth struct {
+	// Available reports whether the database is accepting reads and writes.
+	Available bool `json:"available,omitempty"`
+
+	// Healthy reports whether the database is in a fully healthy state.
+	Healthy bool `json:"healthy,omitempty"`
+
+	// FullReplication reports whether all data are fully replicated according
+	// to the current replication policy.
+	FullReplication bool `json:"fullReplication,omitempty"`
+
+	// DataMovementPriority reports the priority of the highest-priority data
+	// movement in the cluster.
+	DataMovementPriority int `json:"dataMovementPriority,omitempty"`
+}
+
+// PendingRemovalState holds information about a process that is being removed.
+type PendingRemovalState struct {
+	// The name of the pod that is being removed.
+	PodName string `json:"podName,omitempty"`
+
+	// The public address of the process.
+	//
+	// On versions that support locality-based exclusions, the exclusion is
+	// tracked by instance ID, and this is only used to clean up exclusions
+	// that were made by address.
+	Address string `json:"address,omitempty"`
+
+	// Whether we have started the exclusion.
+	ExclusionStarted bool `json:"exclusionStarted,omitempty"`
+
+	// Whether we have completed the exclusion.
+	ExclusionComplete bool `json:"exclusionComplete,omitempty"`
+}
+
+// ProcessGroupStatus describes the state of the processes for a single
+// instance ID.
+type ProcessGroupStatus struct {
+	// InstanceID provides the instance ID for the processes.
+	InstanceID string `json:"instanceID"`
+
+	// ProcessClass provides the process class for the processes.
+	ProcessClass string `json:"processClass,omitempty"`
+
+	// Addresses provides the addresses that the processes were last seen
+	// at.
+	Addresses []string `json:"addresses,omitempty"`
+
+	// Conditions provides the problems and lifecycle stages that currently
+	// apply to the processes.
+	Conditions []ProcessGroupCondition `json:"conditions,omitempty"`
+}
+
+// ProcessGroupConditionType defines a kind of condition that can apply to a
+// process group.
+type ProcessGroupConditionType string
+
+const (
+	// MissingProcess indicates that the process is not reporting to the
+	// database.
+	MissingProcess ProcessGroupConditionType = "MissingProcess"
+
+	// IncorrectCommandLine indicates that the process is running with a
+	// command line or version that does not match the spec.
+	IncorrectCommandLine ProcessGroupConditionType = "IncorrectCommandLine"
+
+	// IncorrectPodSpec indicates that the pod or its volume claim does not
+	// match the spec.
+	IncorrectPodSpec ProcessGroupConditionType = "IncorrectPodSpec"
+
+	// PodFailing indicates that some of the containers in the pod are not
+	// ready.
+	PodFailing ProcessGroupConditionType = "PodFailing"
+
+	// Excluded indicates that the exclusion of the process has completed.
+	Excluded ProcessGroupConditionType = "Excluded"
+
+	// MarkedForRemoval indicates that the process is pending removal.
+	MarkedForRemoval ProcessGroupConditionType = "MarkedForRemoval"
+)
+
+// ProcessGroupCondition records a condition that applies to a process group.
+type ProcessGroupCondition struct {
+	// Type provides the kind of condition.
+	Type ProcessGroupConditionType `json:"type"`
+
+	// Timestamp provides the Unix timestamp when we first observed the
+	// condition.
+	Timestamp int64 `json:"timestamp"`
+}
+
+// RoleCounts represents the roles whose counts can be customized.
+type RoleCounts struct {
+	Storage    int `json:"storage,omitempty"`
+	Logs       int `json:"logs,omitempty"`
+	Proxies    int `json:"proxies,omitempty"`
+	Resolvers  int `json:"resolvers,omitempty"`
+	LogRouters int `json:"log_routers,omitempty"`
+	RemoteLogs int `json:"remote_logs,omitempty"`
+}
+
+// VersionFlags defines internal flags for new features in the database.
+type VersionFlags struct {
+	LogSpill   int `json:"log_spill,omitempty"`
+	LogVersion int `json:"log_version,omitempty"`
+}
+
+// ProcessCounts represents the number of processes we have for each valid
+// process class.
+//
+// If one of the counts in the spec is set to 0, we will infer the process count
+// for that class from the role counts. If one of the counts in the spec is set
+// to -1, we will not create any processes for that class. See
+// GetProcessCountsWithDefaults for more information on the rules for inferring
+// process counts.
+type ProcessCounts struct {
+	// Storage defines the number of storage class processes.
+	Storage int `json:"storage,omitempty"`
+
+	// Transaction defines the number of transaction class processes.
+	Transaction int `json:"transaction,omitempty"`
+
+	// Stateless defines the number of stateless class processes.
+	Stateless int `json:"stateless,omitempty"`
+
+	// Resolution defines the number of resolution class processes.
+	Resolution        int `json:"resolution,omitempty"`
+	Unset             int `json:"unset,omitempty"`
+	Log               int `json:"log,omitempty"`
+	Master            int `json:"master,omitempty"`
+	ClusterController int `json:"cluster_controller,omitempty"`
+	Proxy             int `json:"proxy,omitempty"`
+	Resolver          int `json:"resolver,omitempty"`
+	Router            int `json:"router,omitempty"`
+	Ratekeeper        int `json:"ratekeeper,omitempty"`
+	DataDistributor   int `json:"data_distributor,omitempty"`
+	FastRestore       int `json:"fast_restore,omitempty"`
+	BackupWorker      int `json:"backup,omitempty"`
+}
+
+// FoundationDBClusterAutomationOptions provides flags for enabling or disabling
+// operations that can be performed on a cluster.
+type FoundationDBClusterAutomationOptions struct {
+	// ConfigureDatabase defines whether the operator is allowed to reconfigure
+	// the database.
+	ConfigureDatabase *bool `json:"configureDatabase,omitempty"`
+
+	// KillProcesses defines whether the operator is allowed to bounce fdbserver
+	// processes.
+	KillProcesses *bool `json:"killProcesses,omitempty"`
+
+	// DeletePods defines whether the operator is allowed to delete pods in
+	// order to recreate them.
+	DeletePods *bool `json:"deletePods,omitempty"`
+
+	// Replacements configures automatic replacement of processes that have
+	// stopped reporting to the database.
+	Replacements AutomaticReplacementOptions `json:"replacements,omitempty"`
+}
+
+// AutomaticReplacementOptions controls options for automatically replacing
+// processes that have stopped reporting to the database.
+type AutomaticReplacementOptions struct {
+	// Enabled controls whether automatic replacements are enabled.
+	// The default is false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// FailureDetectionTimeSeconds controls how long a process must be
+	// missing from the database status before we replace it.
+	// The default is 1800.
+	FailureDetectionTimeSeconds *int `json:"failureDetectionTimeSeconds,omitempty"`
+
+	// MaxConcurrentReplacements controls how many processes can be pending
+	// removal before we stop starting new automatic replacements.
+	// The default is 1.
+	MaxConcurrentReplacements *int `json:"maxConcurrentReplacements,omitempty"`
+}
+
+// ProcessSettings defines process-level settings.
+type ProcessSettings struct {
+	// PodTemplate allows customizing the pod.
+	PodTemplate *corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+
+	// VolumeClaimTemplate allows customizing the persistent volume claim for the
+	// pod.
+	VolumeClaimTemplate *corev1.PersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
+
+	// CustomParameters defines additional parameters to pass to the fdbserver
+	// process.
+	CustomParameters *[]string `json:"customParameters,omitempty"`
+}
+
+// FoundationDBClusterFaultDomain describes the fault domain that a cluster is
+// replicated across.
+type FoundationDBClusterFaultDomain struct {
+	// Key provides a topology key for the fault domain to replicate across.
+	Key string `json:"key,omitempty"`
+
+	// Value provides a harcoded value to use for the zoneid for the pods.
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom provides a field selector to use as the source of the fault
+	// domain.
+	ValueFrom string `json:"valueFrom,omitempty"`
+
+	// ZoneCount provides the number of fault domains in the data center where
+	// these processes are running. This is only used in the
+	// `kubernetes-cluster` fault domain strategy.
+	ZoneCount int `json:"zoneCount,omitempty"`
+
+	// ZoneIndex provides the index of this Kubernetes cluster in the list of
+	// KCs in the data center. This is only used in the `kubernetes-cluster`
+	// fault domain strategy.
+	ZoneIndex int `json:"zoneIndex,omitempty"`
+
+	// SpreadStrategy determines how the operator asks the scheduler to spread
+	// the pods for each process class across the fault domains. This has no
+	// effect on pods that define their own pod anti-affinity or topology
+	// spread constraints. The default is PreferredAntiAffinity.
+	SpreadStrategy PodSpreadStrategy `json:"spreadStrategy,omitempty"`
+}
+
+// PodSpreadStrategy describes how pods are spread across fault domains.
+type PodSpreadStrategy string
+
+const (
+	// PodSpreadStrategyPreferredAntiAffinity adds a preferred pod
+	// anti-affinity for the pods in the same process class.
+	PodSpreadStrategyPreferredAntiAffinity PodSpreadStrategy = "PreferredAntiAffinity"
+
+	// PodSpreadStrategyRequiredAntiAffinity adds a required pod
+	// anti-affinity for the pods in the same process class.
+	PodSpreadStrategyRequiredAntiAffinity PodSpreadStrategy = "RequiredAntiAffinity"
+
+	// PodSpreadStrategyTopologySpread adds a topology spread constraint for
+	// the pods in the same process class.
+	PodSpreadStrategyTopologySpread PodSpreadStrategy = "TopologySpreadConstraints"
+
+	// PodSpreadStrategyNone does not add any rules for spreading pods.
+	PodSpreadStrategyNone PodSpreadStrategy = "None"
+)
+
+// DatabaseConfiguration represents the configuration of the database
+type DatabaseConfiguration struct {
+	// RedundancyMode defines the core replication factor for the database.
+	RedundancyMode string `json:"redundancy_mode,omitempty"`
+
+	// StorageEngine defines the storage engine the database uses.
+	StorageEngine string `json:"storage_engine,omitempty"`
+
+	// UsableRegions defines how many regions the database should store data in.
+	UsableRegions int `json:"usable_regions,omitempty"`
+
+	// Regions defines the regions that the database can replicate in.
+	Regions []Region `json:"regions,omitempty"`
+
+	// RoleCounts defines how many processes the database should recruit for
+	// each role.
+	RoleCounts `json:""`
+
+	// VersionFlags defines internal flags for testing new features in the
+	// database.
+	VersionFlags `json:""`
+}
+
+// Region represents a region in the database configuration
+type Region struct {
+	// The data centers in this region.
+	DataCenters []DataCenter `json:"datacenters,omitempty"`
+
+	// The number of satellite logs that we should recruit.
+	SatelliteLogs int `json:"satellite_logs,omitempty"`
+
+	// The replication strategy for satellite logs.
+	SatelliteRedundancyMode string `json:"satellite_redundancy_mode,omitempty"`
+}
+
+// DataCenter represents a data center in the region configuration
+type DataCenter struct {
+	// The ID of the data center. This must match the dcid locality field.
+	ID string `json:"id,omitempty"`
+
+	// The priority of this data center when we have to choose a location.
+	// Higher priorities are preferred over lower priorities.
+	Priority int `json:"priority,omitempty"`
+
+	// Satellite indicates whether the data center is serving as a satellite for
+	// the region. A value of 1 indicates that it is a satellite, and a value of
+	// 0 indicates that it is not a satellite.
+	Satellite int `json:"satellite,omitempty"`
+}
+
+// ContainerOverrides provides options for customizing a container created by
+// the operator.
+type ContainerOverrides struct {
+	// EnableTLS controls whether we should be listening on a TLS connection.
+	EnableTLS bool `json:"enableTls,omitempty"`
+
+	// PeerVerificationRules provides the rules for what client certificates
+	// the process should accept.
+	PeerVerificationRules string `json:"peerVerificationRules,omitempty"`
+}
+
+// LockOptions provides customization for locking global operations.
+type LockOptions struct {
+	// DisableLocks determines whether we should disable locking entirely.
+	DisableLocks *bool `json:"disableLocks,omitempty"`
+
+	// LockKeyPrefix provides a custom prefix for the keys in the database we
+	// use to store locks.
+	LockKeyPrefix string `json:"lockKeyPrefix,omitempty"`
+}
+
+// ServiceConfig allows configuring services that sit in front of our pods.
+type ServiceConfig struct {
+	// Headless determines whether we want to run a headless service for the
+	// cluster.
+	Headless *bool `json:"headless,omitempty"`
+
+	// PublicIPSource specifies where the processes in the cluster should get
+	// their public IPs from. This supports the values `pod` and `service`.
+	//
+	// When this is `service`, the operator creates a ClusterIP service for
+	// each instance, and the processes use the service IP as their public
+	// IP. This allows the processes to keep the same address when their pods
+	// are recreated.
+	//
+	// The default is `pod`.
+	PublicIPSource PublicIPSource `json:"publicIPSource,omitempty"`
+}
+
+// BounceStrategyConfig allows configuring how the operator restarts
+// processes when their configuration changes.
+type BounceStrategyConfig struct {
+	// Type defines the strategy for choosing which processes to bounce
+	// together.
+	//
+	// When this is `all`, the operator bounces all of the processes that need
+	// to be restarted at once. When this is `faultDomain`, the operator
+	// bounces the processes one fault domain at a time, and waits for the
+	// processes to rejoin the cluster and for the cluster to be healthy
+	// before moving on to the next fault domain.
+	//
+	// The default is `all`.
+	Type BounceStrategyType `json:"type,omitempty"`
+
+	// MaxProcessesPerBatch defines the maximum number of processes that the
+	// operator will bounce at once. When the operator has to split a bounce
+	// into multiple batches, it waits for the processes in each batch to
+	// rejoin the cluster and for the cluster to be healthy before bouncing
+	// the next batch.
+	//
+	// If this is 0, there is no limit.
+	MaxProcessesPerBatch int `json:"maxProcessesPerBatch,omitempty"`
+}
+
+// BounceStrategyType describes how the operator chooses which processes to
+// bounce together.
+type BounceStrategyType string
+
+const (
+	// BounceStrategyAll bounces all of the processes at once.
+	BounceStrategyAll BounceStrategyType = "all"
+
+	// BounceStrategyFaultDomain bounces the processes one fault domain at a
+	// time.
+	BounceStrategyFaultDomain BounceStrategyType = "faultDomain"
+)
+
+// CanaryUpgradeConfig allows configuring a set of processes that the
+// operator upgrades first.
+//
+// Canaries are only used for upgrades between protocol-compatible versions,
+// since processes in a cluster cannot run incompatible versions at the same
+// time. Other upgrades will bounce all of the processes at once.
+type CanaryUpgradeConfig struct {
+	// ProcessClasses defines the process classes that the canary processes
+	// can be chosen from.
+	//
+	// If this is empty, processes from any class can be chosen.
+	ProcessClasses []string `json:"processClasses,omitempty"`
+
+	// PodSelector defines a selector for the pods whose processes can be
+	// chosen as canaries.
+	//
+	// If this is empty, processes from any pod can be chosen.
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+
+	// MaxProcesses defines the maximum number of processes that the operator
+	// will upgrade as canaries. The default is 1.
+	MaxProcesses *int `json:"maxProcesses,omitempty"`
+
+	// SoakPeriodSeconds defines how long the cluster must stay healthy with
+	// the canary processes running the new version before the operator
+	// upgrades the rest of the processes. The default is 600.
+	SoakPeriodSeconds *int `json:"soakPeriodSeconds,omitempty"`
+}
+
+// PublicIPSource describes where a process gets its public IP from.
+type PublicIPSource string
+
+const (
+	// PublicIPSourcePod uses the IP of the pod as the public IP.
+	PublicIPSourcePod PublicIPSource = "pod"
+
+	// PublicIPSourceService uses the IP of a service for the instance as the
+	// public IP.
+	PublicIPSourceService PublicIPSource = "service"
+)
+
+// CoordinatorSelectionConfig allows configuring how the operator chooses the
+// processes that serve as coordinators.
+type CoordinatorSelectionConfig struct {
+	// ProcessClasses defines the process classes that can serve as
+	// coordinators, along with their priorities. Processes in classes with a
+	// higher priority will be chosen before processes in classes with a lower
+	// priority.
+	//
+	// If this is empty, all of the stateful process classes will be
+	// eligible, with equal priority.
+	ProcessClasses []CoordinatorSelectionSetting `json:"processClasses,omitempty"`
+
+	// LocalityFields defines the locality fields that the coordinators should
+	// be spread across, in addition to the zone ID and the data center ID.
+	// The operator will never choose more than one coordinator in the same
+	// zone.
+	LocalityFields []string `json:"localityFields,omitempty"`
+
+	// ExcludedPodSelector defines a selector for pods whose processes should
+	// never serve as coordinators.
+	ExcludedPodSelector *metav1.LabelSelector `json:"excludedPodSelector,omitempty"`
+
+	// PreserveHealthyCoordinators determines whether the operator should keep
+	// the current coordinators that are still healthy and eligible when it
+	// changes coordinators, rather than choosing a new set of coordinators
+	// from scratch.
+	PreserveHealthyCoordinators *bool `json:"preserveHealthyCoordinators,omitempty"`
+}
+
+// CoordinatorSelectionSetting defines the priority of a process class for
+// serving as coordinators.
+type CoordinatorSelectionSetting struct {
+	// ProcessClass defines the process class.
+	ProcessClass string `json:"processClass"`
+
+	// Priority defines the priority of the process class. Higher values are
+	// chosen first.
+	Priority int `json:"priority,omitempty"`
+}
+
+// PodDisruptionBudgetConfig allows configuring the pod disruption budget
+// that the operator manages for the cluster.
+type PodDisruptionBudgetConfig struct {
+	// Enabled determines whether the operator should manage a pod disruption
+	// budget for the cluster.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MaxUnavailable provides the number of pods in the cluster, across all
+	// process classes, that can be voluntarily disrupted at once. If this is
+	// not set, it will be derived from the desired fault tolerance and the
+	// fault domain configuration.
+	MaxUnavailable *int `json:"maxUnavailable,omitempty"`
+}
+
+// AutoscalingConfig provides settings for automatically scaling the storage
+// processes based on disk utilization.
+type AutoscalingConfig struct {
+	// Enabled determines whether the operator should change the storage
+	// process count based on disk utilization.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// MinStorageProcesses provides the smallest number of storage processes
+	// the operator will scale down to.
+	MinStorageProcesses int `json:"minStorageProcesses,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// MaxStorageProcesses provides the largest number of storage processes
+	// the operator will scale up to.
+	MaxStorageProcesses int `json:"maxStorageProcesses,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// TargetDiskUtilizationPercent provides the percentage of the disk space
+	// on the storage processes that we want to be in use. The default is 60.
+	TargetDiskUtilizationPercent *int `json:"targetDiskUtilizationPercent,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// TolerancePercent provides how far the disk utilization can drift from
+	// the target, in percentage points, before we change the storage process
+	// count. The default is 10.
+	TolerancePercent *int `json:"tolerancePercent,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// CooldownSeconds provides the minimum time between changes to the
+	// storage process count. The default is 1800.
+	CooldownSeconds *int `json:"cooldownSeconds,omitempty"`
+}
+
+// RequiredAddressSet provides settings for which addresses we need to listen
+// on.
+type RequiredAddressSet struct {
+	// TLS defines whether we need to listen on a TLS address.
+	TLS bool `json:"tls,omitempty"`
+
+	// NonTLS defines whether we need to listen on a non-TLS address.
+	NonTLS bool `json:"nonTLS,omitempty"`
+}
@@ -0,0 +1,1075 @@
+// +build !ignore_autogenerated
+
+/*
+Copyright 2020 FoundationDB project authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutomaticReplacementOptions) DeepCopyInto(out *AutomaticReplacementOptions) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FailureDetectionTimeSeconds != nil {
+		in, out := &in.FailureDetectionTimeSeconds, &out.FailureDetectionTimeSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxConcurrentReplacements != nil {
+		in, out := &in.MaxConcurrentReplacements, &out.MaxConcurrentReplacements
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomaticReplacementOptions.
+func (in *AutomaticReplacementOptions) DeepCopy() *AutomaticReplacementOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(AutomaticReplacementOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingConfig) DeepCopyInto(out *AutoscalingConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TargetDiskUtilizationPercent != nil {
+		in, out := &in.TargetDiskUtilizationPercent, &out.TargetDiskUtilizationPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.TolerancePercent != nil {
+		in, out := &in.TolerancePercent, &out.TolerancePercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.CooldownSeconds != nil {
+		in, out := &in.CooldownSeconds, &out.CooldownSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingConfig.
+func (in *AutoscalingConfig) DeepCopy() *AutoscalingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingStatus) DeepCopyInto(out *AutoscalingStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingStatus.
+func (in *AutoscalingStatus) DeepCopy() *AutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupGenerationStatus) DeepCopyInto(out *BackupGenerationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupGenerationStatus.
+func (in *BackupGenerationStatus) DeepCopy() *BackupGenerationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupGenerationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BounceStatus) DeepCopyInto(out *BounceStatus) {
+	*out = *in
+	if in.CurrentBatch != nil {
+		in, out := &in.CurrentBatch, &out.CurrentBatch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BouncedInstances != nil {
+		in, out := &in.BouncedInstances, &out.BouncedInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BounceStatus.
+func (in *BounceStatus) DeepCopy() *BounceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BounceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BounceStrategyConfig) DeepCopyInto(out *BounceStrategyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BounceStrategyConfig.
+func (in *BounceStrategyConfig) DeepCopy() *BounceStrategyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BounceStrategyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryUpgradeConfig) DeepCopyInto(out *CanaryUpgradeConfig) {
+	*out = *in
+	if in.ProcessClasses != nil {
+		in, out := &in.ProcessClasses, &out.ProcessClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxProcesses != nil {
+		in, out := &in.MaxProcesses, &out.MaxProcesses
+		*out = new(int)
+		**out = **in
+	}
+	if in.SoakPeriodSeconds != nil {
+		in, out := &in.SoakPeriodSeconds, &out.SoakPeriodSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryUpgradeConfig.
+func (in *CanaryUpgradeConfig) DeepCopy() *CanaryUpgradeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryUpgradeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryUpgradeStatus) DeepCopyInto(out *CanaryUpgradeStatus) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryUpgradeStatus.
+func (in *CanaryUpgradeStatus) DeepCopy() *CanaryUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCondition.
+func (in *ClusterCondition) DeepCopy() *ClusterCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterGenerationStatus) DeepCopyInto(out *ClusterGenerationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterGenerationStatus.
+func (in *ClusterGenerationStatus) DeepCopy() *ClusterGenerationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterGenerationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealth) DeepCopyInto(out *ClusterHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealth.
+func (in *ClusterHealth) DeepCopy() *ClusterHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerOverrides) DeepCopyInto(out *ContainerOverrides) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerOverrides.
+func (in *ContainerOverrides) DeepCopy() *ContainerOverrides {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerOverrides)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoordinatorSelectionConfig) DeepCopyInto(out *CoordinatorSelectionConfig) {
+	*out = *in
+	if in.ProcessClasses != nil {
+		in, out := &in.ProcessClasses, &out.ProcessClasses
+		*out = make([]CoordinatorSelectionSetting, len(*in))
+		copy(*out, *in)
+	}
+	if in.LocalityFields != nil {
+		in, out := &in.LocalityFields, &out.LocalityFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedPodSelector != nil {
+		in, out := &in.ExcludedPodSelector, &out.ExcludedPodSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreserveHealthyCoordinators != nil {
+		in, out := &in.PreserveHealthyCoordinators, &out.PreserveHealthyCoordinators
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoordinatorSelectionConfig.
+func (in *CoordinatorSelectionConfig) DeepCopy() *CoordinatorSelectionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CoordinatorSelectionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoordinatorSelectionSetting) DeepCopyInto(out *CoordinatorSelectionSetting) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoordinatorSelectionSetting.
+func (in *CoordinatorSelectionSetting) DeepCopy() *CoordinatorSelectionSetting {
+	if in == nil {
+		return nil
+	}
+	out := new(CoordinatorSelectionSetting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataCenter) DeepCopyInto(out *DataCenter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataCenter.
+func (in *DataCenter) DeepCopy() *DataCenter {
+	if in == nil {
+		return nil
+	}
+	out := new(DataCenter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseConfiguration) DeepCopyInto(out *DatabaseConfiguration) {
+	*out = *in
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]Region, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.RoleCounts = in.RoleCounts
+	out.VersionFlags = in.VersionFlags
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseConfiguration.
+func (in *DatabaseConfiguration) DeepCopy() *DatabaseConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBBackup) DeepCopyInto(out *FoundationDBBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackup.
+func (in *FoundationDBBackup) DeepCopy() *FoundationDBBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBBackupList) DeepCopyInto(out *FoundationDBBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FoundationDBBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackupList.
+func (in *FoundationDBBackupList) DeepCopy() *FoundationDBBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBBackupSpec) DeepCopyInto(out *FoundationDBBackupSpec) {
+	*out = *in
+	if in.AgentCount != nil {
+		in, out := &in.AgentCount, &out.AgentCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.SnapshotPeriodSeconds != nil {
+		in, out := &in.SnapshotPeriodSeconds, &out.SnapshotPeriodSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.BackupDeploymentMetadata != nil {
+		in, out := &in.BackupDeploymentMetadata, &out.BackupDeploymentMetadata
+		*out = new(v1.ObjectMeta)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodTemplateSpec != nil {
+		in, out := &in.PodTemplateSpec, &out.PodTemplateSpec
+		*out = new(corev1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackupSpec.
+func (in *FoundationDBBackupSpec) DeepCopy() *FoundationDBBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBBackupStatus) DeepCopyInto(out *FoundationDBBackupStatus) {
+	*out = *in
+	if in.BackupDetails != nil {
+		in, out := &in.BackupDetails, &out.BackupDetails
+		*out = new(FoundationDBBackupStatusBackupDetails)
+		**out = **in
+	}
+	out.Generations = in.Generations
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackupStatus.
+func (in *FoundationDBBackupStatus) DeepCopy() *FoundationDBBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBBackupStatusBackupDetails) DeepCopyInto(out *FoundationDBBackupStatusBackupDetails) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackupStatusBackupDetails.
+func (in *FoundationDBBackupStatusBackupDetails) DeepCopy() *FoundationDBBackupStatusBackupDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBBackupStatusBackupDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBCluster) DeepCopyInto(out *FoundationDBCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBCluster.
+func (in *FoundationDBCluster) DeepCopy() *FoundationDBCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClusterAutomationOptions) DeepCopyInto(out *FoundationDBClusterAutomationOptions) {
+	*out = *in
+	if in.ConfigureDatabase != nil {
+		in, out := &in.ConfigureDatabase, &out.ConfigureDatabase
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KillProcesses != nil {
+		in, out := &in.KillProcesses, &out.KillProcesses
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeletePods != nil {
+		in, out := &in.DeletePods, &out.DeletePods
+		*out = new(bool)
+		**out = **in
+	}
+	in.Replacements.DeepCopyInto(&out.Replacements)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterAutomationOptions.
+func (in *FoundationDBClusterAutomationOptions) DeepCopy() *FoundationDBClusterAutomationOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClusterAutomationOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClusterFaultDomain) DeepCopyInto(out *FoundationDBClusterFaultDomain) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterFaultDomain.
+func (in *FoundationDBClusterFaultDomain) DeepCopy() *FoundationDBClusterFaultDomain {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClusterFaultDomain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClusterList) DeepCopyInto(out *FoundationDBClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FoundationDBCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterList.
+func (in *FoundationDBClusterList) DeepCopy() *FoundationDBClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClusterSpec) DeepCopyInto(out *FoundationDBClusterSpec) {
+	*out = *in
+	if in.SidecarVersions != nil {
+		in, out := &in.SidecarVersions, &out.SidecarVersions
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.DatabaseConfiguration.DeepCopyInto(&out.DatabaseConfiguration)
+	if in.Processes != nil {
+		in, out := &in.Processes, &out.Processes
+		*out = make(map[string]ProcessSettings, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	out.ProcessCounts = in.ProcessCounts
+	out.FaultDomain = in.FaultDomain
+	if in.InstancesToRemove != nil {
+		in, out := &in.InstancesToRemove, &out.InstancesToRemove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstancesToRemoveWithoutExclusion != nil {
+		in, out := &in.InstancesToRemoveWithoutExclusion, &out.InstancesToRemoveWithoutExclusion
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(corev1.ConfigMap)
+		(*in).DeepCopyInto(*out)
+	}
+	out.MainContainer = in.MainContainer
+	out.SidecarContainer = in.SidecarContainer
+	if in.TrustedCAs != nil {
+		in, out := &in.TrustedCAs, &out.TrustedCAs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SidecarVariables != nil {
+		in, out := &in.SidecarVariables, &out.SidecarVariables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.AutomationOptions.DeepCopyInto(&out.AutomationOptions)
+	out.BounceStrategy = in.BounceStrategy
+	in.CanaryUpgrade.DeepCopyInto(&out.CanaryUpgrade)
+	in.LockOptions.DeepCopyInto(&out.LockOptions)
+	in.Services.DeepCopyInto(&out.Services)
+	if in.PodIPFamily != nil {
+		in, out := &in.PodIPFamily, &out.PodIPFamily
+		*out = new(int)
+		**out = **in
+	}
+	in.PodDisruptionBudgets.DeepCopyInto(&out.PodDisruptionBudgets)
+	in.CoordinatorSelection.DeepCopyInto(&out.CoordinatorSelection)
+	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
+	if in.UseNativeAdminClient != nil {
+		in, out := &in.UseNativeAdminClient, &out.UseNativeAdminClient
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterSpec.
+func (in *FoundationDBClusterSpec) DeepCopy() *FoundationDBClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBClusterStatus) DeepCopyInto(out *FoundationDBClusterStatus) {
+	*out = *in
+	out.ProcessCounts = in.ProcessCounts
+	if in.IncorrectProcesses != nil {
+		in, out := &in.IncorrectProcesses, &out.IncorrectProcesses
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IncorrectPods != nil {
+		in, out := &in.IncorrectPods, &out.IncorrectPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailingPods != nil {
+		in, out := &in.FailingPods, &out.FailingPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MissingProcesses != nil {
+		in, out := &in.MissingProcesses, &out.MissingProcesses
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.DatabaseConfiguration.DeepCopyInto(&out.DatabaseConfiguration)
+	out.Generations = in.Generations
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Health = in.Health
+	out.RequiredAddresses = in.RequiredAddresses
+	if in.PendingRemovals != nil {
+		in, out := &in.PendingRemovals, &out.PendingRemovals
+		*out = make(map[string]PendingRemovalState, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProcessGroups != nil {
+		in, out := &in.ProcessGroups, &out.ProcessGroups
+		*out = make([]ProcessGroupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Autoscaling = in.Autoscaling
+	in.Bounce.DeepCopyInto(&out.Bounce)
+	if in.UpgradeLaggingPods != nil {
+		in, out := &in.UpgradeLaggingPods, &out.UpgradeLaggingPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.CanaryUpgrade.DeepCopyInto(&out.CanaryUpgrade)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterStatus.
+func (in *FoundationDBClusterStatus) DeepCopy() *FoundationDBClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBRestore) DeepCopyInto(out *FoundationDBRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestore.
+func (in *FoundationDBRestore) DeepCopy() *FoundationDBRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBRestoreList) DeepCopyInto(out *FoundationDBRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FoundationDBRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreList.
+func (in *FoundationDBRestoreList) DeepCopy() *FoundationDBRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBRestoreSpec) DeepCopyInto(out *FoundationDBRestoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreSpec.
+func (in *FoundationDBRestoreSpec) DeepCopy() *FoundationDBRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBRestoreStatus) DeepCopyInto(out *FoundationDBRestoreStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreStatus.
+func (in *FoundationDBRestoreStatus) DeepCopy() *FoundationDBRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockOptions) DeepCopyInto(out *LockOptions) {
+	*out = *in
+	if in.DisableLocks != nil {
+		in, out := &in.DisableLocks, &out.DisableLocks
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockOptions.
+func (in *LockOptions) DeepCopy() *LockOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(LockOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingRemovalState) DeepCopyInto(out *PendingRemovalState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingRemovalState.
+func (in *PendingRemovalState) DeepCopy() *PendingRemovalState {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingRemovalState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetConfig) DeepCopyInto(out *PodDisruptionBudgetConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetConfig.
+func (in *PodDisruptionBudgetConfig) DeepCopy() *PodDisruptionBudgetConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessCounts) DeepCopyInto(out *ProcessCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessCounts.
+func (in *ProcessCounts) DeepCopy() *ProcessCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessGroupCondition) DeepCopyInto(out *ProcessGroupCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupCondition.
+func (in *ProcessGroupCondition) DeepCopy() *ProcessGroupCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessGroupCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessGroupStatus) DeepCopyInto(out *ProcessGroupStatus) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ProcessGroupCondition, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupStatus.
+func (in *ProcessGroupStatus) DeepCopy() *ProcessGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessSettings) DeepCopyInto(out *ProcessSettings) {
+	*out = *in
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(corev1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeClaimTemplate != nil {
+		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
+		*out = new(corev1.PersistentVolumeClaim)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomParameters != nil {
+		in, out := &in.CustomParameters, &out.CustomParameters
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessSettings.
+func (in *ProcessSettings) DeepCopy() *ProcessSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Region) DeepCopyInto(out *Region) {
+	*out = *in
+	if in.DataCenters != nil {
+		in, out := &in.DataCenters, &out.DataCenters
+		*out = make([]DataCenter, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Region.
+func (in *Region) DeepCopy() *Region {
+	if in == nil {
+		return nil
+	}
+	out := new(Region)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequiredAddressSet) DeepCopyInto(out *RequiredAddressSet) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredAddressSet.
+func (in *RequiredAddressSet) DeepCopy() *RequiredAddressSet {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiredAddressSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleCounts) DeepCopyInto(out *RoleCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleCounts.
+func (in *RoleCounts) DeepCopy() *RoleCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceConfig) DeepCopyInto(out *ServiceConfig) {
+	*out = *in
+	if in.Headless != nil {
+		in, out := &in.Headless, &out.Headless
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceConfig.
+func (in *ServiceConfig) DeepCopy() *ServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionFlags) DeepCopyInto(out *VersionFlags) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionFlags.
+func (in *VersionFlags) DeepCopy() *VersionFlags {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionFlags)
+	in.DeepCopyInto(out)
+	return out
+}
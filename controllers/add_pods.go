@@ -149,6 +149,11 @@ func (a AddPods) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context
 					return false, err
 				}
 
+				err = createInstanceService(r, context, cluster, processClass, idNum)
+				if err != nil {
+					return false, err
+				}
+
 				pod, err := GetPod(context, cluster, processClass, idNum, r)
 				if err != nil {
 					return false, err
@@ -161,6 +166,7 @@ func (a AddPods) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context
 				if err != nil {
 					return false, err
 				}
+				r.getAdminClientSession(cluster).InvalidateStatus()
 
 				if instanceIDs[processClass] == nil {
 					instanceIDs[processClass] = make(map[int]bool)
@@ -188,6 +194,11 @@ func (a AddPods) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context
 					idNum++
 				}
 
+				err = createInstanceService(r, context, cluster, processClass, idNum)
+				if err != nil {
+					return false, err
+				}
+
 				pvc, err := GetPvc(cluster, processClass, idNum)
 				if err != nil {
 					return false, err
@@ -216,6 +227,7 @@ func (a AddPods) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context
 				if err != nil {
 					return false, err
 				}
+				r.getAdminClientSession(cluster).InvalidateStatus()
 
 				addedCount++
 				idNum++
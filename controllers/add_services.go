@@ -26,6 +26,7 @@ import (
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -39,23 +40,46 @@ func (a AddServices) Reconcile(r *FoundationDBClusterReconciler, context ctx.Con
 		return false, err
 	}
 
-	if service == nil {
+	if service != nil {
+		existingServices := &corev1.ServiceList{}
+		err = r.List(context, existingServices, client.InNamespace(cluster.Namespace), client.MatchingField("metadata.name", service.Name))
+		if err != nil {
+			return false, err
+		}
+
+		if len(existingServices.Items) == 0 {
+			owner := buildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)
+			if err != nil {
+				return false, err
+			}
+			service.ObjectMeta.OwnerReferences = owner
+			err = r.Create(context, service)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if cluster.GetPublicIPSource() != fdbtypes.PublicIPSourceService {
 		return true, nil
 	}
 
-	existingServices := &corev1.ServiceList{}
-	err = r.List(context, existingServices, client.InNamespace(cluster.Namespace), client.MatchingField("metadata.name", service.Name))
+	instances, err := r.PodLifecycleManager.GetInstances(r, cluster, context, getPodListOptions(cluster, "", "")...)
 	if err != nil {
 		return false, err
 	}
 
-	if len(existingServices.Items) == 0 {
-		owner := buildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)
+	for _, instance := range instances {
+		if cluster.InstanceIsBeingRemoved(instance.GetInstanceID()) {
+			continue
+		}
+
+		_, idNum, err := ParseInstanceID(instance.GetInstanceID())
 		if err != nil {
 			return false, err
 		}
-		service.ObjectMeta.OwnerReferences = owner
-		err = r.Create(context, service)
+
+		err = createInstanceService(r, context, cluster, instance.GetProcessClass(), idNum)
 		if err != nil {
 			return false, err
 		}
@@ -64,6 +88,36 @@ func (a AddServices) Reconcile(r *FoundationDBClusterReconciler, context ctx.Con
 	return true, nil
 }
 
+// createInstanceService creates the service that provides the public IP for
+// an instance, if the service does not already exist.
+func createInstanceService(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster, processClass string, idNum int) error {
+	service, err := GetService(cluster, processClass, idNum)
+	if err != nil {
+		return err
+	}
+
+	if service == nil {
+		return nil
+	}
+
+	existingService := &corev1.Service{}
+	err = r.Get(context, client.ObjectKey{Namespace: cluster.Namespace, Name: service.Name}, existingService)
+	if err == nil {
+		return nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	log.Info("Creating service", "namespace", cluster.Namespace, "cluster", cluster.Name, "name", service.Name)
+	service.ObjectMeta.OwnerReferences = buildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)
+	err = r.Create(context, service)
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
 // RequeueAfter returns the delay before we should run the reconciliation
 // again.
 func (a AddServices) RequeueAfter() time.Duration {
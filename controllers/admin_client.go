@@ -81,6 +81,14 @@ type AdminClient interface {
 	// KillProcesses restarts processes
 	KillInstances(addresses []string) error
 
+	// SetMaintenanceZone places a zone into maintenance mode, so that the
+	// database does not start healing when the processes in that zone go
+	// down.
+	SetMaintenanceZone(zone string, timeoutSeconds int) error
+
+	// ResetMaintenanceMode takes the database out of maintenance mode.
+	ResetMaintenanceMode() error
+
 	// ChangeCoordinators changes the coordinator set
 	ChangeCoordinators(addresses []string) (string, error)
 
@@ -194,6 +202,19 @@ func (command cliCommand) getClusterFileFlag() string {
 	return "-C"
 }
 
+// getDescription gets a short description of the command for use in error
+// messages.
+func (command cliCommand) getDescription() string {
+	binary := command.binary
+	if binary == "" {
+		binary = "fdbcli"
+	}
+	if len(command.args) > 0 {
+		return fmt.Sprintf("%s %s", binary, strings.Join(command.args, " "))
+	}
+	return fmt.Sprintf("%s --exec '%s'", binary, command.command)
+}
+
 // getBinaryPath generates the path to an FDB binary.
 func getBinaryPath(binaryName string, version string) string {
 
@@ -246,9 +267,18 @@ func (client *CliAdminClient) runCommand(command cliCommand) (string, error) {
 
 	output, err := execCommand.Output()
 	if err != nil {
+		if timeoutContext.Err() == context.DeadlineExceeded {
+			return "", AdminClientError{Reason: AdminClientTimeout, Command: command.getDescription(), Cause: err}
+		}
 		exitError, canCast := err.(*exec.ExitError)
 		if canCast {
 			log.Error(exitError, "Error from FDB command", "namespace", client.Cluster.Namespace, "cluster", client.Cluster.Name, "code", exitError.ProcessState.ExitCode(), "stdout", string(output), "stderr", string(exitError.Stderr))
+			return "", AdminClientError{
+				Reason:  classifyCommandFailure(string(output) + string(exitError.Stderr)),
+				Command: command.getDescription(),
+				Message: strings.TrimSpace(string(exitError.Stderr)),
+				Cause:   err,
+			}
 		}
 		return "", err
 	}
@@ -272,7 +302,7 @@ func (client *CliAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error)
 	status := &fdbtypes.FoundationDBStatus{}
 	err = json.Unmarshal([]byte(statusString), &status)
 	if err != nil {
-		return nil, err
+		return nil, AdminClientError{Reason: AdminClientUnparseableOutput, Command: "status json", Cause: err}
 	}
 	return status, nil
 }
@@ -292,13 +322,49 @@ func (client *CliAdminClient) ConfigureDatabase(configuration fdbtypes.DatabaseC
 	return err
 }
 
+// localityInstanceIDExclusionPrefix is the prefix for the argument to the
+// exclude command that excludes processes by instance ID.
+const localityInstanceIDExclusionPrefix = "locality_instance_id:"
+
+// isLocalityExclusion determines whether an argument to the exclude command
+// refers to a locality rather than an address.
+func isLocalityExclusion(address string) bool {
+	return strings.HasPrefix(address, "locality_")
+}
+
+// getLocalityExclusionAddresses gets the addresses of the processes that
+// match a locality exclusion, without any flags.
+func getLocalityExclusionAddresses(status *fdbtypes.FoundationDBStatus, locality string) []string {
+	components := strings.SplitN(strings.TrimPrefix(locality, "locality_"), ":", 2)
+	if len(components) < 2 {
+		return nil
+	}
+
+	var addresses []string
+	for _, process := range status.Cluster.Processes {
+		if process.Locality[components[0]] == components[1] {
+			addresses = append(addresses, removeAddressFlags([]string{process.Address})[0])
+		}
+	}
+	return addresses
+}
+
 // removeAddressFlags strips the flags from the end of the addresses, leaving
-// only the IP and port.
+// only the IP and port. Locality exclusions are returned unchanged.
 func removeAddressFlags(addresses []string) []string {
 	results := make([]string, 0, len(addresses))
 	for _, address := range addresses {
-		components := strings.Split(address, ":")
-		results = append(results, fmt.Sprintf("%s:%s", components[0], components[1]))
+		if isLocalityExclusion(address) {
+			results = append(results, address)
+			continue
+		}
+		parsed, err := fdbtypes.ParseProcessAddress(address)
+		if err != nil {
+			results = append(results, address)
+			continue
+		}
+		parsed.Flags = nil
+		results = append(results, parsed.String())
 	}
 	return results
 }
@@ -366,9 +432,24 @@ func (client *CliAdminClient) CanSafelyRemove(addresses []string) ([]string, err
 		}
 		exclusionResults := parseExclusionOutput(output)
 		log.Info("Checking exclusion results", "namespace", client.Cluster.Namespace, "cluster", client.Cluster.Name, "addresses", addresses, "results", exclusionResults)
+
+		var status *fdbtypes.FoundationDBStatus
 		remaining := make([]string, 0, len(addressesWithoutFlags))
 		for _, address := range addressesWithoutFlags {
-			if exclusionResults[address] != "Success" && exclusionResults[address] != "Missing" {
+			if isLocalityExclusion(address) {
+				if status == nil {
+					status, err = client.GetStatus()
+					if err != nil {
+						return nil, err
+					}
+				}
+				for _, processAddress := range getLocalityExclusionAddresses(status, address) {
+					if exclusionResults[processAddress] != "Success" && exclusionResults[processAddress] != "Missing" {
+						remaining = append(remaining, address)
+						break
+					}
+				}
+			} else if exclusionResults[address] != "Success" && exclusionResults[address] != "Missing" {
 				remaining = append(remaining, address)
 			}
 		}
@@ -385,10 +466,10 @@ func (client *CliAdminClient) CanSafelyRemove(addresses []string) ([]string, err
 // the output of an exclusion command.
 func parseExclusionOutput(output string) map[string]string {
 	results := make(map[string]string)
-	var regex = regexp.MustCompile(`\s*([\w.:]+)\s*-+(.*)`)
+	var regex = regexp.MustCompile(`\s*([\w.:\[\]]+)\s*-+(.*)`)
 	matches := regex.FindAllStringSubmatch(output, -1)
 	for _, match := range matches {
-		address := match[1]
+		address := normalizeAddress(match[1])
 		status := match[2]
 		if strings.Contains(status, "Successfully excluded") {
 			results[address] = "Success"
@@ -416,6 +497,22 @@ func (client *CliAdminClient) KillInstances(addresses []string) error {
 	return err
 }
 
+// SetMaintenanceZone places a zone into maintenance mode.
+func (client *CliAdminClient) SetMaintenanceZone(zone string, timeoutSeconds int) error {
+	_, err := client.runCommand(cliCommand{command: fmt.Sprintf(
+		"maintenance on %s %d",
+		zone,
+		timeoutSeconds,
+	)})
+	return err
+}
+
+// ResetMaintenanceMode takes the database out of maintenance mode.
+func (client *CliAdminClient) ResetMaintenanceMode() error {
+	_, err := client.runCommand(cliCommand{command: "maintenance off"})
+	return err
+}
+
 // ChangeCoordinators changes the coordinator set
 func (client *CliAdminClient) ChangeCoordinators(addresses []string) (string, error) {
 	_, err := client.runCommand(cliCommand{command: fmt.Sprintf(
@@ -433,7 +530,7 @@ func (client *CliAdminClient) ChangeCoordinators(addresses []string) (string, er
 
 	connectionString, err := fdbtypes.ParseConnectionString(string(connectionStringBytes))
 	if err != nil {
-		return "", err
+		return "", AdminClientError{Reason: AdminClientUnparseableOutput, Command: "coordinators", Message: "Invalid cluster file", Cause: err}
 	}
 	return connectionString.String(), nil
 }
@@ -446,7 +543,7 @@ func (client *CliAdminClient) GetConnectionString() (string, error) {
 	}
 
 	if !strings.Contains(output, "The database is available") {
-		return "", fmt.Errorf("Unable to fetch connection string: %s", output)
+		return "", AdminClientError{Reason: AdminClientClusterUnreachable, Command: "status minimal", Message: fmt.Sprintf("Unable to fetch connection string: %s", output)}
 	}
 
 	connectionStringBytes, err := ioutil.ReadFile(client.clusterFilePath)
@@ -456,7 +553,7 @@ func (client *CliAdminClient) GetConnectionString() (string, error) {
 
 	connectionString, err := fdbtypes.ParseConnectionString(string(connectionStringBytes))
 	if err != nil {
-		return "", err
+		return "", AdminClientError{Reason: AdminClientUnparseableOutput, Command: "status minimal", Message: "Invalid cluster file", Cause: err}
 	}
 	return connectionString.String(), nil
 }
@@ -469,7 +566,7 @@ func (client *CliAdminClient) VersionSupported(versionString string) (bool, erro
 		return false, err
 	}
 
-	if !version.IsAtLeast(MinimumFDBVersion()) {
+	if !version.IsSupported() {
 		return false, nil
 	}
 
@@ -494,7 +591,11 @@ func (client *CliAdminClient) GetProtocolVersion(version string) (string, error)
 	protocolVersionMatch := protocolVersionRegex.FindStringSubmatch(output)
 
 	if protocolVersionMatch == nil || len(protocolVersionMatch) < 2 {
-		return "", fmt.Errorf("Failed to parse protocol version for %s. Version output:\n%s", version, output)
+		return "", AdminClientError{
+			Reason:  AdminClientUnparseableOutput,
+			Command: "fdbcli --version",
+			Message: fmt.Sprintf("Failed to parse protocol version for %s. Version output:\n%s", version, output),
+		}
 	}
 
 	return protocolVersionMatch[1], nil
@@ -579,7 +680,7 @@ func (client *CliAdminClient) GetBackupStatus() (*fdbtypes.FoundationDBLiveBacku
 	status := &fdbtypes.FoundationDBLiveBackupStatus{}
 	err = json.Unmarshal([]byte(statusString), &status)
 	if err != nil {
-		return nil, err
+		return nil, AdminClientError{Reason: AdminClientUnparseableOutput, Command: "fdbbackup status --json", Cause: err}
 	}
 
 	return status, nil
@@ -618,6 +719,12 @@ func (client *CliAdminClient) Close() error {
 }
 
 // MockAdminClient provides a mock implementation of the cluster admin interface
+//
+// The mock simulates a database based on the pods in the cluster. By default,
+// every operation completes immediately and the database is always healthy.
+// The simulation fields allow tests to make the database behave more like a
+// real cluster, with exclusions that take time to complete, processes that
+// go missing, unreachable coordinators, and recoveries.
 type MockAdminClient struct {
 	Cluster               *fdbtypes.FoundationDBCluster
 	KubeClient            client.Client
@@ -629,26 +736,131 @@ type MockAdminClient struct {
 	Backups               map[string]fdbtypes.FoundationDBBackupStatusBackupDetails
 	restoreURL            string
 	clientVersions        map[string][]string
+
+	// ExclusionDrainPolls is the number of status polls it takes to move the
+	// data off of a newly excluded process. Calls to GetStatus and
+	// CanSafelyRemove both count as polls.
+	ExclusionDrainPolls int
+
+	// RecoveryPolls is the number of calls to GetStatus that will report the
+	// database as unavailable after a recovery.
+	RecoveryPolls int
+
+	// MissingProcessGroups provides the instance IDs of processes that
+	// should be left out of the status, as if the processes had stopped
+	// reporting to the cluster controller.
+	MissingProcessGroups map[string]bool
+
+	// UnreachableCoordinators provides the addresses of coordinators that
+	// should be reported as unreachable.
+	UnreachableCoordinators map[string]bool
+
+	// FaultTolerance provides the number of fault domains that the database
+	// should report that it can lose. If this is nil, the database will
+	// report the desired fault tolerance for the cluster.
+	FaultTolerance *int
+
+	// DiskUtilizationPercent provides the percentage of the disk space that
+	// the processes should report as in use.
+	DiskUtilizationPercent int
+
+	// remainingDrainPolls tracks the number of polls left before each
+	// excluded address has finished moving its data.
+	remainingDrainPolls map[string]int
+
+	// remainingRecoveryPolls tracks the number of polls left before the
+	// database finishes its current recovery.
+	remainingRecoveryPolls int
+
+	// faults provides the faults that have been injected into the client,
+	// indexed by method name.
+	faults map[string]*MockFault
+
+	// mockConnectionString provides a connection string that will be
+	// returned by GetConnectionString in place of the real value.
+	mockConnectionString string
+
+	// MaintenanceZone provides the zone that is currently in maintenance
+	// mode.
+	MaintenanceZone string
+
+	// MaintenanceZoneHistory provides the zones that have been put into
+	// maintenance mode, in the order they were set.
+	MaintenanceZoneHistory []string
+}
+
+// MockFault describes a failure that a mock client should simulate in calls
+// to one of its methods.
+type MockFault struct {
+	// Error provides the error that the call should return.
+	Error error
+
+	// Delay provides an amount of time that the call should wait before
+	// returning. This can be used to simulate calls that hang.
+	Delay time.Duration
+
+	// Count provides the number of calls that should fail. If this is 0,
+	// every call will fail until the fault is cleared.
+	Count int
 }
 
+// applyMockFault simulates the fault for a method, if one has been injected.
+//
+// This will return the error from the fault, or nil if there is no fault for
+// the method.
+func applyMockFault(faults map[string]*MockFault, method string) error {
+	fault, present := faults[method]
+	if !present {
+		return nil
+	}
+
+	if fault.Count > 0 {
+		fault.Count--
+		if fault.Count == 0 {
+			delete(faults, method)
+		}
+	}
+
+	if fault.Delay > 0 {
+		time.Sleep(fault.Delay)
+	}
+	return fault.Error
+}
+
+// mockBytesPerExclusionPoll is the amount of data the mock admin client
+// reports as moving for each remaining poll in an exclusion.
+const mockBytesPerExclusionPoll = 1024 * 1024
+
+// mockDiskBytes is the disk size the mock admin client reports for each
+// process.
+const mockDiskBytes = 100 * 1024 * 1024 * 1024
+
 // adminClientCache provides a cache of mock admin clients.
 var adminClientCache = make(map[string]*MockAdminClient)
 
 // NewMockAdminClient creates an admin client for a cluster.
 func NewMockAdminClient(cluster *fdbtypes.FoundationDBCluster, kubeClient client.Client) (AdminClient, error) {
-	return newMockAdminClientUncast(cluster, kubeClient)
+	return NewMockAdminClientUncast(cluster, kubeClient)
 }
 
-// newMockAdminClientUncast creates a mock admin client for a cluster.
+// NewMockAdminClientUncast creates a mock admin client for a cluster, and
+// returns it as a MockAdminClient so that tests can configure the
+// simulation.
+//
+// Clients are cached by cluster name, so repeated calls for the same cluster
+// will return the same client until ClearMockAdminClients is called.
 // nolint:unparam
 // is required because we always return a nil error
-func newMockAdminClientUncast(cluster *fdbtypes.FoundationDBCluster, kubeClient client.Client) (*MockAdminClient, error) {
+func NewMockAdminClientUncast(cluster *fdbtypes.FoundationDBCluster, kubeClient client.Client) (*MockAdminClient, error) {
 	client := adminClientCache[cluster.Name]
 	if client == nil {
 		client = &MockAdminClient{
-			Cluster:             cluster,
-			KubeClient:          kubeClient,
-			ReincludedAddresses: make(map[string]bool),
+			Cluster:                 cluster,
+			KubeClient:              kubeClient,
+			ReincludedAddresses:     make(map[string]bool),
+			MissingProcessGroups:    make(map[string]bool),
+			UnreachableCoordinators: make(map[string]bool),
+			remainingDrainPolls:     make(map[string]int),
 		}
 		adminClientCache[cluster.Name] = client
 		client.Backups = make(map[string]fdbtypes.FoundationDBBackupStatusBackupDetails)
@@ -665,11 +877,21 @@ func ClearMockAdminClients() {
 
 // GetStatus gets the database's status
 func (client *MockAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error) {
+	err := applyMockFault(client.faults, "GetStatus")
+	if err != nil {
+		return nil, err
+	}
 	if client.frozenStatus != nil {
 		return client.frozenStatus, nil
 	}
+	client.advanceExclusions()
+	recovering := client.remainingRecoveryPolls > 0
+	if recovering {
+		client.remainingRecoveryPolls--
+	}
+
 	pods := &corev1.PodList{}
-	err := client.KubeClient.List(context.TODO(), pods)
+	err = client.KubeClient.List(context.TODO(), pods)
 	if err != nil {
 		return nil, err
 	}
@@ -697,27 +919,38 @@ func (client *MockAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error)
 	}
 
 	for _, pod := range pods.Items {
-		ip := MockPodIP(&pod)
+		instance := newFdbInstance(pod)
+		if pod.ObjectMeta.DeletionTimestamp != nil || client.MissingProcessGroups[instance.GetInstanceID()] {
+			continue
+		}
+
+		ip := mockPublicIP(&pod)
 		podClient := &mockFdbPodClient{Cluster: client.Cluster, Pod: &pod}
 		fullAddress := client.Cluster.GetFullAddress(ip)
 
+		localityExclusion := localityInstanceIDExclusionPrefix + instance.GetInstanceID()
 		_, ipExcluded := exclusionMap[ip]
 		_, addressExcluded := exclusionMap[fullAddress]
-		excluded := ipExcluded || addressExcluded
+		_, localityExcluded := exclusionMap[localityExclusion]
+		excluded := ipExcluded || addressExcluded || localityExcluded
 		_, isCoordinator := coordinators[fullAddress]
-		if isCoordinator && !excluded {
+		if isCoordinator && !excluded && !client.UnreachableCoordinators[fullAddress] {
 			coordinators[fullAddress] = true
 		}
-		instance := newFdbInstance(pod)
 		command, err := GetStartCommand(client.Cluster, instance, podClient)
 		if err != nil {
 			return nil, err
 		}
+		processClass := GetProcessClassFromMeta(pod.ObjectMeta)
+		var roles []fdbtypes.FoundationDBStatusProcessRoleInfo
+		if excluded && (client.getRemainingDrainPolls(fullAddress) > 0 || client.getRemainingDrainPolls(localityExclusion) > 0) {
+			roles = []fdbtypes.FoundationDBStatusProcessRoleInfo{{Role: processClass}}
+		}
 		status.Cluster.Processes[pod.Name] = fdbtypes.FoundationDBStatusProcessInfo{
 			Address:      fullAddress,
-			ProcessClass: GetProcessClassFromMeta(pod.ObjectMeta),
+			ProcessClass: processClass,
 			CommandLine:  command,
-			Excluded:     ipExcluded || addressExcluded,
+			Excluded:     excluded,
 			Locality: map[string]string{
 				"instance_id": instance.GetInstanceID(),
 				"zoneid":      pod.Name,
@@ -725,6 +958,11 @@ func (client *MockAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error)
 			},
 			Version:       client.Cluster.Status.RunningVersion,
 			UptimeSeconds: 60000,
+			Disk: fdbtypes.FoundationDBStatusProcessDiskInfo{
+				FreeBytes:  mockDiskBytes - mockDiskBytes*client.DiskUtilizationPercent/100,
+				TotalBytes: mockDiskBytes,
+			},
+			Roles: roles,
 		}
 	}
 
@@ -759,8 +997,8 @@ func (client *MockAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error)
 		})
 	}
 
-	status.Client.DatabaseStatus.Available = true
-	status.Client.DatabaseStatus.Healthy = true
+	status.Client.DatabaseStatus.Available = !recovering
+	status.Client.DatabaseStatus.Healthy = !recovering
 
 	if client.DatabaseConfiguration == nil {
 		status.Cluster.Layers.Error = "configurationMissing"
@@ -773,6 +1011,23 @@ func (client *MockAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error)
 	}
 
 	status.Cluster.FullReplication = true
+	status.Cluster.MaintenanceZone = client.MaintenanceZone
+
+	faultTolerance := client.Cluster.DesiredFaultTolerance()
+	if client.FaultTolerance != nil {
+		faultTolerance = *client.FaultTolerance
+	}
+	status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingData = faultTolerance
+	status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingAvailability = faultTolerance
+
+	movingBytes := 0
+	for _, polls := range client.remainingDrainPolls {
+		movingBytes += polls * mockBytesPerExclusionPoll
+	}
+	if movingBytes > 0 {
+		status.Cluster.Data.MovingData.InFlightBytes = movingBytes
+		status.Cluster.Data.MovingData.HighestPriority = 1
+	}
 
 	if len(client.Backups) > 0 {
 		status.Cluster.Layers.Backup.Tags = make(map[string]fdbtypes.FoundationDBStatusBackupTag, len(client.Backups))
@@ -791,6 +1046,10 @@ func (client *MockAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error)
 
 // ConfigureDatabase changes the database configuration
 func (client *MockAdminClient) ConfigureDatabase(configuration fdbtypes.DatabaseConfiguration, newDatabase bool) error {
+	err := applyMockFault(client.faults, "ConfigureDatabase")
+	if err != nil {
+		return err
+	}
 	client.DatabaseConfiguration = configuration.DeepCopy()
 	return nil
 }
@@ -798,12 +1057,16 @@ func (client *MockAdminClient) ConfigureDatabase(configuration fdbtypes.Database
 // ExcludeInstances starts evacuating processes so that they can be removed
 // from the database.
 func (client *MockAdminClient) ExcludeInstances(addresses []string) error {
+	err := applyMockFault(client.faults, "ExcludeInstances")
+	if err != nil {
+		return err
+	}
 	count := len(addresses) + len(client.ExcludedAddresses)
 	exclusionMap := make(map[string]bool, count)
 	newExclusions := make([]string, 0, count)
 	for _, address := range addresses {
-		if !isValidAddress(address) {
-			return fmt.Errorf("Invalid exclusion address %s", address)
+		if !isValidAddress(address) && !isLocalityExclusion(address) {
+			return AdminClientError{Reason: AdminClientCommandRejected, Command: "exclude", Message: fmt.Sprintf("Invalid exclusion address %s", address)}
 		}
 
 		if !exclusionMap[address] {
@@ -811,6 +1074,15 @@ func (client *MockAdminClient) ExcludeInstances(addresses []string) error {
 			newExclusions = append(newExclusions, address)
 		}
 	}
+	if client.remainingDrainPolls == nil {
+		client.remainingDrainPolls = make(map[string]int)
+	}
+	for _, address := range addresses {
+		_, alreadyExcluded := client.remainingDrainPolls[address]
+		if !alreadyExcluded && client.ExclusionDrainPolls > 0 {
+			client.remainingDrainPolls[address] = client.ExclusionDrainPolls
+		}
+	}
 	for _, address := range client.ExcludedAddresses {
 		if !exclusionMap[address] {
 			exclusionMap[address] = true
@@ -838,10 +1110,14 @@ func isValidAddress(address string) bool {
 // IncludeInstances removes processes from the exclusion list and allows
 // them to take on roles again.
 func (client *MockAdminClient) IncludeInstances(addresses []string) error {
+	err := applyMockFault(client.faults, "IncludeInstances")
+	if err != nil {
+		return err
+	}
 	newExclusions := make([]string, 0, len(client.ExcludedAddresses))
 	for _, address := range addresses {
-		if !isValidAddress(address) {
-			return fmt.Errorf("Invalid exclusion address %s", address)
+		if !isValidAddress(address) && !isLocalityExclusion(address) {
+			return AdminClientError{Reason: AdminClientCommandRejected, Command: "include", Message: fmt.Sprintf("Invalid exclusion address %s", address)}
 		}
 	}
 	for _, excludedAddress := range client.ExcludedAddresses {
@@ -850,6 +1126,7 @@ func (client *MockAdminClient) IncludeInstances(addresses []string) error {
 			if address == excludedAddress {
 				included = true
 				client.ReincludedAddresses[address] = true
+				delete(client.remainingDrainPolls, address)
 				break
 			}
 		}
@@ -870,18 +1147,62 @@ func (client *MockAdminClient) IncludeInstances(addresses []string) error {
 // The list returned by this method will be the addresses that are *not*
 // safe to remove.
 func (client *MockAdminClient) CanSafelyRemove(addresses []string) ([]string, error) {
-	return nil, nil
+	err := applyMockFault(client.faults, "CanSafelyRemove")
+	if err != nil {
+		return nil, err
+	}
+	client.advanceExclusions()
+
+	var remaining []string
+	for _, address := range addresses {
+		if client.getRemainingDrainPolls(address) > 0 {
+			remaining = append(remaining, address)
+		}
+	}
+	return remaining, nil
 }
 
 // KillInstances restarts processes
 func (client *MockAdminClient) KillInstances(addresses []string) error {
+	err := applyMockFault(client.faults, "KillInstances")
+	if err != nil {
+		return err
+	}
 	client.KilledAddresses = append(client.KilledAddresses, addresses...)
 	client.UnfreezeStatus()
+	client.TriggerRecovery()
+	return nil
+}
+
+// SetMaintenanceZone places a zone into maintenance mode.
+func (client *MockAdminClient) SetMaintenanceZone(zone string, timeoutSeconds int) error {
+	err := applyMockFault(client.faults, "SetMaintenanceZone")
+	if err != nil {
+		return err
+	}
+	client.MaintenanceZone = zone
+	client.MaintenanceZoneHistory = append(client.MaintenanceZoneHistory, zone)
+	client.UnfreezeStatus()
+	return nil
+}
+
+// ResetMaintenanceMode takes the database out of maintenance mode.
+func (client *MockAdminClient) ResetMaintenanceMode() error {
+	err := applyMockFault(client.faults, "ResetMaintenanceMode")
+	if err != nil {
+		return err
+	}
+	client.MaintenanceZone = ""
+	client.UnfreezeStatus()
 	return nil
 }
 
 // ChangeCoordinators changes the coordinator set
 func (client *MockAdminClient) ChangeCoordinators(addresses []string) (string, error) {
+	err := applyMockFault(client.faults, "ChangeCoordinators")
+	if err != nil {
+		return "", err
+	}
 	connectionString, err := fdbtypes.ParseConnectionString(client.Cluster.Status.ConnectionString)
 	if err != nil {
 		return "", err
@@ -891,23 +1212,36 @@ func (client *MockAdminClient) ChangeCoordinators(addresses []string) (string, e
 		return "", err
 	}
 	connectionString.Coordinators = addresses
+	client.TriggerRecovery()
 	return connectionString.String(), err
 }
 
 // GetConnectionString fetches the latest connection string.
 func (client *MockAdminClient) GetConnectionString() (string, error) {
+	err := applyMockFault(client.faults, "GetConnectionString")
+	if err != nil {
+		return "", err
+	}
+	if client.mockConnectionString != "" {
+		return client.mockConnectionString, nil
+	}
 	return client.Cluster.Status.ConnectionString, nil
 }
 
 // VersionSupported reports whether we can support a cluster with a given
 // version.
 func (client *MockAdminClient) VersionSupported(versionString string) (bool, error) {
+	err := applyMockFault(client.faults, "VersionSupported")
+	if err != nil {
+		return false, err
+	}
+
 	version, err := fdbtypes.ParseFdbVersion(versionString)
 	if err != nil {
 		return false, err
 	}
 
-	if !version.IsAtLeast(MinimumFDBVersion()) {
+	if !version.IsSupported() {
 		return false, nil
 	}
 
@@ -917,11 +1251,19 @@ func (client *MockAdminClient) VersionSupported(versionString string) (bool, err
 // GetProtocolVersion determines the protocol version that is used by a
 // version of FDB.
 func (client *MockAdminClient) GetProtocolVersion(version string) (string, error) {
+	err := applyMockFault(client.faults, "GetProtocolVersion")
+	if err != nil {
+		return "", err
+	}
 	return version, nil
 }
 
 // StartBackup starts a new backup.
 func (client *MockAdminClient) StartBackup(url string, snapshotPeriodSeconds int) error {
+	err := applyMockFault(client.faults, "StartBackup")
+	if err != nil {
+		return err
+	}
 	client.Backups["default"] = fdbtypes.FoundationDBBackupStatusBackupDetails{
 		URL:                   url,
 		Running:               true,
@@ -932,6 +1274,10 @@ func (client *MockAdminClient) StartBackup(url string, snapshotPeriodSeconds int
 
 // PauseBackups pauses backups.
 func (client *MockAdminClient) PauseBackups() error {
+	err := applyMockFault(client.faults, "PauseBackups")
+	if err != nil {
+		return err
+	}
 	for tag, backup := range client.Backups {
 		backup.Paused = true
 		client.Backups[tag] = backup
@@ -941,6 +1287,10 @@ func (client *MockAdminClient) PauseBackups() error {
 
 // ResumeBackups resumes backups.
 func (client *MockAdminClient) ResumeBackups() error {
+	err := applyMockFault(client.faults, "ResumeBackups")
+	if err != nil {
+		return err
+	}
 	for tag, backup := range client.Backups {
 		backup.Paused = false
 		client.Backups[tag] = backup
@@ -950,6 +1300,10 @@ func (client *MockAdminClient) ResumeBackups() error {
 
 // ModifyBackup reconfigures the backup.
 func (client *MockAdminClient) ModifyBackup(snapshotPeriodSeconds int) error {
+	err := applyMockFault(client.faults, "ModifyBackup")
+	if err != nil {
+		return err
+	}
 	backup := client.Backups["default"]
 	backup.SnapshotPeriodSeconds = snapshotPeriodSeconds
 	client.Backups["default"] = backup
@@ -958,6 +1312,10 @@ func (client *MockAdminClient) ModifyBackup(snapshotPeriodSeconds int) error {
 
 // StopBackup stops a backup.
 func (client *MockAdminClient) StopBackup(url string) error {
+	err := applyMockFault(client.faults, "StopBackup")
+	if err != nil {
+		return err
+	}
 	for tag, backup := range client.Backups {
 		if backup.URL == url {
 			backup.Running = false
@@ -965,11 +1323,15 @@ func (client *MockAdminClient) StopBackup(url string) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("No backup found for URL %s", url)
+	return AdminClientError{Reason: AdminClientCommandRejected, Command: "fdbbackup discontinue", Message: fmt.Sprintf("No backup found for URL %s", url)}
 }
 
 // GetBackupStatus gets the status of the current backup.
 func (client *MockAdminClient) GetBackupStatus() (*fdbtypes.FoundationDBLiveBackupStatus, error) {
+	err := applyMockFault(client.faults, "GetBackupStatus")
+	if err != nil {
+		return nil, err
+	}
 	status := &fdbtypes.FoundationDBLiveBackupStatus{}
 
 	tag := "default"
@@ -986,12 +1348,20 @@ func (client *MockAdminClient) GetBackupStatus() (*fdbtypes.FoundationDBLiveBack
 
 // StartRestore starts a new restore.
 func (client *MockAdminClient) StartRestore(url string) error {
+	err := applyMockFault(client.faults, "StartRestore")
+	if err != nil {
+		return err
+	}
 	client.restoreURL = url
 	return nil
 }
 
 // GetRestoreStatus gets the status of the current restore.
 func (client *MockAdminClient) GetRestoreStatus() (string, error) {
+	err := applyMockFault(client.faults, "GetRestoreStatus")
+	if err != nil {
+		return "", err
+	}
 	return fmt.Sprintf("%s\n", client.restoreURL), nil
 }
 
@@ -1026,3 +1396,87 @@ func (client *MockAdminClient) FreezeStatus() error {
 func (client *MockAdminClient) UnfreezeStatus() {
 	client.frozenStatus = nil
 }
+
+// TriggerRecovery simulates a recovery in the database, which will make the
+// database unavailable for the next RecoveryPolls calls to GetStatus.
+func (client *MockAdminClient) TriggerRecovery() {
+	client.remainingRecoveryPolls = client.RecoveryPolls
+}
+
+// MockMissingProcessGroup sets whether the processes for an instance should
+// be left out of the status.
+func (client *MockAdminClient) MockMissingProcessGroup(instanceID string, missing bool) {
+	if missing {
+		if client.MissingProcessGroups == nil {
+			client.MissingProcessGroups = make(map[string]bool)
+		}
+		client.MissingProcessGroups[instanceID] = true
+	} else {
+		delete(client.MissingProcessGroups, instanceID)
+	}
+}
+
+// MockUnreachableCoordinator sets whether a coordinator should be reported
+// as unreachable.
+func (client *MockAdminClient) MockUnreachableCoordinator(address string, unreachable bool) {
+	if unreachable {
+		if client.UnreachableCoordinators == nil {
+			client.UnreachableCoordinators = make(map[string]bool)
+		}
+		client.UnreachableCoordinators[address] = true
+	} else {
+		delete(client.UnreachableCoordinators, address)
+	}
+}
+
+// advanceExclusions records a status poll, moving data off of any processes
+// that are still being excluded.
+func (client *MockAdminClient) advanceExclusions() {
+	for address, polls := range client.remainingDrainPolls {
+		if polls > 0 {
+			client.remainingDrainPolls[address] = polls - 1
+		}
+	}
+}
+
+// getRemainingDrainPolls gets the number of polls left before an excluded
+// address has finished moving its data.
+//
+// The address can be either a full address or an IP address.
+func (client *MockAdminClient) getRemainingDrainPolls(address string) int {
+	polls := client.remainingDrainPolls[address]
+	if isLocalityExclusion(address) {
+		return polls
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err == nil && client.remainingDrainPolls[host] > polls {
+		polls = client.remainingDrainPolls[host]
+	}
+	return polls
+}
+
+// InjectFault causes calls to a method on the client to fail.
+//
+// The method should be the name of a method in the AdminClient interface.
+// Injecting a new fault for a method replaces any existing fault for that
+// method.
+func (client *MockAdminClient) InjectFault(method string, fault MockFault) {
+	if client.faults == nil {
+		client.faults = make(map[string]*MockFault)
+	}
+	client.faults[method] = &fault
+}
+
+// ClearFaults removes all of the faults that have been injected into the
+// client.
+func (client *MockAdminClient) ClearFaults() {
+	client.faults = nil
+	client.mockConnectionString = ""
+}
+
+// MockConnectionString causes GetConnectionString to return a fixed value,
+// which can be used to simulate a malformed connection string. Passing an
+// empty string restores the normal behavior.
+func (client *MockAdminClient) MockConnectionString(connectionString string) {
+	client.mockConnectionString = connectionString
+}
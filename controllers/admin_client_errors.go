@@ -0,0 +1,154 @@
+/*
+ * admin_client_errors.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
+)
+
+// AdminClientErrorReason describes the category of a failure from an admin
+// client.
+type AdminClientErrorReason string
+
+const (
+	// AdminClientTimeout indicates that the command did not complete within
+	// its timeout.
+	AdminClientTimeout AdminClientErrorReason = "Timeout"
+
+	// AdminClientClusterUnreachable indicates that we could not connect to the
+	// database.
+	AdminClientClusterUnreachable AdminClientErrorReason = "ClusterUnreachable"
+
+	// AdminClientTLSHandshakeFailure indicates that we could not establish a
+	// TLS connection to the database.
+	AdminClientTLSHandshakeFailure AdminClientErrorReason = "TLSHandshakeFailure"
+
+	// AdminClientCommandRejected indicates that the database or the CLI
+	// rejected the command.
+	AdminClientCommandRejected AdminClientErrorReason = "CommandRejected"
+
+	// AdminClientUnparseableOutput indicates that we could not parse the
+	// output of the command.
+	AdminClientUnparseableOutput AdminClientErrorReason = "UnparseableOutput"
+)
+
+// AdminClientError is returned when an admin client fails to run a command.
+type AdminClientError struct {
+	// Reason provides the category of the failure.
+	Reason AdminClientErrorReason
+
+	// Command provides a description of the command that failed.
+	Command string
+
+	// Message provides additional details about the failure.
+	Message string
+
+	// Cause provides the underlying error, if there is one.
+	Cause error
+}
+
+// Error formats the error message.
+func (err AdminClientError) Error() string {
+	message := fmt.Sprintf("%s error running %s", err.Reason, err.Command)
+	if err.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, err.Message)
+	}
+	if err.Cause != nil {
+		message = fmt.Sprintf("%s: %v", message, err.Cause)
+	}
+	return message
+}
+
+// Unwrap returns the underlying error.
+func (err AdminClientError) Unwrap() error {
+	return err.Cause
+}
+
+// Retryable determines whether the failure is likely to be resolved by
+// retrying the command without any change to the cluster spec.
+func (err AdminClientError) Retryable() bool {
+	return err.Reason == AdminClientTimeout || err.Reason == AdminClientClusterUnreachable
+}
+
+// RequeueAfter returns the delay we should use before retrying a
+// reconciliation that hit this error.
+func (err AdminClientError) RequeueAfter() time.Duration {
+	return time.Duration(DefaultCLITimeout) * time.Second
+}
+
+// GetAdminClientErrorReason gets the reason for an admin client error. This
+// will return an empty string if the error did not come from an admin
+// client.
+func GetAdminClientErrorReason(err error) AdminClientErrorReason {
+	var adminError AdminClientError
+	if !errors.As(err, &adminError) {
+		return ""
+	}
+	return adminError.Reason
+}
+
+// classifyCommandFailure determines the reason for a failed CLI command based
+// on the output it produced.
+func classifyCommandFailure(output string) AdminClientErrorReason {
+	lowerOutput := strings.ToLower(output)
+	if strings.Contains(lowerOutput, "tls") && (strings.Contains(lowerOutput, "handshake") || strings.Contains(lowerOutput, "certificate")) {
+		return AdminClientTLSHandshakeFailure
+	}
+	if strings.Contains(lowerOutput, "timeout reached") || strings.Contains(lowerOutput, "timed out") {
+		return AdminClientTimeout
+	}
+	if strings.Contains(lowerOutput, "database is unavailable") || strings.Contains(lowerOutput, "unable to connect to cluster") {
+		return AdminClientClusterUnreachable
+	}
+	return AdminClientCommandRejected
+}
+
+// newNativeAdminClientError wraps an error from the client library in an
+// admin client error.
+func newNativeAdminClientError(command string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var fdbError fdb.Error
+	if !errors.As(err, &fdbError) {
+		return AdminClientError{Reason: classifyCommandFailure(err.Error()), Command: command, Cause: err}
+	}
+
+	reason := AdminClientCommandRejected
+	switch fdbError.Code {
+	// timed_out and transaction_timed_out
+	case 1004, 1031:
+		reason = AdminClientTimeout
+	// connection_failed
+	case 1026:
+		reason = AdminClientClusterUnreachable
+	// tls_error
+	case 2107:
+		reason = AdminClientTLSHandshakeFailure
+	}
+	return AdminClientError{Reason: reason, Command: command, Cause: err}
+}
@@ -0,0 +1,161 @@
+/*
+ * admin_client_session.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"fmt"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+)
+
+// adminClientSession provides an admin client and a snapshot of the database
+// status that are shared by all of the sub-reconcilers in a single
+// reconciliation pass.
+//
+// Sub-reconcilers that make changes to the database or to the processes in
+// the cluster must call InvalidateStatus after making those changes, so that
+// later sub-reconcilers will fetch a new status.
+type adminClientSession struct {
+	// reconciler is the reconciler that owns this session.
+	reconciler *FoundationDBClusterReconciler
+
+	// cluster is the cluster this session is working with.
+	cluster *fdbtypes.FoundationDBCluster
+
+	// adminClient is the admin client for the session. This will be nil until
+	// the first time it is needed.
+	adminClient AdminClient
+
+	// connectionString is the connection string the admin client was created
+	// with.
+	connectionString string
+
+	// status is the cached database status.
+	status *fdbtypes.FoundationDBStatus
+}
+
+// GetAdminClient gets the admin client for the session.
+//
+// If the connection string for the cluster has changed since the client was
+// created, this will replace the client.
+func (session *adminClientSession) GetAdminClient() (AdminClient, error) {
+	if session.adminClient != nil && session.connectionString == session.cluster.Status.ConnectionString {
+		return session.adminClient, nil
+	}
+
+	if session.adminClient != nil {
+		err := session.adminClient.Close()
+		if err != nil {
+			return nil, err
+		}
+		session.adminClient = nil
+		session.status = nil
+	}
+
+	adminClient, err := session.reconciler.AdminClientProvider(session.cluster, session.reconciler)
+	if err != nil {
+		return nil, err
+	}
+	session.adminClient = adminClient
+	session.connectionString = session.cluster.Status.ConnectionString
+	return adminClient, nil
+}
+
+// GetStatus gets the database status, using the cached status if one is
+// available.
+func (session *adminClientSession) GetStatus() (*fdbtypes.FoundationDBStatus, error) {
+	adminClient, err := session.GetAdminClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if session.status != nil {
+		statusCacheRequests.WithLabelValues(session.cluster.Namespace, session.cluster.Name, "hit").Inc()
+		return session.status, nil
+	}
+
+	statusCacheRequests.WithLabelValues(session.cluster.Namespace, session.cluster.Name, "miss").Inc()
+	status, err := adminClient.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+	session.status = status
+	return status, nil
+}
+
+// InvalidateStatus clears the cached status, so that the next call to
+// GetStatus will fetch a new status from the database.
+func (session *adminClientSession) InvalidateStatus() {
+	session.status = nil
+}
+
+// Close shuts down the admin client for the session.
+func (session *adminClientSession) Close() error {
+	session.status = nil
+	if session.adminClient == nil {
+		return nil
+	}
+	err := session.adminClient.Close()
+	session.adminClient = nil
+	return err
+}
+
+// getAdminClientSessionKey gets the key we use to track the admin client
+// session for a cluster.
+func getAdminClientSessionKey(cluster *fdbtypes.FoundationDBCluster) string {
+	return fmt.Sprintf("%s/%s", cluster.ObjectMeta.Namespace, cluster.ObjectMeta.Name)
+}
+
+// getAdminClientSession gets the admin client session for the current
+// reconciliation pass for a cluster, creating one if necessary.
+func (r *FoundationDBClusterReconciler) getAdminClientSession(cluster *fdbtypes.FoundationDBCluster) *adminClientSession {
+	r.adminClientSessionsMutex.Lock()
+	defer r.adminClientSessionsMutex.Unlock()
+
+	if r.adminClientSessions == nil {
+		r.adminClientSessions = make(map[string]*adminClientSession)
+	}
+
+	cacheKey := getAdminClientSessionKey(cluster)
+	session, present := r.adminClientSessions[cacheKey]
+	if !present {
+		session = &adminClientSession{reconciler: r}
+		r.adminClientSessions[cacheKey] = session
+	}
+	session.cluster = cluster
+	return session
+}
+
+// endAdminClientSession closes the admin client session for a cluster, so
+// that the next reconciliation pass will start with a new client and a new
+// status.
+func (r *FoundationDBClusterReconciler) endAdminClientSession(cluster *fdbtypes.FoundationDBCluster) error {
+	r.adminClientSessionsMutex.Lock()
+	defer r.adminClientSessionsMutex.Unlock()
+
+	cacheKey := getAdminClientSessionKey(cluster)
+	session, present := r.adminClientSessions[cacheKey]
+	if !present {
+		return nil
+	}
+	delete(r.adminClientSessions, cacheKey)
+	return session.Close()
+}
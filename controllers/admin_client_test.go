@@ -22,12 +22,18 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
 )
 
 var _ = Describe("admin_client_test", func() {
@@ -47,7 +53,7 @@ var _ = Describe("admin_client_test", func() {
 			return reloadCluster(cluster)
 		}, timeout).ShouldNot(Equal(int64(0)))
 
-		client, err = newMockAdminClientUncast(cluster, k8sClient)
+		client, err = NewMockAdminClientUncast(cluster, k8sClient)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -261,6 +267,194 @@ var _ = Describe("admin_client_test", func() {
 		})
 	})
 
+	Describe("exclusions", func() {
+		Context("with an invalid address", func() {
+			It("should return a rejected command error", func() {
+				err = client.ExcludeInstances([]string{"1.1.1.1"})
+				Expect(err).To(HaveOccurred())
+				Expect(GetAdminClientErrorReason(err)).To(Equal(AdminClientCommandRejected))
+			})
+		})
+
+		Context("with a drain period", func() {
+			var status *fdbtypes.FoundationDBStatus
+
+			BeforeEach(func() {
+				client.ExclusionDrainPolls = 2
+				err = client.ExcludeInstances([]string{"1.1.0.1:4501"})
+				Expect(err).NotTo(HaveOccurred())
+				status, err = client.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should report the data movement in the status", func() {
+				Expect(status.Cluster.Data.MovingData.InFlightBytes).To(Equal(mockBytesPerExclusionPoll))
+				process := status.Cluster.Processes["operator-test-1-storage-1"]
+				Expect(process.Excluded).To(BeTrue())
+				Expect(process.Roles).To(Equal([]fdbtypes.FoundationDBStatusProcessRoleInfo{{Role: "storage"}}))
+			})
+
+			It("should report the process as safe to remove once the data has moved", func() {
+				remaining, err := client.CanSafelyRemove([]string{"1.1.0.1:4501"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(remaining).To(BeNil())
+
+				status, err = client.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.Cluster.Data.MovingData.InFlightBytes).To(Equal(0))
+				Expect(status.Cluster.Processes["operator-test-1-storage-1"].Roles).To(BeNil())
+			})
+
+			Context("with more polls remaining", func() {
+				BeforeEach(func() {
+					client.ExclusionDrainPolls = 5
+					err = client.ExcludeInstances([]string{"1.1.0.2:4501"})
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should only report the processes that are still draining", func() {
+					remaining, err := client.CanSafelyRemove([]string{"1.1.0.1:4501", "1.1.0.2:4501"})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(remaining).To(Equal([]string{"1.1.0.2:4501"}))
+				})
+			})
+		})
+	})
+
+	Describe("locality-based exclusions", func() {
+		var status *fdbtypes.FoundationDBStatus
+
+		BeforeEach(func() {
+			err = client.ExcludeInstances([]string{"locality_instance_id:storage-1"})
+			Expect(err).NotTo(HaveOccurred())
+			status, err = client.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should exclude the process with that instance ID", func() {
+			Expect(status.Cluster.Processes["operator-test-1-storage-1"].Excluded).To(BeTrue())
+			Expect(status.Cluster.Processes["operator-test-1-storage-2"].Excluded).To(BeFalse())
+		})
+
+		It("should find the address for the locality", func() {
+			Expect(getLocalityExclusionAddresses(status, "locality_instance_id:storage-1")).To(Equal([]string{"1.1.0.1:4501"}))
+		})
+	})
+
+	Describe("simulated failures", func() {
+		var status *fdbtypes.FoundationDBStatus
+
+		JustBeforeEach(func() {
+			status, err = client.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("with a missing process", func() {
+			BeforeEach(func() {
+				client.MockMissingProcessGroup("storage-1", true)
+			})
+
+			It("should leave the process out of the status", func() {
+				_, present := status.Cluster.Processes["operator-test-1-storage-1"]
+				Expect(present).To(BeFalse())
+				_, present = status.Cluster.Processes["operator-test-1-storage-2"]
+				Expect(present).To(BeTrue())
+			})
+		})
+
+		Context("with an unreachable coordinator", func() {
+			BeforeEach(func() {
+				cluster.Status.ConnectionString = "operator-test:asdfasf@1.1.0.1:4501,1.1.0.2:4501"
+				client.MockUnreachableCoordinator("1.1.0.1:4501", true)
+			})
+
+			It("should mark the coordinator as unreachable", func() {
+				reachability := make(map[string]bool)
+				for _, coordinator := range status.Client.Coordinators.Coordinators {
+					reachability[coordinator.Address] = coordinator.Reachable
+				}
+				Expect(reachability).To(Equal(map[string]bool{
+					"1.1.0.1:4501": false,
+					"1.1.0.2:4501": true,
+				}))
+			})
+		})
+
+		Context("with a recovery", func() {
+			BeforeEach(func() {
+				client.RecoveryPolls = 1
+				err = client.KillInstances([]string{"1.1.0.1:4501"})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should make the database unavailable until the recovery is done", func() {
+				Expect(status.Client.DatabaseStatus.Available).To(BeFalse())
+				Expect(status.Client.DatabaseStatus.Healthy).To(BeFalse())
+
+				status, err = client.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.Client.DatabaseStatus.Available).To(BeTrue())
+				Expect(status.Client.DatabaseStatus.Healthy).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("admin client session", func() {
+		var reconciler *FoundationDBClusterReconciler
+		var session *adminClientSession
+		var status *fdbtypes.FoundationDBStatus
+
+		BeforeEach(func() {
+			reconciler = &FoundationDBClusterReconciler{
+				Client:              k8sClient,
+				AdminClientProvider: NewMockAdminClient,
+			}
+			session = reconciler.getAdminClientSession(cluster)
+			status, err = session.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(reconciler.endAdminClientSession(cluster)).NotTo(HaveOccurred())
+		})
+
+		It("should share the session for the cluster", func() {
+			Expect(reconciler.getAdminClientSession(cluster)).To(BeIdenticalTo(session))
+		})
+
+		Context("with no changes to the database", func() {
+			It("should return the cached status", func() {
+				newStatus, err := session.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(newStatus).To(BeIdenticalTo(status))
+			})
+		})
+
+		Context("with an invalidated status", func() {
+			BeforeEach(func() {
+				session.InvalidateStatus()
+			})
+
+			It("should fetch a new status", func() {
+				newStatus, err := session.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(newStatus).NotTo(BeIdenticalTo(status))
+			})
+		})
+
+		Context("with a change to the connection string", func() {
+			BeforeEach(func() {
+				cluster.Status.ConnectionString = "operator-test:asdfasf@127.0.0.1:4501"
+			})
+
+			It("should fetch a new status", func() {
+				newStatus, err := session.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(newStatus).NotTo(BeIdenticalTo(status))
+			})
+		})
+	})
+
 	Describe("helper methods", func() {
 		Describe("parseExclusionOutput", func() {
 			It("should map the output description to exclusion success", func() {
@@ -284,6 +478,231 @@ var _ = Describe("admin_client_test", func() {
 					"10.1.56.56:4501": "Success",
 				}))
 			})
+
+			It("should handle IPv6 addresses", func() {
+				output := "  [fd00::1:36]:4501  ---- Successfully excluded. It is now safe to remove this process from the cluster.\n" +
+					"  [fd00:0:0:0:0:0:1:35]:4501  ---- WARNING: Exclusion in progress! It is not safe to remove this process from the cluster\n"
+				results := parseExclusionOutput(output)
+				Expect(results).To(Equal(map[string]string{
+					"[fd00::1:36]:4501": "Success",
+					"[fd00::1:35]:4501": "In Progress",
+				}))
+			})
+		})
+
+		Describe("removeAddressFlags", func() {
+			It("should strip the flags from addresses", func() {
+				Expect(removeAddressFlags([]string{"1.1.0.1:4501:tls", "1.1.0.2:4501"})).To(Equal([]string{"1.1.0.1:4501", "1.1.0.2:4501"}))
+			})
+
+			It("should strip the flags from IPv6 addresses", func() {
+				Expect(removeAddressFlags([]string{"[fd00::1:1]:4501:tls", "[fd00::1:2]:4501"})).To(Equal([]string{"[fd00::1:1]:4501", "[fd00::1:2]:4501"}))
+			})
+
+			It("should leave locality exclusions unchanged", func() {
+				Expect(removeAddressFlags([]string{"locality_instance_id:storage-1"})).To(Equal([]string{"locality_instance_id:storage-1"}))
+			})
+		})
+
+		Describe("classifyCommandFailure", func() {
+			It("should detect TLS failures", func() {
+				Expect(classifyCommandFailure("ERROR: TLS handshake failed: certificate verify failed")).To(Equal(AdminClientTLSHandshakeFailure))
+			})
+
+			It("should detect timeouts", func() {
+				Expect(classifyCommandFailure("Specified timeout reached -- exiting...")).To(Equal(AdminClientTimeout))
+			})
+
+			It("should detect an unavailable database", func() {
+				Expect(classifyCommandFailure("The database is unavailable; type `status' for more information.")).To(Equal(AdminClientClusterUnreachable))
+			})
+
+			It("should treat other failures as rejected commands", func() {
+				Expect(classifyCommandFailure("ERROR: Unknown command `foo'")).To(Equal(AdminClientCommandRejected))
+			})
+		})
+
+		Describe("newNativeAdminClientError", func() {
+			It("should detect timeouts", func() {
+				Expect(GetAdminClientErrorReason(newNativeAdminClientError("status", fdb.Error{Code: 1031}))).To(Equal(AdminClientTimeout))
+			})
+
+			It("should detect connection failures", func() {
+				Expect(GetAdminClientErrorReason(newNativeAdminClientError("status", fdb.Error{Code: 1026}))).To(Equal(AdminClientClusterUnreachable))
+			})
+
+			It("should detect TLS failures", func() {
+				Expect(GetAdminClientErrorReason(newNativeAdminClientError("status", fdb.Error{Code: 2107}))).To(Equal(AdminClientTLSHandshakeFailure))
+			})
+
+			It("should detect wrapped errors from the client library", func() {
+				err := fmt.Errorf("error reading status: %w", fdb.Error{Code: 1004})
+				Expect(GetAdminClientErrorReason(newNativeAdminClientError("status", err))).To(Equal(AdminClientTimeout))
+			})
+
+			It("should classify other errors by their message", func() {
+				err := fmt.Errorf("TLS handshake failed: certificate verify failed")
+				Expect(GetAdminClientErrorReason(newNativeAdminClientError("status", err))).To(Equal(AdminClientTLSHandshakeFailure))
+			})
+
+			It("should treat other errors from the client library as rejected commands", func() {
+				Expect(GetAdminClientErrorReason(newNativeAdminClientError("status", fdb.Error{Code: 2000}))).To(Equal(AdminClientCommandRejected))
+			})
+		})
+
+		Describe("NewAdminClient", func() {
+			It("should use the CLI client by default", func() {
+				adminClient, err := NewAdminClient(cluster, k8sClient)
+				Expect(err).NotTo(HaveOccurred())
+				defer adminClient.Close()
+
+				_, isCliClient := adminClient.(*CliAdminClient)
+				Expect(isCliClient).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("native admin client", func() {
+		var binaryDir string
+		var argumentsPath string
+		var outputPath string
+		var nativeClient *NativeAdminClient
+		var originalBinaryDir string
+
+		BeforeEach(func() {
+			binaryDir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+			argumentsPath = filepath.Join(binaryDir, "arguments")
+			outputPath = filepath.Join(binaryDir, "output")
+
+			versionDir := filepath.Join(binaryDir, cluster.Status.RunningVersion[:strings.LastIndex(cluster.Status.RunningVersion, ".")])
+			err = os.MkdirAll(versionDir, 0755)
+			Expect(err).NotTo(HaveOccurred())
+
+			script := fmt.Sprintf(`#!/bin/sh
+echo "$@" > %s
+while [ $# -gt 0 ]; do
+	if [ "$1" = "-C" ]; then
+		echo "operator_test:abcd1234@127.0.0.2:4501,127.0.0.3:4501" > "$2"
+	fi
+	shift
+done
+if [ -f %s ]; then
+	cat %s
+fi
+`, argumentsPath, outputPath, outputPath)
+			err = ioutil.WriteFile(filepath.Join(versionDir, "fdbcli"), []byte(script), 0755)
+			Expect(err).NotTo(HaveOccurred())
+
+			originalBinaryDir = os.Getenv("FDB_BINARY_DIR")
+			err = os.Setenv("FDB_BINARY_DIR", binaryDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			cliClient, err := NewCliAdminClient(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+			nativeClient = &NativeAdminClient{Cluster: cluster, cliClient: cliClient.(*CliAdminClient)}
+		})
+
+		AfterEach(func() {
+			Expect(nativeClient.Close()).NotTo(HaveOccurred())
+			Expect(os.Setenv("FDB_BINARY_DIR", originalBinaryDir)).NotTo(HaveOccurred())
+			Expect(os.RemoveAll(binaryDir)).NotTo(HaveOccurred())
+		})
+
+		Describe("ChangeCoordinators", func() {
+			var connectionString string
+
+			BeforeEach(func() {
+				connectionString, err = nativeClient.ChangeCoordinators([]string{"127.0.0.2:4501", "127.0.0.3:4501"})
+			})
+
+			It("should change the coordinators through the CLI", func() {
+				Expect(err).NotTo(HaveOccurred())
+				arguments, err := ioutil.ReadFile(argumentsPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(arguments)).To(HavePrefix("--exec coordinators 127.0.0.2:4501 127.0.0.3:4501 -C "))
+			})
+
+			It("should return the connection string from the CLI", func() {
+				Expect(connectionString).To(Equal("operator_test:abcd1234@127.0.0.2:4501,127.0.0.3:4501"))
+			})
+		})
+
+		Describe("ExcludeInstances", func() {
+			BeforeEach(func() {
+				err = nativeClient.ExcludeInstances([]string{"127.0.0.4:4501"})
+			})
+
+			It("should exclude the instances through the CLI", func() {
+				Expect(err).NotTo(HaveOccurred())
+				arguments, err := ioutil.ReadFile(argumentsPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(arguments)).To(HavePrefix("--exec exclude 127.0.0.4:4501 -C "))
+			})
+		})
+
+		Describe("CanSafelyRemove", func() {
+			var remaining []string
+
+			BeforeEach(func() {
+				cluster.Spec.Version = "6.3.5"
+				output := "  127.0.0.4:4501  ---- Successfully excluded. It is now safe to remove this process from the cluster.\n" +
+					"  127.0.0.5:4501  ---- WARNING: Exclusion in progress! It is not safe to remove this process from the cluster\n"
+				err = ioutil.WriteFile(outputPath, []byte(output), 0644)
+				Expect(err).NotTo(HaveOccurred())
+
+				remaining, err = nativeClient.CanSafelyRemove([]string{"127.0.0.4:4501", "127.0.0.5:4501"})
+			})
+
+			It("should check the exclusions through the CLI", func() {
+				Expect(err).NotTo(HaveOccurred())
+				arguments, err := ioutil.ReadFile(argumentsPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(arguments)).To(HavePrefix("--exec exclude no_wait 127.0.0.4:4501 127.0.0.5:4501 -C "))
+			})
+
+			It("should report the processes that still have data as not safe to remove", func() {
+				Expect(remaining).To(Equal([]string{"127.0.0.5:4501"}))
+			})
+		})
+
+		Context("with a database that cannot be reached", func() {
+			var originalTimeout int
+
+			BeforeEach(func() {
+				if !fdb.IsAPIVersionSelected() {
+					fdb.MustAPIVersion(610)
+				}
+				nativeClient.database, err = getNativeDatabase("operator_test:abcd1234@127.0.0.1:1")
+				Expect(err).NotTo(HaveOccurred())
+
+				originalTimeout = DefaultCLITimeout
+				DefaultCLITimeout = 1
+			})
+
+			AfterEach(func() {
+				DefaultCLITimeout = originalTimeout
+			})
+
+			Describe("GetStatus", func() {
+				It("should not report the database as available", func() {
+					status, err := nativeClient.GetStatus()
+					if err != nil {
+						Expect(GetAdminClientErrorReason(err)).To(Equal(AdminClientTimeout))
+					} else {
+						Expect(status.Client.DatabaseStatus.Available).To(BeFalse())
+					}
+				})
+			})
+
+			Describe("IncludeInstances", func() {
+				It("should time out without running the CLI", func() {
+					err = nativeClient.IncludeInstances([]string{"127.0.0.4:4501"})
+					Expect(GetAdminClientErrorReason(err)).To(Equal(AdminClientTimeout))
+					_, err = os.Stat(argumentsPath)
+					Expect(os.IsNotExist(err)).To(BeTrue())
+				})
+			})
 		})
 	})
 })
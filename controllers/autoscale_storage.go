@@ -0,0 +1,198 @@
+/*
+ * autoscale_storage.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	ctx "context"
+	"fmt"
+	"time"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// autoscalingCheckInterval provides how often we check the disk utilization
+// when storage autoscaling is enabled.
+const autoscalingCheckInterval = 5 * time.Minute
+
+// AutoscaleStorage provides a reconciliation step for changing the storage
+// process count based on the disk utilization of the storage processes.
+//
+// This changes the storage process count in the cluster spec, and leaves the
+// work of adding and removing pods to the later reconciliation steps.
+type AutoscaleStorage struct{}
+
+// Reconcile runs the reconciler's work.
+func (a AutoscaleStorage) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
+	if !cluster.ShouldAutoscaleStorage() {
+		return true, nil
+	}
+
+	// The webhooks are optional, so we apply the same validation here before
+	// we act on the configuration.
+	err := cluster.ValidateAutoscaling()
+	if err != nil {
+		log.Info("Skipping storage autoscaling because the configuration is invalid", "namespace", cluster.Namespace, "cluster", cluster.Name, "error", err.Error())
+		r.Recorder.Event(cluster, "Normal", "AutoscalingInvalid", err.Error())
+		return true, nil
+	}
+
+	if len(cluster.Status.PendingRemovals) > 0 {
+		log.Info("Deferring storage autoscaling until pending removals are complete", "namespace", cluster.Namespace, "cluster", cluster.Name)
+		return true, nil
+	}
+
+	status, err := r.getAdminClientSession(cluster).GetStatus()
+	if err != nil {
+		return false, err
+	}
+
+	if !status.Client.DatabaseStatus.Healthy {
+		log.Info("Deferring storage autoscaling until the database is healthy", "namespace", cluster.Namespace, "cluster", cluster.Name)
+		return true, nil
+	}
+
+	if status.Cluster.Data.MovingData.InFlightBytes > 0 || status.Cluster.Data.MovingData.InQueueBytes > 0 {
+		log.Info("Deferring storage autoscaling until data movement is complete", "namespace", cluster.Namespace, "cluster", cluster.Name)
+		return true, nil
+	}
+
+	counts, err := cluster.GetProcessCountsWithDefaults()
+	if err != nil {
+		return false, err
+	}
+
+	utilization, ready := getStorageDiskUtilization(status, counts.Storage)
+	if !ready {
+		log.Info("Deferring storage autoscaling until all storage processes are reporting", "namespace", cluster.Namespace, "cluster", cluster.Name)
+		return true, nil
+	}
+
+	desiredCount := getAutoscaledStorageCount(cluster, counts.Storage, utilization)
+	if desiredCount == counts.Storage {
+		return true, nil
+	}
+
+	now := time.Now().Unix()
+	cooldownEnd := cluster.Status.Autoscaling.LastScaleTimestamp + int64(cluster.GetAutoscalingCooldownSeconds())
+	if now < cooldownEnd {
+		log.Info("Deferring storage autoscaling until the cooldown expires", "namespace", cluster.Namespace, "cluster", cluster.Name, "desiredCount", desiredCount, "remainingSeconds", cooldownEnd-now)
+		r.Recorder.Event(cluster, "Normal", "AutoscalingDeferred",
+			fmt.Sprintf("Storage disk utilization is %d%%, but the storage process count cannot change for another %d seconds", utilization, cooldownEnd-now))
+		return true, nil
+	}
+
+	log.Info("Changing storage process count", "namespace", cluster.Namespace, "cluster", cluster.Name, "currentCount", counts.Storage, "desiredCount", desiredCount, "utilization", utilization)
+	r.Recorder.Event(cluster, "Normal", "AutoscalingStorage",
+		fmt.Sprintf("Changing storage process count from %d to %d because disk utilization is %d%%", counts.Storage, desiredCount, utilization))
+
+	modifiedCluster := &fdbtypes.FoundationDBCluster{}
+	err = r.Get(context, types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}, modifiedCluster)
+	if err != nil {
+		return false, err
+	}
+	modifiedCluster.Spec.ProcessCounts.Storage = desiredCount
+	err = r.Update(context, modifiedCluster)
+	if err != nil {
+		return false, err
+	}
+
+	// We only start the cooldown once the new count is in the spec, so that a
+	// failed update does not prevent us from trying again.
+	modifiedCluster.Status.Autoscaling.LastScaleTimestamp = now
+	err = r.Status().Update(context, modifiedCluster)
+	if err != nil {
+		return false, err
+	}
+
+	// The next reconciliation will pick up the new process count.
+	return false, nil
+}
+
+// getStorageDiskUtilization gets the percentage of the disk space on the
+// storage processes that is in use.
+//
+// This will return false if the number of storage processes reporting to the
+// database does not match the expected count, since the utilization will
+// change once the cluster converges.
+func getStorageDiskUtilization(status *fdbtypes.FoundationDBStatus, expectedCount int) (int, bool) {
+	var usedBytes, totalBytes, processCount int
+	for _, process := range status.Cluster.Processes {
+		if process.ProcessClass != "storage" || process.Excluded || process.Disk.TotalBytes == 0 {
+			continue
+		}
+		usedBytes += process.Disk.TotalBytes - process.Disk.FreeBytes
+		totalBytes += process.Disk.TotalBytes
+		processCount++
+	}
+
+	if processCount == 0 || processCount != expectedCount {
+		return 0, false
+	}
+
+	return int(int64(usedBytes) * 100 / int64(totalBytes)), true
+}
+
+// getAutoscaledStorageCount gets the storage process count that would bring
+// the disk utilization back to the target.
+//
+// The count only changes when the utilization is outside of the tolerance
+// around the target, and it is always kept within the limits from the
+// autoscaling configuration.
+func getAutoscaledStorageCount(cluster *fdbtypes.FoundationDBCluster, currentCount int, utilization int) int {
+	target := cluster.GetTargetDiskUtilizationPercent()
+	tolerance := cluster.GetAutoscalingTolerancePercent()
+
+	desiredCount := currentCount
+	if utilization > target+tolerance || utilization < target-tolerance {
+		desiredCount = (currentCount*utilization + target - 1) / target
+	}
+
+	if desiredCount < cluster.Spec.Autoscaling.MinStorageProcesses {
+		desiredCount = cluster.Spec.Autoscaling.MinStorageProcesses
+	}
+	if desiredCount > cluster.Spec.Autoscaling.MaxStorageProcesses {
+		desiredCount = cluster.Spec.Autoscaling.MaxStorageProcesses
+	}
+	return desiredCount
+}
+
+// getAutoscalingCheckDelay gets the delay before we should run reconciliation
+// again in order to check the disk utilization.
+//
+// This will return 0 if storage autoscaling is disabled.
+func getAutoscalingCheckDelay(cluster *fdbtypes.FoundationDBCluster, now int64) time.Duration {
+	if !cluster.ShouldAutoscaleStorage() {
+		return 0
+	}
+
+	remaining := time.Duration(cluster.Status.Autoscaling.LastScaleTimestamp+int64(cluster.GetAutoscalingCooldownSeconds())-now) * time.Second
+	if remaining < autoscalingCheckInterval {
+		return autoscalingCheckInterval
+	}
+	return remaining
+}
+
+// RequeueAfter returns the delay before we should run the reconciliation
+// again.
+func (a AutoscaleStorage) RequeueAfter() time.Duration {
+	return 0
+}
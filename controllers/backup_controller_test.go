@@ -60,7 +60,7 @@ var _ = Describe("backup_controller", func() {
 		ClearMockAdminClients()
 		cluster = createDefaultCluster()
 		backup = createDefaultBackup(cluster)
-		adminClient, err = newMockAdminClientUncast(cluster, k8sClient)
+		adminClient, err = NewMockAdminClientUncast(cluster, k8sClient)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
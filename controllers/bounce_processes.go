@@ -24,6 +24,7 @@ import (
 	ctx "context"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
@@ -36,35 +37,62 @@ type BounceProcesses struct{}
 
 // Reconcile runs the reconciler's work.
 func (b BounceProcesses) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
-	adminClient, err := r.AdminClientProvider(cluster, r)
+	session := r.getAdminClientSession(cluster)
+	adminClient, err := session.GetAdminClient()
 	if err != nil {
 		return false, err
 	}
-	defer adminClient.Close()
 
-	status, err := adminClient.GetStatus()
+	status, err := session.GetStatus()
 	if err != nil {
 		return false, err
 	}
 
+	bouncedInstances := make(map[string]bool, len(cluster.Status.Bounce.BouncedInstances))
+	for _, instanceID := range cluster.Status.Bounce.BouncedInstances {
+		bouncedInstances[instanceID] = true
+	}
+
 	minimumUptime := math.Inf(1)
 	addressMap := make(map[string]string, len(status.Cluster.Processes))
+	zoneMap := make(map[string]string, len(status.Cluster.Processes))
 	for _, process := range status.Cluster.Processes {
-		addressMap[process.Locality["instance_id"]] = process.Address
-		if process.UptimeSeconds < minimumUptime {
+		instanceID := process.Locality["instance_id"]
+		addressMap[instanceID] = process.Address
+		zoneMap[instanceID] = process.Locality["zoneid"]
+
+		// Processes we have already restarted as part of this bounce will
+		// have a low uptime, but that should not block the later batches.
+		if process.UptimeSeconds < minimumUptime && !bouncedInstances[instanceID] {
 			minimumUptime = process.UptimeSeconds
 		}
 	}
 
-	addresses := make([]string, 0, len(cluster.Status.IncorrectProcesses))
+	for _, instanceID := range cluster.Status.Bounce.CurrentBatch {
+		if addressMap[instanceID] == "" && !cluster.InstanceIsBeingRemoved(instanceID) {
+			log.Info("Waiting for bounced processes to rejoin", "namespace", cluster.Namespace, "cluster", cluster.Name, "instanceID", instanceID)
+			return false, ReconciliationNotReadyError{message: "Waiting for bounced processes to rejoin", retryable: true}
+		}
+	}
+	if len(cluster.Status.Bounce.CurrentBatch) > 0 && !status.Client.DatabaseStatus.Healthy {
+		log.Info("Waiting for the cluster to be healthy before bouncing more processes", "namespace", cluster.Namespace, "cluster", cluster.Name)
+		return false, ReconciliationNotReadyError{message: "Waiting for the cluster to be healthy before bouncing more processes", retryable: true}
+	}
+
+	instanceIDs := make([]string, 0, len(cluster.Status.ProcessGroups))
+	instanceMap := make(map[string]FdbInstance, len(cluster.Status.ProcessGroups))
 
-	for instanceID := range cluster.Status.IncorrectProcesses {
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if !processGroup.HasCondition(fdbtypes.IncorrectCommandLine) || processGroup.HasCondition(fdbtypes.MarkedForRemoval) {
+			continue
+		}
+		instanceID := processGroup.InstanceID
 
 		if addressMap[instanceID] == "" {
 			return false, fmt.Errorf("Could not find address for instance %s", instanceID)
 		}
 
-		addresses = append(addresses, addressMap[instanceID])
+		instanceIDs = append(instanceIDs, instanceID)
 
 		instances, err := r.PodLifecycleManager.GetInstances(r, cluster, context, getSinglePodListOptions(cluster, instanceID)...)
 		if err != nil {
@@ -73,6 +101,7 @@ func (b BounceProcesses) Reconcile(r *FoundationDBClusterReconciler, context ctx
 		if len(instances) == 0 {
 			return false, MissingPodErrorByName(instanceID, cluster)
 		}
+		instanceMap[instanceID] = instances[0]
 
 		synced, err := r.updatePodDynamicConf(cluster, instances[0])
 		if !synced {
@@ -80,7 +109,7 @@ func (b BounceProcesses) Reconcile(r *FoundationDBClusterReconciler, context ctx
 		}
 	}
 
-	if len(addresses) > 0 {
+	if len(instanceIDs) > 0 {
 		var enabled = cluster.Spec.AutomationOptions.KillProcesses
 		if enabled != nil && !*enabled {
 			err := r.Get(context, types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}, cluster)
@@ -109,16 +138,101 @@ func (b BounceProcesses) Reconcile(r *FoundationDBClusterReconciler, context ctx
 			return false, ReconciliationNotReadyError{message: "Cluster needs to stabilize before bouncing"}
 		}
 
+		useCanaries, err := usesCanariesForUpgrade(cluster)
+		if err != nil {
+			return false, err
+		}
+		startingCanaries := useCanaries && cluster.Status.CanaryUpgrade.Version != cluster.Spec.Version
+
+		var batch []string
+		if startingCanaries {
+			batch, err = getCanaryBatch(cluster, instanceIDs, instanceMap)
+			if err != nil {
+				return false, err
+			}
+			if len(batch) == 0 {
+				cluster.Status.CanaryUpgrade = fdbtypes.CanaryUpgradeStatus{Version: cluster.Spec.Version}
+				return false, haltCanaryUpgrade(r, context, cluster, "none of the processes that need to be upgraded match the canary settings")
+			}
+		} else {
+			batch, err = getBounceBatch(cluster, instanceIDs, zoneMap)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		addresses := make([]string, 0, len(batch))
+		zones := make(map[string]bool)
+		for _, instanceID := range batch {
+			addresses = append(addresses, addressMap[instanceID])
+			zones[zoneMap[instanceID]] = true
+		}
+
+		ready, err := r.clearMaintenanceZone(context, cluster)
+		if err != nil {
+			return false, err
+		}
+		if !ready {
+			return false, ReconciliationNotReadyError{message: "Waiting for processes to rejoin before leaving maintenance mode", retryable: true}
+		}
+
+		// The database only supports one zone in maintenance mode, so we
+		// can only use it when all of the processes are in the same zone.
+		if len(zones) == 1 && !zones[""] {
+			for zone := range zones {
+				err = r.setMaintenanceZone(context, cluster, zone)
+				if err != nil {
+					return false, err
+				}
+			}
+		}
+
 		log.Info("Bouncing instances", "namespace", cluster.Namespace, "cluster", cluster.Name, "addresses", addresses)
 		r.Recorder.Event(cluster, "Normal", "BouncingInstances", fmt.Sprintf("Bouncing processes: %v", addresses))
 		err = adminClient.KillInstances(addresses)
+		session.InvalidateStatus()
 		if err != nil {
 			return false, err
 		}
+
+		if startingCanaries {
+			cluster.Status.CanaryUpgrade = fdbtypes.CanaryUpgradeStatus{
+				Version:        cluster.Spec.Version,
+				Instances:      batch,
+				State:          fdbtypes.CanaryUpgradeStarting,
+				StartTimestamp: time.Now().Unix(),
+			}
+		}
+
+		if len(batch) < len(instanceIDs) || startingCanaries {
+			cluster.Status.Bounce.Strategy = cluster.GetBounceStrategyType()
+			cluster.Status.Bounce.CurrentBatch = batch
+			cluster.Status.Bounce.BouncedInstances = append(cluster.Status.Bounce.BouncedInstances, batch...)
+			err = r.Status().Update(context, cluster)
+			if err != nil {
+				return false, err
+			}
+			return false, ReconciliationNotReadyError{message: "Waiting for bounced processes to rejoin", retryable: true}
+		}
+	}
+
+	needsStatusUpdate := false
+	if cluster.Status.Bounce.Strategy != "" || len(cluster.Status.Bounce.BouncedInstances) > 0 {
+		cluster.Status.Bounce = fdbtypes.BounceStatus{}
+		needsStatusUpdate = true
+	}
+
+	if cluster.Status.CanaryUpgrade.Version != "" {
+		cluster.Status.CanaryUpgrade = fdbtypes.CanaryUpgradeStatus{}
+		needsStatusUpdate = true
 	}
 
 	if cluster.Status.RunningVersion != cluster.Spec.Version {
 		cluster.Status.RunningVersion = cluster.Spec.Version
+		needsStatusUpdate = true
+	}
+
+	if needsStatusUpdate {
 		err = r.Status().Update(context, cluster)
 		if err != nil {
 			return false, err
@@ -133,3 +247,47 @@ func (b BounceProcesses) Reconcile(r *FoundationDBClusterReconciler, context ctx
 func (b BounceProcesses) RequeueAfter() time.Duration {
 	return 0
 }
+
+// getBounceBatch chooses the instances that we should bounce next, based on
+// the bounce strategy in the cluster spec.
+//
+// Upgrades to a version that is not protocol-compatible with the running
+// version require bouncing all of the processes at once, so this will ignore
+// the bounce strategy in that case.
+func getBounceBatch(cluster *fdbtypes.FoundationDBCluster, instanceIDs []string, zoneMap map[string]string) ([]string, error) {
+	if cluster.Status.RunningVersion != "" && cluster.Status.RunningVersion != cluster.Spec.Version {
+		runningVersion, err := fdbtypes.ParseFdbVersion(cluster.Status.RunningVersion)
+		if err != nil {
+			return nil, err
+		}
+		desiredVersion, err := fdbtypes.ParseFdbVersion(cluster.Spec.Version)
+		if err != nil {
+			return nil, err
+		}
+		if !runningVersion.IsProtocolCompatible(desiredVersion) {
+			return instanceIDs, nil
+		}
+	}
+
+	candidates := make([]string, len(instanceIDs))
+	copy(candidates, instanceIDs)
+	sort.Strings(candidates)
+
+	batch := candidates
+	if cluster.GetBounceStrategyType() == fdbtypes.BounceStrategyFaultDomain {
+		zone := zoneMap[candidates[0]]
+		batch = make([]string, 0, len(candidates))
+		for _, instanceID := range candidates {
+			if zoneMap[instanceID] == zone {
+				batch = append(batch, instanceID)
+			}
+		}
+	}
+
+	maxProcesses := cluster.Spec.BounceStrategy.MaxProcessesPerBatch
+	if maxProcesses > 0 && len(batch) > maxProcesses {
+		batch = batch[:maxProcesses]
+	}
+
+	return batch, nil
+}
@@ -23,9 +23,12 @@ package controllers
 import (
 	ctx "context"
 	"fmt"
+	"sort"
 	"time"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // ChangeCoordinators provides a reconciliation step for choosing new
@@ -38,11 +41,11 @@ func (c ChangeCoordinators) Reconcile(r *FoundationDBClusterReconciler, context
 		return true, nil
 	}
 
-	adminClient, err := r.AdminClientProvider(cluster, r)
+	session := r.getAdminClientSession(cluster)
+	adminClient, err := session.GetAdminClient()
 	if err != nil {
 		return false, err
 	}
-	defer adminClient.Close()
 
 	connectionString, err := adminClient.GetConnectionString()
 	if err != nil {
@@ -60,7 +63,7 @@ func (c ChangeCoordinators) Reconcile(r *FoundationDBClusterReconciler, context
 		}
 	}
 
-	status, err := adminClient.GetStatus()
+	status, err := session.GetStatus()
 	if err != nil {
 		return false, err
 	}
@@ -70,6 +73,16 @@ func (c ChangeCoordinators) Reconcile(r *FoundationDBClusterReconciler, context
 		return false, err
 	}
 
+	excludedInstances, err := getCoordinatorExclusions(r, context, cluster)
+	if err != nil {
+		return false, err
+	}
+
+	if hasValidCoordinators && hasExcludedCoordinators(status, excludedInstances) {
+		log.Info("Cluster has a coordinator on an excluded instance", "namespace", cluster.Namespace, "cluster", cluster.Name)
+		hasValidCoordinators = false
+	}
+
 	if !hasValidCoordinators {
 		lockClient, err := r.getLockClient(cluster)
 		if err != nil {
@@ -95,18 +108,7 @@ func (c ChangeCoordinators) Reconcile(r *FoundationDBClusterReconciler, context
 		log.Info("Changing coordinators", "namespace", cluster.Namespace, "cluster", cluster.Name)
 		r.Recorder.Event(cluster, "Normal", "ChangingCoordinators", "Choosing new coordinators")
 
-		candidates := make([]localityInfo, 0, len(status.Cluster.Processes))
-		for _, process := range status.Cluster.Processes {
-			eligible := !process.Excluded && isStateful(process.ProcessClass) && !cluster.InstanceIsBeingRemoved(process.Locality["fdb-instance-id"])
-			if eligible {
-				candidates = append(candidates, localityInfoForProcess(process))
-			}
-		}
-
-		coordinatorCount := cluster.DesiredCoordinatorCount()
-		coordinators, err := chooseDistributedProcesses(candidates, coordinatorCount, processSelectionConstraint{
-			HardLimits: map[string]int{"zoneid": 1},
-		})
+		coordinators, err := chooseCoordinators(cluster, status, excludedInstances)
 		if err != nil {
 			return false, err
 		}
@@ -117,6 +119,7 @@ func (c ChangeCoordinators) Reconcile(r *FoundationDBClusterReconciler, context
 		}
 
 		connectionString, err := adminClient.ChangeCoordinators(coordinatorAddresses)
+		session.InvalidateStatus()
 		if err != nil {
 			return false, err
 		}
@@ -135,3 +138,146 @@ func (c ChangeCoordinators) Reconcile(r *FoundationDBClusterReconciler, context
 func (c ChangeCoordinators) RequeueAfter() time.Duration {
 	return 0
 }
+
+// chooseCoordinators selects a new set of coordinators from the processes in
+// the cluster status, following the coordinator selection settings in the
+// cluster spec.
+//
+// Processes on instances in the excluded map will not be chosen.
+func chooseCoordinators(cluster *fdbtypes.FoundationDBCluster, status *fdbtypes.FoundationDBStatus, excludedInstances map[string]bool) ([]localityInfo, error) {
+	currentCoordinators := make(map[string]bool, len(status.Client.Coordinators.Coordinators))
+	if cluster.ShouldPreserveHealthyCoordinators() {
+		for _, coordinator := range status.Client.Coordinators.Coordinators {
+			if coordinator.Reachable {
+				currentCoordinators[normalizeAddress(coordinator.Address)] = true
+			}
+		}
+	}
+
+	candidates := make([]localityInfo, 0, len(status.Cluster.Processes))
+	priorities := make(map[string]int, len(status.Cluster.Processes))
+	for _, process := range status.Cluster.Processes {
+		instanceID := process.Locality["instance_id"]
+		priority, eligible := getCoordinatorPriority(cluster, process.ProcessClass)
+		eligible = eligible && !process.Excluded && !cluster.InstanceIsBeingRemoved(instanceID) && !excludedInstances[instanceID]
+		if eligible {
+			candidates = append(candidates, localityInfoForProcess(process))
+			priorities[instanceID] = priority
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		current1 := currentCoordinators[normalizeAddress(candidates[i].Address)]
+		current2 := currentCoordinators[normalizeAddress(candidates[j].Address)]
+		if current1 != current2 {
+			return current1
+		}
+		priority1 := priorities[candidates[i].ID]
+		priority2 := priorities[candidates[j].ID]
+		if priority1 != priority2 {
+			return priority1 > priority2
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+
+	constraint := getCoordinatorSelectionConstraint(cluster)
+	constraint.HardLimits = map[string]int{"zoneid": 1}
+	return chooseDistributedProcesses(candidates, cluster.DesiredCoordinatorCount(), constraint)
+}
+
+// getCoordinatorPriority determines whether a process class is eligible to
+// serve as a coordinator, and what priority it has relative to other process
+// classes.
+//
+// If the cluster spec does not specify any process classes for coordinator
+// selection, all stateful processes are eligible with equal priority.
+func getCoordinatorPriority(cluster *fdbtypes.FoundationDBCluster, processClass string) (int, bool) {
+	settings := cluster.Spec.CoordinatorSelection.ProcessClasses
+	if len(settings) == 0 {
+		return 0, isStateful(processClass)
+	}
+
+	for _, setting := range settings {
+		if setting.ProcessClass == processClass {
+			return setting.Priority, true
+		}
+	}
+	return 0, false
+}
+
+// getCoordinatorSelectionConstraint builds the constraint for spreading
+// coordinators across zones, data centers, and the extra locality fields in
+// the cluster spec.
+func getCoordinatorSelectionConstraint(cluster *fdbtypes.FoundationDBCluster) processSelectionConstraint {
+	fields := []string{"zoneid", "dcid"}
+	seenFields := map[string]bool{"zoneid": true, "dcid": true}
+	for _, field := range cluster.Spec.CoordinatorSelection.LocalityFields {
+		if !seenFields[field] {
+			fields = append(fields, field)
+			seenFields[field] = true
+		}
+	}
+	return processSelectionConstraint{Fields: fields}
+}
+
+// getCoordinatorExclusionSelector parses the selector for instances that
+// should not serve as coordinators.
+//
+// This will return nil if the cluster spec does not exclude any instances.
+func getCoordinatorExclusionSelector(cluster *fdbtypes.FoundationDBCluster) (labels.Selector, error) {
+	selector := cluster.Spec.CoordinatorSelection.ExcludedPodSelector
+	if selector == nil {
+		return nil, nil
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+// isExcludedFromCoordinators determines whether an instance matches the
+// selector for instances that should not serve as coordinators.
+func isExcludedFromCoordinators(selector labels.Selector, instance FdbInstance) bool {
+	return selector != nil && instance.Metadata != nil && selector.Matches(labels.Set(instance.Metadata.Labels))
+}
+
+// getCoordinatorExclusions builds a map of the IDs of the instances that
+// should not serve as coordinators.
+func getCoordinatorExclusions(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (map[string]bool, error) {
+	excluded := make(map[string]bool)
+
+	selector, err := getCoordinatorExclusionSelector(cluster)
+	if err != nil || selector == nil {
+		return excluded, err
+	}
+
+	instances, err := r.PodLifecycleManager.GetInstances(r, cluster, context, getPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, instance := range instances {
+		if isExcludedFromCoordinators(selector, instance) {
+			excluded[instance.GetInstanceID()] = true
+		}
+	}
+
+	return excluded, nil
+}
+
+// hasExcludedCoordinators determines whether any of the current coordinators
+// are running on an excluded instance.
+func hasExcludedCoordinators(status *fdbtypes.FoundationDBStatus, excludedInstances map[string]bool) bool {
+	if len(excludedInstances) == 0 {
+		return false
+	}
+
+	coordinators := make(map[string]bool, len(status.Client.Coordinators.Coordinators))
+	for _, coordinator := range status.Client.Coordinators.Coordinators {
+		coordinators[normalizeAddress(coordinator.Address)] = true
+	}
+
+	for _, process := range status.Cluster.Processes {
+		if coordinators[normalizeAddress(process.Address)] && excludedInstances[process.Locality["instance_id"]] {
+			return true
+		}
+	}
+	return false
+}
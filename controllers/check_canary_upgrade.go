@@ -0,0 +1,223 @@
+/*
+ * check_canary_upgrade.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	ctx "context"
+	"fmt"
+	"sort"
+	"time"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// CheckCanaryUpgrade provides a reconciliation step for watching the canary
+// processes during an upgrade, and deciding whether to upgrade the rest of
+// the processes.
+type CheckCanaryUpgrade struct{}
+
+// Reconcile runs the reconciler's work.
+func (c CheckCanaryUpgrade) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
+	canary := cluster.Status.CanaryUpgrade
+	if canary.Version != cluster.Spec.Version || canary.State == fdbtypes.CanaryUpgradePromoted {
+		return true, nil
+	}
+
+	useCanaries, err := usesCanariesForUpgrade(cluster)
+	if err != nil {
+		return false, err
+	}
+	if !useCanaries {
+		return true, nil
+	}
+
+	if canary.State == fdbtypes.CanaryUpgradeHalted {
+		log.Info("Canary upgrade is halted", "namespace", cluster.Namespace, "cluster", cluster.Name, "version", canary.Version, "reason", canary.Message)
+		r.Recorder.Event(cluster, "Normal", "CanaryUpgradeHalted",
+			fmt.Sprintf("Upgrade to version %s is halted: %s", canary.Version, canary.Message))
+		return false, nil
+	}
+
+	status, err := r.getAdminClientSession(cluster).GetStatus()
+	if err != nil {
+		return false, err
+	}
+
+	problem := getCanaryHealthProblem(cluster, status)
+	now := time.Now().Unix()
+	soakPeriod := int64(cluster.GetCanarySoakPeriodSeconds())
+
+	if canary.State == fdbtypes.CanaryUpgradeStarting {
+		if problem != "" {
+			if now-canary.StartTimestamp < CanaryRejoinTimeoutSeconds {
+				log.Info("Waiting for canary processes to rejoin", "namespace", cluster.Namespace, "cluster", cluster.Name, "reason", problem)
+				return false, ReconciliationNotReadyError{message: "Waiting for canary processes to rejoin", retryable: true}
+			}
+			return false, haltCanaryUpgrade(r, context, cluster, problem)
+		}
+
+		log.Info("Canary processes have rejoined", "namespace", cluster.Namespace, "cluster", cluster.Name, "instances", canary.Instances)
+		cluster.Status.CanaryUpgrade.State = fdbtypes.CanaryUpgradeSoaking
+		cluster.Status.CanaryUpgrade.StartTimestamp = now
+		err = r.Status().Update(context, cluster)
+		if err != nil {
+			return false, err
+		}
+		canary = cluster.Status.CanaryUpgrade
+	}
+
+	if problem != "" {
+		return false, haltCanaryUpgrade(r, context, cluster, problem)
+	}
+
+	if now-canary.StartTimestamp < soakPeriod {
+		log.Info("Waiting for canary processes to soak", "namespace", cluster.Namespace, "cluster", cluster.Name, "remainingSeconds", soakPeriod-(now-canary.StartTimestamp))
+		return false, nil
+	}
+
+	log.Info("Promoting canary upgrade", "namespace", cluster.Namespace, "cluster", cluster.Name, "version", canary.Version)
+	r.Recorder.Event(cluster, "Normal", "CanaryUpgradePromoted",
+		fmt.Sprintf("Canary processes ran version %s for %d seconds, upgrading the remaining processes", canary.Version, soakPeriod))
+	cluster.Status.CanaryUpgrade.State = fdbtypes.CanaryUpgradePromoted
+	cluster.Status.CanaryUpgrade.StartTimestamp = now
+	err = r.Status().Update(context, cluster)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RequeueAfter returns the delay before we should run the reconciliation
+// again.
+func (c CheckCanaryUpgrade) RequeueAfter() time.Duration {
+	return time.Duration(30) * time.Second
+}
+
+// haltCanaryUpgrade records that the operator has stopped an upgrade because
+// of a problem with the canary processes.
+func haltCanaryUpgrade(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster, problem string) error {
+	log.Info("Halting canary upgrade", "namespace", cluster.Namespace, "cluster", cluster.Name, "version", cluster.Status.CanaryUpgrade.Version, "reason", problem)
+	r.Recorder.Event(cluster, "Normal", "CanaryUpgradeHalted",
+		fmt.Sprintf("Halting upgrade to version %s: %s", cluster.Status.CanaryUpgrade.Version, problem))
+
+	cluster.Status.CanaryUpgrade.State = fdbtypes.CanaryUpgradeHalted
+	cluster.Status.CanaryUpgrade.StartTimestamp = time.Now().Unix()
+	cluster.Status.CanaryUpgrade.Message = problem
+	return r.Status().Update(context, cluster)
+}
+
+// usesCanariesForUpgrade determines whether the operator should upgrade
+// canary processes before the rest of the processes for the upgrade that is
+// in progress.
+//
+// Canaries can only be used for upgrades between protocol-compatible
+// versions.
+func usesCanariesForUpgrade(cluster *fdbtypes.FoundationDBCluster) (bool, error) {
+	if !cluster.UsesCanaryUpgrades() || !cluster.IsBeingUpgraded() {
+		return false, nil
+	}
+
+	runningVersion, err := fdbtypes.ParseFdbVersion(cluster.Status.RunningVersion)
+	if err != nil {
+		return false, err
+	}
+	desiredVersion, err := fdbtypes.ParseFdbVersion(cluster.Spec.Version)
+	if err != nil {
+		return false, err
+	}
+	return runningVersion.IsProtocolCompatible(desiredVersion), nil
+}
+
+// getCanaryHealthProblem checks whether the canary processes are reporting
+// to the cluster and the cluster is healthy.
+//
+// This returns a description of the first problem it finds, or an empty
+// string if there are no problems.
+func getCanaryHealthProblem(cluster *fdbtypes.FoundationDBCluster, status *fdbtypes.FoundationDBStatus) string {
+	reporting := make(map[string]bool, len(status.Cluster.Processes))
+	for _, process := range status.Cluster.Processes {
+		reporting[process.Locality["instance_id"]] = true
+	}
+
+	for _, instanceID := range cluster.Status.CanaryUpgrade.Instances {
+		if !reporting[instanceID] && !cluster.InstanceIsBeingRemoved(instanceID) {
+			return fmt.Sprintf("canary process %s is not reporting to the cluster", instanceID)
+		}
+	}
+
+	if !status.Client.DatabaseStatus.Available {
+		return "the database is not available"
+	}
+	if !status.Client.DatabaseStatus.Healthy {
+		return "the database is not healthy"
+	}
+	return ""
+}
+
+// getCanaryBatch chooses the instances that we should bounce as canaries,
+// based on the canary settings in the cluster spec.
+//
+// This will return an empty list if none of the instances match the canary
+// settings.
+func getCanaryBatch(cluster *fdbtypes.FoundationDBCluster, instanceIDs []string, instances map[string]FdbInstance) ([]string, error) {
+	var selector labels.Selector
+	if cluster.Spec.CanaryUpgrade.PodSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(cluster.Spec.CanaryUpgrade.PodSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	processClasses := make(map[string]bool, len(cluster.Spec.CanaryUpgrade.ProcessClasses))
+	for _, processClass := range cluster.Spec.CanaryUpgrade.ProcessClasses {
+		processClasses[processClass] = true
+	}
+
+	candidates := make([]string, len(instanceIDs))
+	copy(candidates, instanceIDs)
+	sort.Strings(candidates)
+
+	maxProcesses := cluster.GetCanaryMaxProcesses()
+	batch := make([]string, 0, maxProcesses)
+	for _, instanceID := range candidates {
+		instance, present := instances[instanceID]
+		if !present {
+			continue
+		}
+		if len(processClasses) > 0 && !processClasses[instance.GetProcessClass()] {
+			continue
+		}
+		if selector != nil && (instance.Metadata == nil || !selector.Matches(labels.Set(instance.Metadata.Labels))) {
+			continue
+		}
+
+		batch = append(batch, instanceID)
+		if len(batch) >= maxProcesses {
+			break
+		}
+	}
+
+	return batch, nil
+}
@@ -39,11 +39,11 @@ func (c CheckClientCompatibility) Reconcile(r *FoundationDBClusterReconciler, co
 		return true, nil
 	}
 
-	adminClient, err := r.AdminClientProvider(cluster, r)
+	session := r.getAdminClientSession(cluster)
+	adminClient, err := session.GetAdminClient()
 	if err != nil {
 		return false, err
 	}
-	defer adminClient.Close()
 
 	runningVersion, err := fdbtypes.ParseFdbVersion(cluster.Status.RunningVersion)
 	if err != nil {
@@ -55,15 +55,20 @@ func (c CheckClientCompatibility) Reconcile(r *FoundationDBClusterReconciler, co
 		return false, err
 	}
 
-	if !version.IsAtLeast(runningVersion) {
-		return false, fmt.Errorf("cluster downgrade operation is not supported")
+	if !version.IsAtLeast(runningVersion) && !version.IsProtocolCompatible(runningVersion) && !cluster.Spec.AllowIncompatibleDowngrade {
+		return false, fmt.Errorf("cluster downgrade operation is only supported between protocol-compatible versions")
+	}
+
+	err = fdbtypes.CheckUpgradePath(runningVersion, version)
+	if err != nil {
+		return false, err
 	}
 
 	if version.IsProtocolCompatible(runningVersion) {
 		return true, nil
 	}
 
-	status, err := adminClient.GetStatus()
+	status, err := session.GetStatus()
 	if err != nil {
 		return false, err
 	}
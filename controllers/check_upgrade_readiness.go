@@ -0,0 +1,166 @@
+/*
+ * check_upgrade_readiness.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	ctx "context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+)
+
+// CheckUpgradeReadiness provides a reconciliation step for confirming that
+// every pod has the binaries for the new version before we bounce the
+// processes during an upgrade.
+//
+// This only checks the binaries. BounceProcesses makes sure the monitor conf
+// is up to date before it bounces a process.
+type CheckUpgradeReadiness struct{}
+
+// Reconcile runs the reconciler's work.
+func (c CheckUpgradeReadiness) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
+	laggingPods := make([]string, 0)
+
+	if cluster.IsBeingUpgraded() {
+		instances, err := r.PodLifecycleManager.GetInstances(r, cluster, context, getPodListOptions(cluster, "", "")...)
+		if err != nil {
+			return false, err
+		}
+
+		binaryHashes := make(map[string]map[string]string, len(instances))
+		for _, instance := range instances {
+			if instance.Pod == nil || cluster.InstanceIsBeingRemoved(instance.GetInstanceID()) {
+				continue
+			}
+
+			hashes, err := getUpgradeBinaryHashes(r, cluster, instance)
+			if err != nil {
+				log.Error(err, "Error checking files for upgrade", "namespace", cluster.Namespace, "cluster", cluster.Name, "pod", instance.Metadata.Name)
+			}
+			if hashes == nil {
+				laggingPods = append(laggingPods, instance.Metadata.Name)
+			} else {
+				binaryHashes[instance.Metadata.Name] = hashes
+			}
+		}
+
+		mismatchedPods := getPodsWithMismatchedBinaries(binaryHashes)
+		if len(mismatchedPods) > 0 {
+			log.Info("Found pods whose binaries do not match the other pods", "namespace", cluster.Namespace, "cluster", cluster.Name, "version", cluster.Spec.Version, "pods", mismatchedPods)
+			laggingPods = append(laggingPods, mismatchedPods...)
+		}
+		sort.Strings(laggingPods)
+	}
+
+	if len(laggingPods) == 0 {
+		laggingPods = nil
+	}
+
+	if !reflect.DeepEqual(laggingPods, cluster.Status.UpgradeLaggingPods) {
+		cluster.Status.UpgradeLaggingPods = laggingPods
+		err := r.Status().Update(context, cluster)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if len(laggingPods) > 0 {
+		log.Info("Waiting for pods to have the files for the new version", "namespace", cluster.Namespace, "cluster", cluster.Name, "version", cluster.Spec.Version, "pods", laggingPods)
+		r.Recorder.Event(cluster, "Normal", "UpgradeFilesNotReady",
+			fmt.Sprintf("Waiting for pods to have the files for version %s: %s", cluster.Spec.Version, strings.Join(laggingPods, ", ")))
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// RequeueAfter returns the delay before we should run the reconciliation
+// again.
+func (c CheckUpgradeReadiness) RequeueAfter() time.Duration {
+	return time.Duration(30) * time.Second
+}
+
+// getUpgradeBinaryHashes gets the hashes of the binaries in a pod for the
+// version that the cluster is upgrading to, indexed by the binary name.
+//
+// This will return nil if any of the binaries are missing.
+func getUpgradeBinaryHashes(r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster, instance FdbInstance) (map[string]string, error) {
+	client, err := r.getPodClient(cluster, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[string]string, 2)
+	for _, binary := range []string{"fdbserver", "fdbcli"} {
+		path := fmt.Sprintf("bin/%s/%s", cluster.Spec.Version, binary)
+		present, err := CheckDynamicFilePresent(client, path)
+		if !present {
+			return nil, err
+		}
+
+		hashes[binary], err = client.GetHash(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return hashes, nil
+}
+
+// getPodsWithMismatchedBinaries finds the pods whose binaries do not match the
+// binaries that most of the pods have.
+//
+// Every pod copies the binaries from the same sidecar image, so a pod with a
+// different hash has an incomplete or corrupted copy. The input should map
+// the pod names to the binary hashes from getUpgradeBinaryHashes.
+func getPodsWithMismatchedBinaries(binaryHashes map[string]map[string]string) []string {
+	mismatched := make(map[string]bool)
+	for _, binary := range []string{"fdbserver", "fdbcli"} {
+		counts := make(map[string]int)
+		for _, hashes := range binaryHashes {
+			counts[hashes[binary]]++
+		}
+
+		expectedHash := ""
+		for hash, count := range counts {
+			if count > counts[expectedHash] || (count == counts[expectedHash] && hash < expectedHash) {
+				expectedHash = hash
+			}
+		}
+
+		for podName, hashes := range binaryHashes {
+			if hashes[binary] != expectedHash {
+				mismatched[podName] = true
+			}
+		}
+	}
+
+	podNames := make([]string, 0, len(mismatched))
+	for podName := range mismatched {
+		podNames = append(podNames, podName)
+	}
+	sort.Strings(podNames)
+	return podNames
+}
@@ -32,11 +32,13 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -62,6 +64,9 @@ type FoundationDBClusterReconciler struct {
 	LockClientProvider  LockClientProvider
 	lockClients         map[string]LockClient
 	UseFutureDefaults   bool
+
+	adminClientSessions      map[string]*adminClientSession
+	adminClientSessionsMutex sync.Mutex
 }
 
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclusters,verbs=get;list;watch;create;update;patch;delete
@@ -88,21 +93,31 @@ func (r *FoundationDBClusterReconciler) Reconcile(request ctrl.Request) (ctrl.Re
 		return ctrl.Result{}, err
 	}
 
-	NormalizeClusterSpec(&cluster.Spec, defaultsSelection{UseFutureDefaults: r.UseFutureDefaults})
+	fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{UseFutureDefaults: r.UseFutureDefaults})
 	normalizedSpec := cluster.Spec.DeepCopy()
 
-	adminClient, err := r.AdminClientProvider(cluster, r)
+	err = r.endAdminClientSession(cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		err := r.endAdminClientSession(cluster)
+		if err != nil {
+			log.Error(err, "Error closing admin client", "namespace", cluster.Namespace, "cluster", cluster.Name)
+		}
+	}()
+
+	adminClient, err := r.getAdminClientSession(cluster).GetAdminClient()
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	defer adminClient.Close()
 
 	supportedVersion, err := adminClient.VersionSupported(cluster.Spec.Version)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	if !supportedVersion {
-		return ctrl.Result{}, fmt.Errorf("Version %s is not supported", cluster.Spec.Version)
+		return ctrl.Result{}, fmt.Errorf("Version %s is not supported, the supported versions are %s", cluster.Spec.Version, fdbtypes.GetSupportedVersionDescription())
 	}
 
 	subReconcilers := []ClusterSubReconciler{
@@ -110,6 +125,8 @@ func (r *FoundationDBClusterReconciler) Reconcile(request ctrl.Request) (ctrl.Re
 		CheckClientCompatibility{},
 		CheckInstancesToRemove{},
 		ReplaceMisconfiguredPods{},
+		ReplaceFailedPods{},
+		AutoscaleStorage{},
 		AddServices{},
 		AddPods{},
 		GenerateInitialClusterFile{},
@@ -121,10 +138,13 @@ func (r *FoundationDBClusterReconciler) Reconcile(request ctrl.Request) (ctrl.Re
 		ExcludeInstances{},
 		ChangeCoordinators{},
 		ConfirmExclusionCompletion{},
+		CheckUpgradeReadiness{},
+		CheckCanaryUpgrade{},
 		BounceProcesses{},
 		UpdatePods{},
-		RemoveServices{},
+		UpdatePodDisruptionBudgets{},
 		RemovePods{},
+		RemoveServices{},
 		IncludeInstances{},
 		UpdateStatus{},
 	}
@@ -161,7 +181,14 @@ func (r *FoundationDBClusterReconciler) Reconcile(request ctrl.Request) (ctrl.Re
 
 	log.Info("Reconciliation complete", "namespace", cluster.Namespace, "cluster", cluster.Name)
 
-	return ctrl.Result{}, nil
+	now := time.Now().Unix()
+	delay := getReplacementCheckDelay(cluster, now)
+	autoscalingDelay := getAutoscalingCheckDelay(cluster, now)
+	if delay == 0 || (autoscalingDelay > 0 && autoscalingDelay < delay) {
+		delay = autoscalingDelay
+	}
+
+	return ctrl.Result{RequeueAfter: delay}, nil
 }
 
 // SetupWithManager prepares a reconciler for use.
@@ -192,6 +219,8 @@ func (r *FoundationDBClusterReconciler) SetupWithManager(mgr ctrl.Manager) error
 		Owns(&corev1.Pod{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Service{}).
+		Owns(&policyv1beta1.PodDisruptionBudget{}).
 		Complete(r)
 }
 
@@ -205,6 +234,11 @@ func (r *FoundationDBClusterReconciler) checkRetryableError(err error) (ctrl.Res
 		log.Info("Retrying reconciliation", "reason", "Conflict")
 		return ctrl.Result{Requeue: true}, nil
 	}
+	var adminError AdminClientError
+	if errors.As(err, &adminError) && adminError.Retryable() {
+		log.Info("Retrying reconciliation", "reason", adminError.Reason, "error", adminError.Error())
+		return ctrl.Result{Requeue: true, RequeueAfter: adminError.RequeueAfter()}, nil
+	}
 
 	return ctrl.Result{}, err
 }
@@ -414,10 +448,15 @@ func GetConfigMap(context ctx.Context, cluster *fdbtypes.FoundationDBCluster, ku
 	}
 	needsInstanceIDSubstitution := !version.HasInstanceIDInSidecarSubstitutions()
 
+	usesServiceIPs := cluster.GetPublicIPSource() == fdbtypes.PublicIPSourceService
+
 	substitutionCount := len(cluster.Spec.SidecarVariables)
 	if needsInstanceIDSubstitution {
 		substitutionCount++
 	}
+	if usesServiceIPs {
+		substitutionCount++
+	}
 
 	var substitutionKeys []string
 
@@ -428,6 +467,10 @@ func GetConfigMap(context ctx.Context, cluster *fdbtypes.FoundationDBCluster, ku
 		if needsInstanceIDSubstitution {
 			substitutionKeys = append(substitutionKeys, "FDB_INSTANCE_ID")
 		}
+
+		if usesServiceIPs {
+			substitutionKeys = append(substitutionKeys, "FDB_POD_IP")
+		}
 	}
 
 	filesToCopy := []string{"fdb.cluster"}
@@ -593,6 +636,10 @@ func getStartCommandLines(cluster *fdbtypes.FoundationDBCluster, processClass st
 		fmt.Sprintf("locality_zoneid = %s", zoneVariable),
 	)
 
+	if cluster.GetPublicIPSource() == fdbtypes.PublicIPSourceService {
+		confLines = append(confLines, fmt.Sprintf("listen_address = %s", cluster.GetFullAddressList("$FDB_POD_IP", false)))
+	}
+
 	if cluster.Spec.DataCenter != "" {
 		confLines = append(confLines, fmt.Sprintf("locality_dcid = %s", cluster.Spec.DataCenter))
 	}
@@ -705,7 +752,9 @@ func (r *FoundationDBClusterReconciler) getPendingRemovalState(instance FdbInsta
 	}
 	if instance.Pod != nil {
 		var ip string
-		if r.PodIPProvider == nil {
+		if instance.Pod.ObjectMeta.Annotations[PublicIPAnnotation] != "" {
+			ip = instance.Pod.ObjectMeta.Annotations[PublicIPAnnotation]
+		} else if r.PodIPProvider == nil {
 			ip = instance.Pod.Status.PodIP
 		} else {
 			ip = r.PodIPProvider(instance.Pod)
@@ -731,8 +780,10 @@ func (r *FoundationDBClusterReconciler) clearPendingRemovalsFromSpec(context ctx
 // updatePendingRemovals processes an update to the pending removals for the
 // cluster.
 //
-// This will update both the status and the config map.
+// This will update the status, including the removal conditions on the
+// process groups, and the config map.
 func (r *FoundationDBClusterReconciler) updatePendingRemovals(context ctx.Context, cluster *fdbtypes.FoundationDBCluster) error {
+	cluster.Status.UpdateProcessGroupRemovals()
 	err := r.Status().Update(context, cluster)
 	if err != nil {
 		return err
@@ -906,13 +957,7 @@ func (manager StandardPodLifecycleManager) DeleteInstance(r *FoundationDBCluster
 
 // CanDeletePods checks whether it is safe to delete pods.
 func (manager StandardPodLifecycleManager) CanDeletePods(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
-	adminClient, err := r.AdminClientProvider(cluster, r)
-	if err != nil {
-		return false, err
-	}
-	defer adminClient.Close()
-
-	status, err := adminClient.GetStatus()
+	status, err := r.getAdminClientSession(cluster).GetStatus()
 	if err != nil {
 		return false, err
 	}
@@ -1021,7 +1066,8 @@ type ClusterSubReconciler interface {
 
 // MinimumFDBVersion defines the minimum supported FDB version.
 func MinimumFDBVersion() fdbtypes.FdbVersion {
-	return fdbtypes.FdbVersion{Major: 6, Minor: 1, Patch: 12}
+	series := fdbtypes.SupportedVersionSeries[0]
+	return fdbtypes.FdbVersion{Major: series.Major, Minor: series.Minor, Patch: series.MinimumPatch}
 }
 
 // localityInfo captures information about a process for the purposes of
@@ -1104,6 +1150,7 @@ func chooseDistributedProcesses(processes []localityInfo, count int, constraint
 	if len(fields) == 0 {
 		fields = []string{"zoneid", "dcid"}
 	}
+	fields = filterSpreadableFields(processes, fields, constraint.HardLimits)
 
 	chosenCounts := make(map[string]map[string]int, len(fields))
 	hardLimits := make(map[string]int, len(fields))
@@ -1173,6 +1220,29 @@ func chooseDistributedProcesses(processes []localityInfo, count int, constraint
 	return chosen, nil
 }
 
+// filterSpreadableFields removes the locality fields that have the same value
+// for every process, unless they have a hard limit.
+//
+// These fields cannot spread the processes out, and keeping them would force
+// us to relax the limits on every field after them before we could choose a
+// second process.
+func filterSpreadableFields(processes []localityInfo, fields []string, hardLimits map[string]int) []string {
+	filteredFields := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if hardLimits[field] > 0 {
+			filteredFields = append(filteredFields, field)
+			continue
+		}
+		for _, process := range processes {
+			if process.LocalityData[field] != processes[0].LocalityData[field] {
+				filteredFields = append(filteredFields, field)
+				break
+			}
+		}
+	}
+	return filteredFields
+}
+
 // checkCoordinatorValidity determines if the cluster's current coordinators
 // meet the fault tolerance requirements.
 //
@@ -1184,7 +1254,7 @@ func chooseDistributedProcesses(processes []localityInfo, count int, constraint
 func checkCoordinatorValidity(cluster *fdbtypes.FoundationDBCluster, status *fdbtypes.FoundationDBStatus) (bool, bool, error) {
 	coordinatorStatus := make(map[string]bool, len(status.Client.Coordinators.Coordinators))
 	for _, coordinator := range status.Client.Coordinators.Coordinators {
-		coordinatorStatus[coordinator.Address] = false
+		coordinatorStatus[normalizeAddress(coordinator.Address)] = false
 	}
 
 	if len(coordinatorStatus) == 0 {
@@ -1202,10 +1272,15 @@ func checkCoordinatorValidity(cluster *fdbtypes.FoundationDBCluster, status *fdb
 	}
 
 	for _, process := range status.Cluster.Processes {
-		_, isCoordinator := coordinatorStatus[process.Address]
+		processAddress := normalizeAddress(process.Address)
+		_, isCoordinator := coordinatorStatus[processAddress]
 		_, pendingRemoval := removals[process.Locality["instance_id"]]
-		if isCoordinator && !process.Excluded && !pendingRemoval {
-			coordinatorStatus[process.Address] = true
+		eligibleClass := true
+		if len(cluster.Spec.CoordinatorSelection.ProcessClasses) > 0 {
+			_, eligibleClass = getCoordinatorPriority(cluster, process.ProcessClass)
+		}
+		if isCoordinator && !process.Excluded && !pendingRemoval && eligibleClass {
+			coordinatorStatus[processAddress] = true
 		}
 
 		if isCoordinator {
@@ -1258,3 +1333,16 @@ func checkCoordinatorValidity(cluster *fdbtypes.FoundationDBCluster, status *fdb
 
 	return coordinatorsValid, allAddressesValid, nil
 }
+
+// normalizeAddress converts a process address into a canonical form, so that
+// addresses that refer to the same process can be compared as strings. This
+// is needed for IPv6 addresses, which can be written in multiple ways.
+//
+// If the address cannot be parsed, this will return it unchanged.
+func normalizeAddress(address string) string {
+	parsed, err := fdbtypes.ParseProcessAddress(address)
+	if err != nil {
+		return address
+	}
+	return parsed.String()
+}
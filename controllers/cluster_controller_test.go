@@ -37,10 +37,12 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
@@ -173,6 +175,11 @@ var _ = Describe("cluster_controller", func() {
 				Expect(len(services.Items)).To(Equal(0))
 			})
 
+			It("should set the selector for the storage pods in the status", func() {
+				Expect(cluster.Status.StorageProcessSelector).To(Equal("fdb-cluster-name=operator-test-1,fdb-process-class=storage"))
+				Expect(cluster.Status.ProcessCounts.Storage).To(Equal(4))
+			})
+
 			It("should fill in the required fields in the configuration", func() {
 				Expect(cluster.Status.DatabaseConfiguration.RedundancyMode).To(Equal("double"))
 				Expect(cluster.Status.DatabaseConfiguration.StorageEngine).To(Equal("ssd-2"))
@@ -188,7 +195,7 @@ var _ = Describe("cluster_controller", func() {
 			})
 
 			It("should send the configuration to the cluster", func() {
-				adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+				adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(adminClient).NotTo(BeNil())
 				Expect(adminClient.DatabaseConfiguration.RedundancyMode).To(Equal("double"))
@@ -203,7 +210,7 @@ var _ = Describe("cluster_controller", func() {
 			})
 
 			It("should update the status with the reconciliation result", func() {
-				adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+				adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(cluster.Status.Generations.Reconciled).To(Equal(int64(1)))
@@ -220,6 +227,13 @@ var _ = Describe("cluster_controller", func() {
 				Expect(cluster.Status.IncorrectProcesses).To(BeNil())
 				Expect(cluster.Status.MissingProcesses).To(BeNil())
 
+				Expect(cluster.Status.ProcessGroups).To(HaveLen(17))
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					Expect(processGroup.Addresses).To(HaveLen(1))
+					Expect(processGroup.Conditions).To(BeNil())
+				}
+				Expect(cluster.Status.GetProcessGroup("storage-1").ProcessClass).To(Equal("storage"))
+
 				status, err := adminClient.GetStatus()
 				Expect(err).NotTo(HaveOccurred())
 
@@ -266,7 +280,7 @@ var _ = Describe("cluster_controller", func() {
 				Expect(cluster.Spec.InstancesToRemove).To(BeNil())
 				Expect(cluster.Status.PendingRemovals).To(BeNil())
 
-				adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+				adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(adminClient).NotTo(BeNil())
 				Expect(adminClient.ExcludedAddresses).To(BeNil())
@@ -437,7 +451,7 @@ var _ = Describe("cluster_controller", func() {
 				})
 
 				It("should exclude and re-include the process", func() {
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(adminClient).NotTo(BeNil())
 					Expect(adminClient.ExcludedAddresses).To(BeNil())
@@ -508,7 +522,7 @@ var _ = Describe("cluster_controller", func() {
 				})
 
 				It("should exclude and re-include the process", func() {
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(adminClient).NotTo(BeNil())
 					Expect(adminClient.ExcludedAddresses).To(BeNil())
@@ -571,7 +585,7 @@ var _ = Describe("cluster_controller", func() {
 				})
 
 				It("should exclude and re-include the process", func() {
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(adminClient).NotTo(BeNil())
 					Expect(adminClient.ExcludedAddresses).To(BeNil())
@@ -624,7 +638,7 @@ var _ = Describe("cluster_controller", func() {
 				})
 
 				It("should not exclude anything", func() {
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 					Expect(adminClient).NotTo(BeNil())
 					Expect(adminClient.ExcludedAddresses).To(BeNil())
@@ -700,7 +714,7 @@ var _ = Describe("cluster_controller", func() {
 
 			BeforeEach(func() {
 
-				adminClient, err = newMockAdminClientUncast(cluster, k8sClient)
+				adminClient, err = NewMockAdminClientUncast(cluster, k8sClient)
 				Expect(err).NotTo(HaveOccurred())
 				err = adminClient.FreezeStatus()
 				Expect(err).NotTo(HaveOccurred())
@@ -771,7 +785,7 @@ var _ = Describe("cluster_controller", func() {
 		Context("with a configuration change", func() {
 			var adminClient *MockAdminClient
 			BeforeEach(func() {
-				adminClient, err = newMockAdminClientUncast(cluster, k8sClient)
+				adminClient, err = NewMockAdminClientUncast(cluster, k8sClient)
 				Expect(err).NotTo(HaveOccurred())
 
 				status, err := adminClient.GetStatus()
@@ -851,7 +865,7 @@ var _ = Describe("cluster_controller", func() {
 				})
 
 				It("should not change the database configuration", func() {
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(adminClient.DatabaseConfiguration.RedundancyMode).To(Equal("double"))
@@ -962,7 +976,7 @@ var _ = Describe("cluster_controller", func() {
 				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
 				Expect(err).NotTo(HaveOccurred())
 
-				NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+				fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 				for _, item := range pods.Items {
 					_, id, err := ParseInstanceID(item.Labels["fdb-instance-id"])
 					Expect(err).NotTo(HaveOccurred())
@@ -1176,7 +1190,7 @@ var _ = Describe("cluster_controller", func() {
 				It("should not update the annotations on other resources", func() {
 					pods := &corev1.PodList{}
 
-					NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+					fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 					err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
 					Expect(err).NotTo(HaveOccurred())
 					for _, item := range pods.Items {
@@ -1252,7 +1266,7 @@ var _ = Describe("cluster_controller", func() {
 					err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
 					Expect(err).NotTo(HaveOccurred())
 
-					NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+					fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 
 					for _, item := range pods.Items {
 						_, id, err := ParseInstanceID(item.Labels["fdb-instance-id"])
@@ -1296,7 +1310,7 @@ var _ = Describe("cluster_controller", func() {
 						},
 					}
 
-					NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+					fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 					err := k8sClient.Update(context.TODO(), cluster)
 					Expect(err).NotTo(HaveOccurred())
 				})
@@ -1431,7 +1445,7 @@ var _ = Describe("cluster_controller", func() {
 				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
 				Expect(err).NotTo(HaveOccurred())
 
-				NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+				fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 
 				for _, item := range pods.Items {
 					_, id, err := ParseInstanceID(item.Labels["fdb-instance-id"])
@@ -1498,6 +1512,16 @@ var _ = Describe("cluster_controller", func() {
 						Expect(pod.Spec.Containers[0].Env[0].Value).To(Equal("1"))
 					}
 				})
+
+				It("should put the zone into maintenance mode while recreating the pods", func() {
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(adminClient.MaintenanceZoneHistory).To(Equal([]string{"simulation"}))
+					Eventually(func() (string, error) {
+						_, err := reloadCluster(cluster)
+						return cluster.Status.MaintenanceZone, err
+					}, timeout).Should(Equal(""))
+				})
 			})
 
 			Context("with the replacement strategy", func() {
@@ -1521,7 +1545,7 @@ var _ = Describe("cluster_controller", func() {
 				})
 
 				It("should replace the processes", func() {
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 
 					replacements := make(map[string]bool, len(originalPods.Items))
@@ -1637,7 +1661,7 @@ var _ = Describe("cluster_controller", func() {
 					addresses[fmt.Sprintf("%s:4500:tls", MockPodIP(&pod))] = true
 				}
 
-				adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+				adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 				Expect(err).NotTo(HaveOccurred())
 
 				killedAddresses := make(map[string]bool, len(adminClient.KilledAddresses))
@@ -1660,18 +1684,59 @@ var _ = Describe("cluster_controller", func() {
 		})
 
 		Context("downgrade cluster", func() {
-			BeforeEach(func() {
-				generationGap = 0
-				IncompatibleVersion := Versions.Default
-				IncompatibleVersion.Patch--
-				cluster.Spec.Version = IncompatibleVersion.String()
-				err := k8sClient.Update(context.TODO(), cluster)
-				Expect(err).NotTo(HaveOccurred())
+			Context("with a protocol-compatible version", func() {
+				var compatibleVersion fdbtypes.FdbVersion
+
+				BeforeEach(func() {
+					timeout = 120 * time.Second
+					compatibleVersion = Versions.Default
+					compatibleVersion.Patch--
+					cluster.Spec.Version = compatibleVersion.String()
+					err := k8sClient.Update(context.TODO(), cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should downgrade cluster", func() {
+					Expect(cluster.Status.Generations.Reconciled).To(Equal(originalVersion + 1))
+					Expect(cluster.Status.RunningVersion).To(Equal(compatibleVersion.String()))
+				})
 			})
 
-			It("should not downgrade cluster", func() {
-				Expect(cluster.Status.Generations.Reconciled).To(Equal(originalVersion))
-				Expect(cluster.Status.RunningVersion).To(Equal(Versions.Default.String()))
+			Context("with an incompatible version", func() {
+				var incompatibleVersion fdbtypes.FdbVersion
+
+				BeforeEach(func() {
+					incompatibleVersion = Versions.Default
+					incompatibleVersion.Minor--
+					cluster.Spec.Version = incompatibleVersion.String()
+				})
+
+				Context("without an acknowledgement", func() {
+					BeforeEach(func() {
+						generationGap = 0
+						err := k8sClient.Update(context.TODO(), cluster)
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("should not downgrade cluster", func() {
+						Expect(cluster.Status.Generations.Reconciled).To(Equal(originalVersion))
+						Expect(cluster.Status.RunningVersion).To(Equal(Versions.Default.String()))
+					})
+				})
+
+				Context("with an acknowledgement", func() {
+					BeforeEach(func() {
+						timeout = 120 * time.Second
+						cluster.Spec.AllowIncompatibleDowngrade = true
+						err := k8sClient.Update(context.TODO(), cluster)
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("should downgrade cluster", func() {
+						Expect(cluster.Status.Generations.Reconciled).To(Equal(originalVersion + 1))
+						Expect(cluster.Status.RunningVersion).To(Equal(incompatibleVersion.String()))
+					})
+				})
 			})
 		})
 
@@ -1679,12 +1744,26 @@ var _ = Describe("cluster_controller", func() {
 			var adminClient *MockAdminClient
 
 			BeforeEach(func() {
-				cluster.Spec.Version = Versions.NextMajorVersion.String()
+				cluster.Spec.Version = Versions.NextMinorVersion.String()
 
-				adminClient, err = newMockAdminClientUncast(cluster, k8sClient)
+				adminClient, err = NewMockAdminClientUncast(cluster, k8sClient)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
+			Context("with a version that skips a release series", func() {
+				BeforeEach(func() {
+					generationGap = 0
+					cluster.Spec.Version = Versions.NextMajorVersion.String()
+					err = k8sClient.Update(context.TODO(), cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should not upgrade the cluster", func() {
+					Expect(cluster.Status.Generations.Reconciled).To(Equal(originalVersion))
+					Expect(cluster.Status.RunningVersion).To(Equal(Versions.Default.String()))
+				})
+			})
+
 			Context("with the default strategy", func() {
 				BeforeEach(func() {
 					timeout = 120 * time.Second
@@ -1698,7 +1777,7 @@ var _ = Describe("cluster_controller", func() {
 						addresses[fmt.Sprintf("%s:4501", MockPodIP(&pod))] = true
 					}
 
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 
 					killedAddresses := make(map[string]bool, len(adminClient.KilledAddresses))
@@ -1714,13 +1793,61 @@ var _ = Describe("cluster_controller", func() {
 					Expect(err).NotTo(HaveOccurred())
 
 					for _, pod := range pods.Items {
-						Expect(pod.Spec.Containers[0].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb:%s", Versions.NextMajorVersion.String())))
+						Expect(pod.Spec.Containers[0].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb:%s", Versions.NextMinorVersion.String())))
+					}
+				})
+
+				It("should update the running version", func() {
+					Expect(cluster.Status.RunningVersion).To(Equal(cluster.Spec.Version))
+				})
+			})
+
+			Context("with canary upgrades", func() {
+				BeforeEach(func() {
+					timeout = 120 * time.Second
+					soakPeriod := 0
+					cluster.Spec.Version = Versions.NextPatchVersion.String()
+					cluster.Spec.CanaryUpgrade = fdbtypes.CanaryUpgradeConfig{
+						ProcessClasses:    []string{"cluster_controller"},
+						SoakPeriodSeconds: &soakPeriod,
+					}
+					err = k8sClient.Update(context.TODO(), cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should bounce the canary first", func() {
+					var canaryAddress string
+					for _, pod := range originalPods.Items {
+						if GetInstanceIDFromMeta(pod.ObjectMeta) == "cluster_controller-1" {
+							canaryAddress = fmt.Sprintf("%s:4501", MockPodIP(&pod))
+						}
+					}
+					Expect(canaryAddress).NotTo(Equal(""))
+
+					Expect(adminClient.KilledAddresses).NotTo(BeEmpty())
+					Expect(adminClient.KilledAddresses[0]).To(Equal(canaryAddress))
+				})
+
+				It("should bounce the processes", func() {
+					addresses := make(map[string]bool, len(originalPods.Items))
+					for _, pod := range originalPods.Items {
+						addresses[fmt.Sprintf("%s:4501", MockPodIP(&pod))] = true
+					}
+
+					killedAddresses := make(map[string]bool, len(adminClient.KilledAddresses))
+					for _, address := range adminClient.KilledAddresses {
+						killedAddresses[address] = true
 					}
+					Expect(killedAddresses).To(Equal(addresses))
 				})
 
 				It("should update the running version", func() {
 					Expect(cluster.Status.RunningVersion).To(Equal(cluster.Spec.Version))
 				})
+
+				It("should clear the canary status", func() {
+					Expect(cluster.Status.CanaryUpgrade).To(Equal(fdbtypes.CanaryUpgradeStatus{}))
+				})
 			})
 
 			Context("with the replacement strategy", func() {
@@ -1737,7 +1864,7 @@ var _ = Describe("cluster_controller", func() {
 						addresses[fmt.Sprintf("%s:4501", MockPodIP(&pod))] = true
 					}
 
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 
 					killedAddresses := make(map[string]bool, len(adminClient.KilledAddresses))
@@ -1753,12 +1880,12 @@ var _ = Describe("cluster_controller", func() {
 					Expect(err).NotTo(HaveOccurred())
 
 					for _, pod := range pods.Items {
-						Expect(pod.Spec.Containers[0].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb:%s", Versions.NextMajorVersion.String())))
+						Expect(pod.Spec.Containers[0].Image).To(Equal(fmt.Sprintf("foundationdb/foundationdb:%s", Versions.NextMinorVersion.String())))
 					}
 				})
 
 				It("should replace the processes", func() {
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 
 					replacements := make(map[string]bool, len(originalPods.Items))
@@ -1772,7 +1899,7 @@ var _ = Describe("cluster_controller", func() {
 
 			Context("with all upgradable clients", func() {
 				BeforeEach(func() {
-					adminClient.MockClientVersion(Versions.NextMajorVersion.String(), []string{"127.0.0.2:3687"})
+					adminClient.MockClientVersion(Versions.NextMinorVersion.String(), []string{"127.0.0.2:3687"})
 					timeout = 120 * time.Second
 					err = k8sClient.Update(context.TODO(), cluster)
 					Expect(err).NotTo(HaveOccurred())
@@ -1799,7 +1926,7 @@ var _ = Describe("cluster_controller", func() {
 
 			Context("with a non-upgradable client", func() {
 				BeforeEach(func() {
-					adminClient.MockClientVersion(Versions.NextMajorVersion.String(), []string{"127.0.0.2:3687"})
+					adminClient.MockClientVersion(Versions.NextMinorVersion.String(), []string{"127.0.0.2:3687"})
 					adminClient.MockClientVersion(Versions.Default.String(), []string{"127.0.0.3:85891"})
 				})
 
@@ -1829,7 +1956,7 @@ var _ = Describe("cluster_controller", func() {
 						}).ShouldNot(Equal(0))
 
 						Expect(matchingEvents[0].Message).To(Equal(
-							fmt.Sprintf("1 clients do not support version %s: 127.0.0.3:85891", Versions.NextMajorVersion),
+							fmt.Sprintf("1 clients do not support version %s: 127.0.0.3:85891", Versions.NextMinorVersion),
 						))
 					})
 				})
@@ -1880,7 +2007,7 @@ var _ = Describe("cluster_controller", func() {
 				})
 
 				It("should replace the processes", func() {
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 
 					replacements := make(map[string]bool, len(originalPods.Items))
@@ -1922,7 +2049,7 @@ var _ = Describe("cluster_controller", func() {
 				})
 
 				It("should replace the processes", func() {
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 
 					replacements := make(map[string]bool, len(originalPods.Items))
@@ -1964,7 +2091,7 @@ var _ = Describe("cluster_controller", func() {
 				})
 
 				It("should replace the processes", func() {
-					adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+					adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 					Expect(err).NotTo(HaveOccurred())
 
 					replacements := make(map[string]bool, len(originalPods.Items))
@@ -1999,7 +2126,7 @@ var _ = Describe("cluster_controller", func() {
 			})
 
 			It("should replace the processes", func() {
-				adminClient, err := newMockAdminClientUncast(cluster, k8sClient)
+				adminClient, err := NewMockAdminClientUncast(cluster, k8sClient)
 				Expect(err).NotTo(HaveOccurred())
 
 				replacements := make(map[string]bool, len(originalPods.Items))
@@ -2065,6 +2192,90 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 
+		Context("when using services for the public IPs", func() {
+			BeforeEach(func() {
+				cluster.Spec.Services.PublicIPSource = fdbtypes.PublicIPSourceService
+				err = k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should create a service for each instance", func() {
+				pods := &corev1.PodList{}
+				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(pods.Items)).To(Equal(17))
+
+				for _, pod := range pods.Items {
+					service := &corev1.Service{}
+					err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: pod.Name}, service)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(service.Spec.Selector["fdb-instance-id"]).To(Equal(GetInstanceIDFromMeta(pod.ObjectMeta)))
+					Expect(len(service.OwnerReferences)).To(Equal(1))
+				}
+			})
+
+			It("should use the service IPs as the public IPs", func() {
+				pods := &corev1.PodList{}
+				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, pod := range pods.Items {
+					service := &corev1.Service{}
+					err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: pod.Name}, service)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(service.Spec.ClusterIP).NotTo(Equal(""))
+					Expect(pod.ObjectMeta.Annotations[PublicIPAnnotation]).To(Equal(service.Spec.ClusterIP))
+				}
+			})
+		})
+
+		Context("when enabling pod disruption budgets", func() {
+			BeforeEach(func() {
+				var flag = true
+				cluster.Spec.PodDisruptionBudgets.Enabled = &flag
+				err = k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should create a single budget for the cluster", func() {
+				budgets := &policyv1beta1.PodDisruptionBudgetList{}
+				err = k8sClient.List(context.TODO(), budgets, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(budgets.Items)).To(Equal(1))
+
+				budget := budgets.Items[0]
+				Expect(budget.Name).To(Equal("operator-test-1"))
+				Expect(*budget.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+				Expect(len(budget.OwnerReferences)).To(Equal(1))
+			})
+		})
+
+		Context("when disabling pod disruption budgets", func() {
+			BeforeEach(func() {
+				var flag = true
+				cluster.Spec.PodDisruptionBudgets.Enabled = &flag
+				err = k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() (int64, error) {
+					generations, err := reloadClusterGenerations(cluster)
+					return generations.Reconciled, err
+				}, timeout).Should(Equal(originalVersion + 1))
+
+				*cluster.Spec.PodDisruptionBudgets.Enabled = false
+				generationGap = 2
+				err = k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should remove the budgets", func() {
+				budgets := &policyv1beta1.PodDisruptionBudgetList{}
+				err = k8sClient.List(context.TODO(), budgets, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(budgets.Items)).To(Equal(0))
+			})
+		})
+
 		Context("custom metrics for a cluster", func() {
 			BeforeEach(func() {
 				generationGap = 0
@@ -2381,6 +2592,35 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 
+		Context("with public IPs from services", func() {
+			BeforeEach(func() {
+				cluster.Spec.Services.PublicIPSource = fdbtypes.PublicIPSourceService
+				conf, err = GetMonitorConf(cluster, "storage", nil, nil)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should listen on the pod IP", func() {
+				Expect(conf).To(Equal(strings.Join([]string{
+					"[general]",
+					"kill_on_configuration_change = false",
+					"restart_delay = 60",
+					"[fdbserver.1]",
+					"command = $BINARY_DIR/fdbserver",
+					"cluster_file = /var/fdb/data/fdb.cluster",
+					"seed_cluster_file = /var/dynamic-conf/fdb.cluster",
+					"public_address = $FDB_PUBLIC_IP:4501",
+					"class = storage",
+					"datadir = /var/fdb/data",
+					"logdir = /var/log/fdb-trace-logs",
+					"loggroup = " + cluster.Name,
+					"locality_instance_id = $FDB_INSTANCE_ID",
+					"locality_machineid = $FDB_MACHINE_ID",
+					"locality_zoneid = $FDB_ZONE_ID",
+					"listen_address = $FDB_POD_IP:4501",
+				}, "\n")))
+			})
+		})
+
 		Context("with TLS enabled", func() {
 			BeforeEach(func() {
 				cluster.Spec.MainContainer.EnableTLS = true
@@ -3095,108 +3335,469 @@ var _ = Describe("cluster_controller", func() {
 				})
 			})
 		})
+
+		Context("with a field that has the same value for every process", func() {
+			BeforeEach(func() {
+				candidates = []localityInfo{
+					{ID: "p1", LocalityData: map[string]string{"zoneid": "z1", "data_hall": "h1"}},
+					{ID: "p2", LocalityData: map[string]string{"zoneid": "z2", "data_hall": "h1"}},
+					{ID: "p3", LocalityData: map[string]string{"zoneid": "z3", "data_hall": "h2"}},
+					{ID: "p4", LocalityData: map[string]string{"zoneid": "z4", "data_hall": "h2"}},
+				}
+				result, err = chooseDistributedProcesses(candidates, 3, processSelectionConstraint{
+					Fields: []string{"zoneid", "dcid", "data_hall"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should still spread the processes across the later fields", func() {
+				Expect(len(result)).To(Equal(3))
+				Expect(result[0].ID).To(Equal("p1"))
+				Expect(result[1].ID).To(Equal("p3"))
+				Expect(result[2].ID).To(Equal("p2"))
+			})
+		})
 	})
 
-	Describe("checkCoordinatorValidity", func() {
-		var status *fdbtypes.FoundationDBStatus
-		var adminClient AdminClient
+	Describe("getBounceBatch", func() {
+		var zoneMap map[string]string
+		var instanceIDs []string
+		var batch []string
 		var err error
 
 		BeforeEach(func() {
-			err = k8sClient.Create(context.TODO(), cluster)
-			Expect(err).NotTo(HaveOccurred())
-
-			Eventually(func() (int64, error) {
-				generations, err := reloadClusterGenerations(cluster)
-				return generations.Reconciled, err
-			}, time.Second*5).ShouldNot(Equal(int64(0)))
-
-			adminClient, err = NewMockAdminClient(cluster, k8sClient)
-			Expect(err).NotTo(HaveOccurred())
+			instanceIDs = []string{"storage-3", "storage-1", "log-1", "storage-2"}
+			zoneMap = map[string]string{
+				"storage-1": "z1",
+				"storage-2": "z2",
+				"storage-3": "z1",
+				"log-1":     "z2",
+			}
+		})
 
-			status, err = adminClient.GetStatus()
+		JustBeforeEach(func() {
+			batch, err = getBounceBatch(cluster, instanceIDs, zoneMap)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		Context("with the default configuration", func() {
-			It("should report the coordinators as valid", func() {
-				coordinatorsValid, addressesValid, err := checkCoordinatorValidity(cluster, status)
-				Expect(coordinatorsValid).To(BeTrue())
-				Expect(addressesValid).To(BeTrue())
-				Expect(err).To(BeNil())
+		Context("with the default strategy", func() {
+			It("should bounce all of the instances", func() {
+				Expect(batch).To(Equal([]string{"log-1", "storage-1", "storage-2", "storage-3"}))
 			})
 		})
 
-		AfterEach(func() {
-			cleanupCluster(cluster)
-		})
-
-		Context("with too few coordinators", func() {
+		Context("with a limit on the batch size", func() {
 			BeforeEach(func() {
-				status.Client.Coordinators.Coordinators = status.Client.Coordinators.Coordinators[0:2]
+				cluster.Spec.BounceStrategy.MaxProcessesPerBatch = 3
 			})
 
-			It("should report the coordinators as not valid", func() {
-				coordinatorsValid, addressesValid, err := checkCoordinatorValidity(cluster, status)
-				Expect(coordinatorsValid).To(BeFalse())
-				Expect(addressesValid).To(BeTrue())
-				Expect(err).To(BeNil())
+			It("should bounce the first instances", func() {
+				Expect(batch).To(Equal([]string{"log-1", "storage-1", "storage-2"}))
 			})
 		})
 
-		Context("with too few zones", func() {
+		Context("with the fault domain strategy", func() {
 			BeforeEach(func() {
-				zone := ""
-				for _, process := range status.Cluster.Processes {
-					if process.Address == status.Client.Coordinators.Coordinators[0].Address {
-						zone = process.Locality["zoneid"]
-					}
-				}
-				for _, process := range status.Cluster.Processes {
-					if process.Address == status.Client.Coordinators.Coordinators[1].Address {
-						process.Locality["zoneid"] = zone
-					}
-				}
+				cluster.Spec.BounceStrategy.Type = fdbtypes.BounceStrategyFaultDomain
 			})
 
-			It("should report the coordinators as not valid", func() {
-				coordinatorsValid, addressesValid, err := checkCoordinatorValidity(cluster, status)
-				Expect(coordinatorsValid).To(BeFalse())
-				Expect(addressesValid).To(BeTrue())
-				Expect(err).To(BeNil())
+			It("should bounce the instances in a single zone", func() {
+				Expect(batch).To(Equal([]string{"log-1", "storage-2"}))
 			})
-		})
 
-		Context("with multiple regions", func() {
-			BeforeEach(func() {
-				coordinatorCount := 9
-				cluster.Spec.ProcessCounts.Storage = coordinatorCount
+			Context("with a limit on the batch size", func() {
+				BeforeEach(func() {
+					cluster.Spec.BounceStrategy.MaxProcessesPerBatch = 1
+				})
 
-				err = k8sClient.Update(context.TODO(), cluster)
-				Expect(err).NotTo(HaveOccurred())
+				It("should bounce one instance", func() {
+					Expect(batch).To(Equal([]string{"log-1"}))
+				})
+			})
 
-				Eventually(func() (int64, error) {
-					generations, err := reloadClusterGenerations(cluster)
-					return generations.Reconciled, err
-				}, time.Second*5).Should(Equal(int64(2)))
+			Context("with a protocol-compatible upgrade", func() {
+				BeforeEach(func() {
+					cluster.Status.RunningVersion = Versions.Default.String()
+					cluster.Spec.Version = fdbtypes.FdbVersion{Major: Versions.Default.Major, Minor: Versions.Default.Minor, Patch: Versions.Default.Patch + 1}.String()
+				})
 
-				status, err = adminClient.GetStatus()
-				Expect(err).NotTo(HaveOccurred())
+				It("should bounce the instances in a single zone", func() {
+					Expect(batch).To(Equal([]string{"log-1", "storage-2"}))
+				})
+			})
 
-				cluster.Spec.UsableRegions = 2
+			Context("with an upgrade that is not protocol-compatible", func() {
+				BeforeEach(func() {
+					cluster.Status.RunningVersion = Versions.Default.String()
+					cluster.Spec.Version = Versions.NextMajorVersion.String()
+				})
 
-				coordinators := make([]fdbtypes.FoundationDBStatusCoordinator, 0, coordinatorCount)
-				dc := 0
-				for _, process := range status.Cluster.Processes {
-					if process.ProcessClass == "storage" {
-						coordinators = append(coordinators, fdbtypes.FoundationDBStatusCoordinator{
-							Address:   process.Address,
-							Reachable: true,
-						})
-						dc++
-						process.Locality["dcid"] = fmt.Sprintf("dc%d", dc)
-						dc = dc % 3
-					}
+				It("should bounce all of the instances", func() {
+					Expect(batch).To(Equal(instanceIDs))
+				})
+			})
+		})
+	})
+
+	Describe("getCanaryBatch", func() {
+		var instanceIDs []string
+		var instances map[string]FdbInstance
+		var batch []string
+		var err error
+
+		BeforeEach(func() {
+			instanceIDs = []string{"storage-2", "stateless-2", "storage-1", "stateless-1"}
+			instances = map[string]FdbInstance{
+				"storage-1":   {Metadata: &metav1.ObjectMeta{Labels: map[string]string{"fdb-process-class": "storage", "fdb-instance-id": "storage-1"}}},
+				"storage-2":   {Metadata: &metav1.ObjectMeta{Labels: map[string]string{"fdb-process-class": "storage", "fdb-instance-id": "storage-2", "canary": "true"}}},
+				"stateless-1": {Metadata: &metav1.ObjectMeta{Labels: map[string]string{"fdb-process-class": "stateless", "fdb-instance-id": "stateless-1"}}},
+				"stateless-2": {Metadata: &metav1.ObjectMeta{Labels: map[string]string{"fdb-process-class": "stateless", "fdb-instance-id": "stateless-2"}}},
+			}
+		})
+
+		JustBeforeEach(func() {
+			batch, err = getCanaryBatch(cluster, instanceIDs, instances)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("with a process class", func() {
+			BeforeEach(func() {
+				cluster.Spec.CanaryUpgrade.ProcessClasses = []string{"stateless"}
+			})
+
+			It("should choose one process from that class", func() {
+				Expect(batch).To(Equal([]string{"stateless-1"}))
+			})
+
+			Context("with a higher limit on the number of processes", func() {
+				BeforeEach(func() {
+					maxProcesses := 3
+					cluster.Spec.CanaryUpgrade.MaxProcesses = &maxProcesses
+				})
+
+				It("should choose all of the processes from that class", func() {
+					Expect(batch).To(Equal([]string{"stateless-1", "stateless-2"}))
+				})
+			})
+		})
+
+		Context("with a pod selector", func() {
+			BeforeEach(func() {
+				cluster.Spec.CanaryUpgrade.PodSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}}
+			})
+
+			It("should choose the matching process", func() {
+				Expect(batch).To(Equal([]string{"storage-2"}))
+			})
+
+			Context("with a process class that does not match the selected pods", func() {
+				BeforeEach(func() {
+					cluster.Spec.CanaryUpgrade.ProcessClasses = []string{"stateless"}
+				})
+
+				It("should not choose any processes", func() {
+					Expect(batch).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("with a matching process that does not need to be bounced", func() {
+			BeforeEach(func() {
+				cluster.Spec.CanaryUpgrade.ProcessClasses = []string{"stateless"}
+				instanceIDs = []string{"storage-1", "stateless-2"}
+			})
+
+			It("should choose from the processes that need to be bounced", func() {
+				Expect(batch).To(Equal([]string{"stateless-2"}))
+			})
+		})
+	})
+
+	Describe("chooseCoordinators", func() {
+		var status *fdbtypes.FoundationDBStatus
+		var excluded map[string]bool
+		var result []localityInfo
+		var err error
+
+		BeforeEach(func() {
+			status = &fdbtypes.FoundationDBStatus{
+				Cluster: fdbtypes.FoundationDBStatusClusterInfo{
+					Processes: make(map[string]fdbtypes.FoundationDBStatusProcessInfo),
+				},
+			}
+			addProcess := func(id string, processClass string, index int, dataHall string) {
+				status.Cluster.Processes[id] = fdbtypes.FoundationDBStatusProcessInfo{
+					Address:      fmt.Sprintf("1.1.0.%d:4501", index),
+					ProcessClass: processClass,
+					Locality: map[string]string{
+						"instance_id": id,
+						"zoneid":      fmt.Sprintf("z%d", index),
+						"data_hall":   dataHall,
+					},
+				}
+			}
+			for index := 1; index <= 4; index++ {
+				addProcess(fmt.Sprintf("storage-%d", index), "storage", index, "h1")
+			}
+			addProcess("log-1", "log", 5, "h1")
+			addProcess("log-2", "log", 6, "h1")
+			addProcess("log-3", "log", 7, "h2")
+			addProcess("stateless-1", "stateless", 8, "h2")
+			excluded = nil
+		})
+
+		JustBeforeEach(func() {
+			result, err = chooseCoordinators(cluster, status, excluded)
+		})
+
+		getIDs := func() []string {
+			ids := make([]string, len(result))
+			for index, process := range result {
+				ids[index] = process.ID
+			}
+			return ids
+		}
+
+		Context("with the default configuration", func() {
+			It("should choose stateful processes", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getIDs()).To(Equal([]string{"log-1", "log-2", "log-3"}))
+			})
+		})
+
+		Context("with process class priorities", func() {
+			BeforeEach(func() {
+				cluster.Spec.CoordinatorSelection.ProcessClasses = []fdbtypes.CoordinatorSelectionSetting{
+					{ProcessClass: "log", Priority: 5},
+					{ProcessClass: "storage", Priority: 10},
+				}
+			})
+
+			It("should choose the processes with the highest priority", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getIDs()).To(Equal([]string{"storage-1", "storage-2", "storage-3"}))
+			})
+
+			Context("with a process excluded by label", func() {
+				BeforeEach(func() {
+					excluded = map[string]bool{"storage-2": true}
+				})
+
+				It("should skip the excluded process", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(getIDs()).To(Equal([]string{"storage-1", "storage-3", "storage-4"}))
+				})
+			})
+		})
+
+		Context("with an ineligible process class", func() {
+			BeforeEach(func() {
+				cluster.Spec.CoordinatorSelection.ProcessClasses = []fdbtypes.CoordinatorSelectionSetting{
+					{ProcessClass: "stateless"},
+				}
+			})
+
+			It("should give an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Could only select 1 processes, but 3 are required"))
+			})
+		})
+
+		Context("with an extra locality field", func() {
+			BeforeEach(func() {
+				cluster.Spec.CoordinatorSelection.LocalityFields = []string{"data_hall"}
+			})
+
+			It("should spread the coordinators across the field", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getIDs()).To(Equal([]string{"log-1", "log-3", "log-2"}))
+			})
+		})
+
+		Context("with existing coordinators", func() {
+			BeforeEach(func() {
+				status.Client.Coordinators.Coordinators = []fdbtypes.FoundationDBStatusCoordinator{
+					{Address: "1.1.0.4:4501", Reachable: true},
+					{Address: "1.1.0.3:4501", Reachable: false},
+				}
+			})
+
+			Context("with healthy coordinators preserved", func() {
+				BeforeEach(func() {
+					preserve := true
+					cluster.Spec.CoordinatorSelection.PreserveHealthyCoordinators = &preserve
+				})
+
+				It("should keep the reachable coordinators", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(getIDs()).To(Equal([]string{"storage-4", "log-1", "log-2"}))
+				})
+			})
+
+			Context("without healthy coordinators preserved", func() {
+				It("should choose the coordinators from scratch", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(getIDs()).To(Equal([]string{"log-1", "log-2", "log-3"}))
+				})
+			})
+		})
+	})
+
+	Describe("getCoordinatorSelectionConstraint", func() {
+		Context("with the default configuration", func() {
+			It("should spread the coordinators across zones and data centers", func() {
+				Expect(getCoordinatorSelectionConstraint(cluster).Fields).To(Equal([]string{"zoneid", "dcid"}))
+			})
+		})
+
+		Context("with an extra locality field", func() {
+			BeforeEach(func() {
+				cluster.Spec.CoordinatorSelection.LocalityFields = []string{"data_hall"}
+			})
+
+			It("should add the field to the defaults", func() {
+				Expect(getCoordinatorSelectionConstraint(cluster).Fields).To(Equal([]string{"zoneid", "dcid", "data_hall"}))
+			})
+		})
+
+		Context("with locality fields that repeat the defaults", func() {
+			BeforeEach(func() {
+				cluster.Spec.CoordinatorSelection.LocalityFields = []string{"dcid", "data_hall", "zoneid", "data_hall"}
+			})
+
+			It("should only include each field once", func() {
+				Expect(getCoordinatorSelectionConstraint(cluster).Fields).To(Equal([]string{"zoneid", "dcid", "data_hall"}))
+			})
+		})
+	})
+
+	Describe("checkCoordinatorValidity", func() {
+		var status *fdbtypes.FoundationDBStatus
+		var adminClient AdminClient
+		var err error
+
+		BeforeEach(func() {
+			err = k8sClient.Create(context.TODO(), cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() (int64, error) {
+				generations, err := reloadClusterGenerations(cluster)
+				return generations.Reconciled, err
+			}, time.Second*5).ShouldNot(Equal(int64(0)))
+
+			adminClient, err = NewMockAdminClient(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, err = adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("with the default configuration", func() {
+			It("should report the coordinators as valid", func() {
+				coordinatorsValid, addressesValid, err := checkCoordinatorValidity(cluster, status)
+				Expect(coordinatorsValid).To(BeTrue())
+				Expect(addressesValid).To(BeTrue())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		AfterEach(func() {
+			cleanupCluster(cluster)
+		})
+
+		Context("with IPv6 addresses", func() {
+			BeforeEach(func() {
+				convertAddress := func(address string, format string) string {
+					parsed, err := fdbtypes.ParseProcessAddress(address)
+					Expect(err).NotTo(HaveOccurred())
+					components := strings.Split(parsed.IPAddress, ".")
+					parsed.IPAddress = fmt.Sprintf(format, components[0], components[1], components[2], components[3])
+					return parsed.String()
+				}
+
+				for index, coordinator := range status.Client.Coordinators.Coordinators {
+					status.Client.Coordinators.Coordinators[index].Address = convertAddress(coordinator.Address, "fd00:0:0:0:%s:%s:%s:%s")
+				}
+				for index, process := range status.Cluster.Processes {
+					process.Address = convertAddress(process.Address, "fd00::%s:%s:%s:%s")
+					status.Cluster.Processes[index] = process
+				}
+			})
+
+			It("should report the coordinators as valid", func() {
+				coordinatorsValid, addressesValid, err := checkCoordinatorValidity(cluster, status)
+				Expect(coordinatorsValid).To(BeTrue())
+				Expect(addressesValid).To(BeTrue())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("with too few coordinators", func() {
+			BeforeEach(func() {
+				status.Client.Coordinators.Coordinators = status.Client.Coordinators.Coordinators[0:2]
+			})
+
+			It("should report the coordinators as not valid", func() {
+				coordinatorsValid, addressesValid, err := checkCoordinatorValidity(cluster, status)
+				Expect(coordinatorsValid).To(BeFalse())
+				Expect(addressesValid).To(BeTrue())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("with too few zones", func() {
+			BeforeEach(func() {
+				zone := ""
+				for _, process := range status.Cluster.Processes {
+					if process.Address == status.Client.Coordinators.Coordinators[0].Address {
+						zone = process.Locality["zoneid"]
+					}
+				}
+				for _, process := range status.Cluster.Processes {
+					if process.Address == status.Client.Coordinators.Coordinators[1].Address {
+						process.Locality["zoneid"] = zone
+					}
+				}
+			})
+
+			It("should report the coordinators as not valid", func() {
+				coordinatorsValid, addressesValid, err := checkCoordinatorValidity(cluster, status)
+				Expect(coordinatorsValid).To(BeFalse())
+				Expect(addressesValid).To(BeTrue())
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("with multiple regions", func() {
+			BeforeEach(func() {
+				coordinatorCount := 9
+				cluster.Spec.ProcessCounts.Storage = coordinatorCount
+
+				err = k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() (int64, error) {
+					generations, err := reloadClusterGenerations(cluster)
+					return generations.Reconciled, err
+				}, time.Second*5).Should(Equal(int64(2)))
+
+				status, err = adminClient.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+
+				cluster.Spec.UsableRegions = 2
+
+				coordinators := make([]fdbtypes.FoundationDBStatusCoordinator, 0, coordinatorCount)
+				dc := 0
+				for _, process := range status.Cluster.Processes {
+					if process.ProcessClass == "storage" {
+						coordinators = append(coordinators, fdbtypes.FoundationDBStatusCoordinator{
+							Address:   process.Address,
+							Reachable: true,
+						})
+						dc++
+						process.Locality["dcid"] = fmt.Sprintf("dc%d", dc)
+						dc = dc % 3
+					}
 				}
 				status.Client.Coordinators.Coordinators = coordinators
 			})
@@ -3227,6 +3828,1297 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 	})
+
+	Describe("getReplacementCheckDelay", func() {
+		var now int64
+
+		BeforeEach(func() {
+			enabled := true
+			cluster.Spec.AutomationOptions.Replacements.Enabled = &enabled
+			now = time.Now().Unix()
+			cluster.Status.ProcessGroups = []fdbtypes.ProcessGroupStatus{
+				{InstanceID: "storage-1", ProcessClass: "storage"},
+				{InstanceID: "storage-2", ProcessClass: "storage", Conditions: []fdbtypes.ProcessGroupCondition{
+					{Type: fdbtypes.MissingProcess, Timestamp: now - 600},
+				}},
+			}
+		})
+
+		It("should wait until the missing process reaches the detection time", func() {
+			Expect(getReplacementCheckDelay(cluster, now)).To(Equal(1200 * time.Second))
+		})
+
+		It("should not wait when replacements are disabled", func() {
+			cluster.Spec.AutomationOptions.Replacements.Enabled = nil
+			Expect(getReplacementCheckDelay(cluster, now)).To(Equal(time.Duration(0)))
+		})
+
+		It("should not wait when no processes are missing", func() {
+			cluster.Status.ProcessGroups[1].Conditions = nil
+			Expect(getReplacementCheckDelay(cluster, now)).To(Equal(time.Duration(0)))
+		})
+
+		It("should wait at least a minute for a process past the detection time", func() {
+			cluster.Status.ProcessGroups[1].Conditions[0].Timestamp = now - 3600
+			Expect(getReplacementCheckDelay(cluster, now)).To(Equal(time.Minute))
+		})
+
+		It("should not wait for a process that is already being removed", func() {
+			cluster.Status.ProcessGroups[1].Conditions = append(cluster.Status.ProcessGroups[1].Conditions, fdbtypes.ProcessGroupCondition{Type: fdbtypes.MarkedForRemoval, Timestamp: now})
+			Expect(getReplacementCheckDelay(cluster, now)).To(Equal(time.Duration(0)))
+		})
+	})
+
+	Describe("getAutoscaledStorageCount", func() {
+		BeforeEach(func() {
+			enabled := true
+			cluster.Spec.Autoscaling = fdbtypes.AutoscalingConfig{
+				Enabled:             &enabled,
+				MinStorageProcesses: 3,
+				MaxStorageProcesses: 10,
+			}
+		})
+
+		It("should scale up to bring the utilization back to the target", func() {
+			Expect(getAutoscaledStorageCount(cluster, 5, 90)).To(Equal(8))
+		})
+
+		It("should scale down to bring the utilization back to the target", func() {
+			Expect(getAutoscaledStorageCount(cluster, 8, 30)).To(Equal(4))
+		})
+
+		It("should not scale within the tolerance", func() {
+			Expect(getAutoscaledStorageCount(cluster, 5, 70)).To(Equal(5))
+			Expect(getAutoscaledStorageCount(cluster, 5, 50)).To(Equal(5))
+		})
+
+		It("should respect the maximum count", func() {
+			Expect(getAutoscaledStorageCount(cluster, 8, 95)).To(Equal(10))
+		})
+
+		It("should respect the minimum count", func() {
+			Expect(getAutoscaledStorageCount(cluster, 4, 5)).To(Equal(3))
+		})
+	})
+
+	Describe("getAutoscalingCheckDelay", func() {
+		var now int64
+
+		BeforeEach(func() {
+			enabled := true
+			cluster.Spec.Autoscaling.Enabled = &enabled
+			now = time.Now().Unix()
+		})
+
+		It("should check again after the check interval", func() {
+			Expect(getAutoscalingCheckDelay(cluster, now)).To(Equal(autoscalingCheckInterval))
+		})
+
+		It("should wait for the cooldown to expire", func() {
+			cluster.Status.Autoscaling.LastScaleTimestamp = now - 600
+			Expect(getAutoscalingCheckDelay(cluster, now)).To(Equal(1200 * time.Second))
+		})
+
+		It("should not wait when autoscaling is disabled", func() {
+			cluster.Spec.Autoscaling.Enabled = nil
+			Expect(getAutoscalingCheckDelay(cluster, now)).To(Equal(time.Duration(0)))
+		})
+	})
+
+	Describe("checkRetryableError", func() {
+		It("should requeue with a delay for a timeout", func() {
+			result, err := clusterReconciler.checkRetryableError(AdminClientError{Reason: AdminClientTimeout, Command: "status json"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(time.Duration(DefaultCLITimeout) * time.Second))
+		})
+
+		It("should requeue with a delay for an unreachable cluster", func() {
+			result, err := clusterReconciler.checkRetryableError(AdminClientError{Reason: AdminClientClusterUnreachable, Command: "status minimal"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(time.Duration(DefaultCLITimeout) * time.Second))
+		})
+
+		It("should return the error for a TLS failure", func() {
+			_, err := clusterReconciler.checkRetryableError(AdminClientError{Reason: AdminClientTLSHandshakeFailure, Command: "status json"})
+			Expect(err).To(HaveOccurred())
+			Expect(GetAdminClientErrorReason(err)).To(Equal(AdminClientTLSHandshakeFailure))
+		})
+
+		It("should return the error for unparseable output", func() {
+			_, err := clusterReconciler.checkRetryableError(AdminClientError{Reason: AdminClientUnparseableOutput, Command: "status json"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should requeue with a delay for a wrapped timeout", func() {
+			result, err := clusterReconciler.checkRetryableError(fmt.Errorf("could not get status: %w", AdminClientError{Reason: AdminClientTimeout, Command: "status json"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(time.Duration(DefaultCLITimeout) * time.Second))
+		})
+	})
+
+	Describe("sub-reconcilers", func() {
+		var reconciler *FoundationDBClusterReconciler
+		var adminClient *MockAdminClient
+		var err error
+		var timeoutError error
+
+		BeforeEach(func() {
+			err = k8sClient.Create(context.TODO(), cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() (int64, error) {
+				generations, err := reloadClusterGenerations(cluster)
+				return generations.Reconciled, err
+			}, time.Second*5).ShouldNot(Equal(int64(0)))
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}, cluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The reconciler normalizes the spec before running the
+			// sub-reconcilers, so we do the same here.
+			fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
+
+			// We use a separate admin client from the one the manager uses, so
+			// that changes the manager makes in response to these tests do not
+			// affect the results.
+			sharedClient, err := NewMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+			adminClient = &MockAdminClient{
+				Cluster:               cluster,
+				KubeClient:            k8sClient,
+				DatabaseConfiguration: sharedClient.DatabaseConfiguration.DeepCopy(),
+				ReincludedAddresses:   make(map[string]bool),
+			}
+
+			reconciler = &FoundationDBClusterReconciler{
+				Client:              k8sClient,
+				Recorder:            clusterReconciler.Recorder,
+				InSimulation:        true,
+				PodLifecycleManager: StandardPodLifecycleManager{},
+				PodClientProvider:   NewMockFdbPodClient,
+				PodIPProvider:       MockPodIP,
+				AdminClientProvider: func(*fdbtypes.FoundationDBCluster, client.Client) (AdminClient, error) {
+					return adminClient, nil
+				},
+				LockClientProvider: NewMockLockClient,
+			}
+
+			timeoutError = AdminClientError{Reason: AdminClientTimeout, Command: "test"}
+		})
+
+		AfterEach(func() {
+			adminClient.ClearFaults()
+			ClearMockPodClientFaults()
+			Expect(reconciler.endAdminClientSession(cluster)).NotTo(HaveOccurred())
+			cleanupCluster(cluster)
+		})
+
+		Describe("BounceProcesses", func() {
+			var result bool
+
+			BeforeEach(func() {
+				cluster.Status.ProcessGroups = []fdbtypes.ProcessGroupStatus{
+					{
+						InstanceID:   "storage-1",
+						ProcessClass: "storage",
+						Conditions: []fdbtypes.ProcessGroupCondition{
+							{Type: fdbtypes.IncorrectCommandLine, Timestamp: 1},
+						},
+					},
+				}
+			})
+
+			JustBeforeEach(func() {
+				result, err = BounceProcesses{}.Reconcile(reconciler, context.TODO(), cluster)
+			})
+
+			Context("with a failure fetching the status", func() {
+				BeforeEach(func() {
+					adminClient.InjectFault("GetStatus", MockFault{Error: timeoutError, Count: 1})
+				})
+
+				It("should return the error", func() {
+					Expect(result).To(BeFalse())
+					Expect(GetAdminClientErrorReason(err)).To(Equal(AdminClientTimeout))
+				})
+
+				It("should not kill any processes", func() {
+					Expect(adminClient.KilledAddresses).To(BeNil())
+				})
+			})
+
+			Context("with processes in a single zone", func() {
+				BeforeEach(func() {
+					// BounceProcesses takes the zones from the process
+					// localities, so we need the real zone lookup when we
+					// check whether the processes have rejoined.
+					reconciler.InSimulation = false
+				})
+
+				It("should put the zone into maintenance mode", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(adminClient.KilledAddresses).To(Equal([]string{"1.1.0.1:4501"}))
+					Expect(adminClient.MaintenanceZone).To(Equal("operator-test-1-storage-1"))
+					Expect(cluster.Status.MaintenanceZone).To(Equal("operator-test-1-storage-1"))
+				})
+
+				Context("with the processes back up", func() {
+					It("should clear the maintenance zone in the next update", func() {
+						result, err = UpdatePods{}.Reconcile(reconciler, context.TODO(), cluster)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeTrue())
+						Expect(adminClient.MaintenanceZone).To(Equal(""))
+						Expect(cluster.Status.MaintenanceZone).To(Equal(""))
+					})
+				})
+
+				Context("with a process that has not come back", func() {
+					It("should wait for the process before clearing the maintenance zone", func() {
+						adminClient.MockMissingProcessGroup("storage-1", true)
+						reconciler.getAdminClientSession(cluster).InvalidateStatus()
+						result, err = UpdatePods{}.Reconcile(reconciler, context.TODO(), cluster)
+						Expect(result).To(BeFalse())
+						Expect(err).To(Equal(ReconciliationNotReadyError{message: "Waiting for processes to rejoin before leaving maintenance mode", retryable: true}))
+						Expect(adminClient.MaintenanceZone).To(Equal("operator-test-1-storage-1"))
+						Expect(cluster.Status.MaintenanceZone).To(Equal("operator-test-1-storage-1"))
+					})
+				})
+
+				Context("with a process outside the maintenance zone that has not come back", func() {
+					It("should clear the maintenance zone", func() {
+						adminClient.MockMissingProcessGroup("storage-2", true)
+						reconciler.getAdminClientSession(cluster).InvalidateStatus()
+						result, err = UpdatePods{}.Reconcile(reconciler, context.TODO(), cluster)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeTrue())
+						Expect(adminClient.MaintenanceZone).To(Equal(""))
+						Expect(cluster.Status.MaintenanceZone).To(Equal(""))
+					})
+				})
+
+				Context("with a maintenance zone that has expired", func() {
+					It("should clear the maintenance zone from the status without waiting for the processes", func() {
+						adminClient.MockMissingProcessGroup("storage-1", true)
+						err = adminClient.ResetMaintenanceMode()
+						Expect(err).NotTo(HaveOccurred())
+						reconciler.getAdminClientSession(cluster).InvalidateStatus()
+						result, err = UpdatePods{}.Reconcile(reconciler, context.TODO(), cluster)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeTrue())
+						Expect(cluster.Status.MaintenanceZone).To(Equal(""))
+					})
+				})
+			})
+
+			Context("with a failure setting the maintenance zone", func() {
+				BeforeEach(func() {
+					adminClient.InjectFault("SetMaintenanceZone", MockFault{Error: timeoutError, Count: 1})
+				})
+
+				It("should return the error without killing any processes", func() {
+					Expect(result).To(BeFalse())
+					Expect(GetAdminClientErrorReason(err)).To(Equal(AdminClientTimeout))
+					Expect(adminClient.KilledAddresses).To(BeNil())
+					Expect(cluster.Status.MaintenanceZone).To(Equal(""))
+				})
+			})
+
+			Context("with a kill command that hangs", func() {
+				BeforeEach(func() {
+					adminClient.InjectFault("KillInstances", MockFault{Error: timeoutError, Delay: 10 * time.Millisecond, Count: 1})
+				})
+
+				It("should return the error", func() {
+					Expect(result).To(BeFalse())
+					Expect(GetAdminClientErrorReason(err)).To(Equal(AdminClientTimeout))
+				})
+
+				It("should invalidate the cached status", func() {
+					Expect(reconciler.getAdminClientSession(cluster).status).To(BeNil())
+				})
+			})
+
+			Context("with a sidecar failure copying files", func() {
+				BeforeEach(func() {
+					MockOutdatedPodFiles("operator-test-1-storage-1", true)
+					InjectMockPodClientFault("operator-test-1-storage-1", "CopyFiles", MockFault{Error: NewMockSidecarError(500, "internal error")})
+				})
+
+				It("should return the error", func() {
+					Expect(result).To(BeFalse())
+					Expect(err).To(Equal(NewMockSidecarError(500, "internal error")))
+				})
+
+				It("should not kill any processes", func() {
+					Expect(adminClient.KilledAddresses).To(BeNil())
+				})
+			})
+
+			Context("with a sidecar that recovers", func() {
+				BeforeEach(func() {
+					MockOutdatedPodFiles("operator-test-1-storage-1", true)
+					InjectMockPodClientFault("operator-test-1-storage-1", "CopyFiles", MockFault{Error: NewMockSidecarError(500, "internal error"), Count: 1})
+				})
+
+				It("should kill the process on the next attempt", func() {
+					Expect(err).To(HaveOccurred())
+					result, err = BounceProcesses{}.Reconcile(reconciler, context.TODO(), cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(adminClient.KilledAddresses).To(Equal([]string{"1.1.0.1:4501"}))
+				})
+			})
+
+			Context("with processes in multiple zones", func() {
+				BeforeEach(func() {
+					cluster.Status.ProcessGroups = append(cluster.Status.ProcessGroups, fdbtypes.ProcessGroupStatus{
+						InstanceID:   "storage-2",
+						ProcessClass: "storage",
+						Conditions: []fdbtypes.ProcessGroupCondition{
+							{Type: fdbtypes.IncorrectCommandLine, Timestamp: 1},
+						},
+					})
+				})
+
+				Context("with the default strategy", func() {
+					It("should bounce all of the processes at once", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeTrue())
+						Expect(adminClient.KilledAddresses).To(Equal([]string{"1.1.0.1:4501", "1.1.0.2:4501"}))
+						Expect(cluster.Status.MaintenanceZone).To(Equal(""))
+						Expect(cluster.Status.Bounce).To(Equal(fdbtypes.BounceStatus{}))
+					})
+				})
+
+				Context("with the fault domain strategy", func() {
+					BeforeEach(func() {
+						cluster.Spec.BounceStrategy.Type = fdbtypes.BounceStrategyFaultDomain
+					})
+
+					It("should bounce the first zone", func() {
+						Expect(result).To(BeFalse())
+						Expect(err).To(Equal(ReconciliationNotReadyError{message: "Waiting for bounced processes to rejoin", retryable: true}))
+						Expect(adminClient.KilledAddresses).To(Equal([]string{"1.1.0.1:4501"}))
+						Expect(cluster.Status.MaintenanceZone).To(Equal("operator-test-1-storage-1"))
+					})
+
+					It("should record the progress in the status", func() {
+						Expect(cluster.Status.Bounce).To(Equal(fdbtypes.BounceStatus{
+							Strategy:         fdbtypes.BounceStrategyFaultDomain,
+							CurrentBatch:     []string{"storage-1"},
+							BouncedInstances: []string{"storage-1"},
+						}))
+					})
+
+					Context("with the processes back up", func() {
+						It("should bounce the next zone", func() {
+							cluster.Status.ProcessGroups[0].Conditions = nil
+							result, err = BounceProcesses{}.Reconcile(reconciler, context.TODO(), cluster)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result).To(BeTrue())
+							Expect(adminClient.KilledAddresses).To(Equal([]string{"1.1.0.1:4501", "1.1.0.2:4501"}))
+							Expect(cluster.Status.MaintenanceZone).To(Equal("operator-test-1-storage-2"))
+							Expect(cluster.Status.Bounce).To(Equal(fdbtypes.BounceStatus{}))
+						})
+					})
+
+					Context("with a process that has not come back", func() {
+						It("should wait for the process before bouncing the next zone", func() {
+							adminClient.MockMissingProcessGroup("storage-1", true)
+							reconciler.getAdminClientSession(cluster).InvalidateStatus()
+							result, err = BounceProcesses{}.Reconcile(reconciler, context.TODO(), cluster)
+							Expect(result).To(BeFalse())
+							Expect(err).To(Equal(ReconciliationNotReadyError{message: "Waiting for bounced processes to rejoin", retryable: true}))
+							Expect(adminClient.KilledAddresses).To(Equal([]string{"1.1.0.1:4501"}))
+						})
+					})
+
+					Context("with a cluster that is not healthy", func() {
+						It("should wait for the cluster to be healthy before bouncing the next zone", func() {
+							adminClient.RecoveryPolls = 1
+							adminClient.TriggerRecovery()
+							reconciler.getAdminClientSession(cluster).InvalidateStatus()
+							result, err = BounceProcesses{}.Reconcile(reconciler, context.TODO(), cluster)
+							Expect(result).To(BeFalse())
+							Expect(err).To(Equal(ReconciliationNotReadyError{message: "Waiting for the cluster to be healthy before bouncing more processes", retryable: true}))
+							Expect(adminClient.KilledAddresses).To(Equal([]string{"1.1.0.1:4501"}))
+						})
+					})
+				})
+
+				Context("with canary upgrades", func() {
+					BeforeEach(func() {
+						cluster.Status.RunningVersion = Versions.Default.String()
+						cluster.Spec.Version = Versions.NextPatchVersion.String()
+						cluster.Spec.CanaryUpgrade.ProcessClasses = []string{"storage"}
+					})
+
+					It("should bounce the canary", func() {
+						Expect(result).To(BeFalse())
+						Expect(err).To(Equal(ReconciliationNotReadyError{message: "Waiting for bounced processes to rejoin", retryable: true}))
+						Expect(adminClient.KilledAddresses).To(Equal([]string{"1.1.0.1:4501"}))
+						Expect(cluster.Status.RunningVersion).To(Equal(Versions.Default.String()))
+					})
+
+					It("should record the canary in the status", func() {
+						Expect(cluster.Status.CanaryUpgrade.Version).To(Equal(Versions.NextPatchVersion.String()))
+						Expect(cluster.Status.CanaryUpgrade.Instances).To(Equal([]string{"storage-1"}))
+						Expect(cluster.Status.CanaryUpgrade.State).To(Equal(fdbtypes.CanaryUpgradeStarting))
+						Expect(cluster.Status.Bounce.CurrentBatch).To(Equal([]string{"storage-1"}))
+					})
+
+					Context("with canaries that have been promoted", func() {
+						BeforeEach(func() {
+							cluster.Status.CanaryUpgrade = fdbtypes.CanaryUpgradeStatus{
+								Version:   Versions.NextPatchVersion.String(),
+								Instances: []string{"storage-1"},
+								State:     fdbtypes.CanaryUpgradePromoted,
+							}
+							cluster.Status.ProcessGroups[0].Conditions = nil
+						})
+
+						It("should bounce the remaining processes", func() {
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result).To(BeTrue())
+							Expect(adminClient.KilledAddresses).To(Equal([]string{"1.1.0.2:4501"}))
+							Expect(cluster.Status.CanaryUpgrade).To(Equal(fdbtypes.CanaryUpgradeStatus{}))
+							Expect(cluster.Status.RunningVersion).To(Equal(Versions.NextPatchVersion.String()))
+						})
+					})
+
+					Context("with no processes that match the canary settings", func() {
+						BeforeEach(func() {
+							cluster.Spec.CanaryUpgrade.ProcessClasses = []string{"stateless"}
+						})
+
+						It("should halt the upgrade", func() {
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result).To(BeFalse())
+							Expect(adminClient.KilledAddresses).To(BeNil())
+							Expect(cluster.Status.CanaryUpgrade.State).To(Equal(fdbtypes.CanaryUpgradeHalted))
+						})
+					})
+
+					Context("with an upgrade that is not protocol-compatible", func() {
+						BeforeEach(func() {
+							cluster.Spec.Version = Versions.NextMinorVersion.String()
+						})
+
+						It("should bounce all of the processes at once", func() {
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result).To(BeTrue())
+							Expect(adminClient.KilledAddresses).To(Equal([]string{"1.1.0.1:4501", "1.1.0.2:4501"}))
+							Expect(cluster.Status.CanaryUpgrade).To(Equal(fdbtypes.CanaryUpgradeStatus{}))
+						})
+					})
+				})
+			})
+		})
+
+		Describe("CheckUpgradeReadiness", func() {
+			var result bool
+
+			BeforeEach(func() {
+				cluster.Status.RunningVersion = Versions.Default.String()
+				cluster.Spec.Version = Versions.NextMajorVersion.String()
+			})
+
+			JustBeforeEach(func() {
+				result, err = CheckUpgradeReadiness{}.Reconcile(reconciler, context.TODO(), cluster)
+			})
+
+			Context("with the files present in every pod", func() {
+				It("should allow the reconciliation to continue", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(cluster.Status.UpgradeLaggingPods).To(BeNil())
+				})
+			})
+
+			Context("with a pod that is missing the binaries", func() {
+				BeforeEach(func() {
+					MockMissingPodBinaries("operator-test-1-storage-1", true)
+				})
+
+				It("should block the reconciliation", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeFalse())
+				})
+
+				It("should list the pod in the status", func() {
+					Expect(cluster.Status.UpgradeLaggingPods).To(Equal([]string{"operator-test-1-storage-1"}))
+				})
+
+				Context("once the binaries have been copied", func() {
+					It("should clear the pod from the status", func() {
+						MockMissingPodBinaries("operator-test-1-storage-1", false)
+						result, err = CheckUpgradeReadiness{}.Reconcile(reconciler, context.TODO(), cluster)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeTrue())
+						Expect(cluster.Status.UpgradeLaggingPods).To(BeNil())
+					})
+				})
+			})
+
+			Context("with a pod whose binaries do not match the other pods", func() {
+				BeforeEach(func() {
+					MockMismatchedPodBinaries("operator-test-1-storage-1", true)
+				})
+
+				It("should block the reconciliation", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeFalse())
+				})
+
+				It("should list the pod in the status", func() {
+					Expect(cluster.Status.UpgradeLaggingPods).To(Equal([]string{"operator-test-1-storage-1"}))
+				})
+			})
+
+			Context("with a sidecar failure getting the hashes", func() {
+				BeforeEach(func() {
+					InjectMockPodClientFault("operator-test-1-log-1", "GetHash", MockFault{Error: NewMockSidecarError(500, "internal error")})
+				})
+
+				It("should list the pod in the status", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeFalse())
+					Expect(cluster.Status.UpgradeLaggingPods).To(Equal([]string{"operator-test-1-log-1"}))
+				})
+			})
+
+			Context("with a pod that has an outdated monitor conf", func() {
+				BeforeEach(func() {
+					MockOutdatedPodFiles("operator-test-1-storage-2", true)
+				})
+
+				It("should allow the reconciliation to continue", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(cluster.Status.UpgradeLaggingPods).To(BeNil())
+				})
+
+				It("should leave the monitor conf for the bounce", func() {
+					Expect(mockOutdatedPodFiles["operator-test-1-storage-2"]).To(BeTrue())
+				})
+			})
+
+			Context("with a sidecar failure checking the files", func() {
+				BeforeEach(func() {
+					MockMissingPodBinaries("operator-test-1-storage-1", true)
+					InjectMockPodClientFault("operator-test-1-log-1", "IsPresent", MockFault{Error: NewMockSidecarError(500, "internal error")})
+				})
+
+				It("should list all of the lagging pods in the status", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeFalse())
+					Expect(cluster.Status.UpgradeLaggingPods).To(Equal([]string{"operator-test-1-log-1", "operator-test-1-storage-1"}))
+				})
+			})
+
+			Context("with no upgrade in progress", func() {
+				BeforeEach(func() {
+					cluster.Spec.Version = cluster.Status.RunningVersion
+					MockMissingPodBinaries("operator-test-1-storage-1", true)
+				})
+
+				It("should not check the files", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(cluster.Status.UpgradeLaggingPods).To(BeNil())
+				})
+			})
+		})
+
+		Describe("CheckCanaryUpgrade", func() {
+			var result bool
+
+			BeforeEach(func() {
+				cluster.Status.RunningVersion = Versions.Default.String()
+				cluster.Spec.Version = Versions.NextPatchVersion.String()
+				cluster.Spec.CanaryUpgrade.ProcessClasses = []string{"storage"}
+				cluster.Status.CanaryUpgrade = fdbtypes.CanaryUpgradeStatus{
+					Version:        Versions.NextPatchVersion.String(),
+					Instances:      []string{"storage-1"},
+					State:          fdbtypes.CanaryUpgradeStarting,
+					StartTimestamp: time.Now().Unix(),
+				}
+			})
+
+			JustBeforeEach(func() {
+				result, err = CheckCanaryUpgrade{}.Reconcile(reconciler, context.TODO(), cluster)
+			})
+
+			Context("with canaries that have rejoined", func() {
+				It("should start the soak period", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeFalse())
+					Expect(cluster.Status.CanaryUpgrade.State).To(Equal(fdbtypes.CanaryUpgradeSoaking))
+				})
+
+				Context("with no soak period", func() {
+					BeforeEach(func() {
+						soakPeriod := 0
+						cluster.Spec.CanaryUpgrade.SoakPeriodSeconds = &soakPeriod
+					})
+
+					It("should promote the upgrade", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeTrue())
+						Expect(cluster.Status.CanaryUpgrade.State).To(Equal(fdbtypes.CanaryUpgradePromoted))
+					})
+				})
+			})
+
+			Context("with a canary that has not rejoined", func() {
+				BeforeEach(func() {
+					adminClient.MockMissingProcessGroup("storage-1", true)
+				})
+
+				It("should wait for the canary", func() {
+					Expect(result).To(BeFalse())
+					Expect(err).To(Equal(ReconciliationNotReadyError{message: "Waiting for canary processes to rejoin", retryable: true}))
+					Expect(cluster.Status.CanaryUpgrade.State).To(Equal(fdbtypes.CanaryUpgradeStarting))
+				})
+
+				Context("after the rejoin timeout", func() {
+					BeforeEach(func() {
+						cluster.Status.CanaryUpgrade.StartTimestamp = time.Now().Unix() - CanaryRejoinTimeoutSeconds - 1
+					})
+
+					It("should halt the upgrade", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeFalse())
+						Expect(cluster.Status.CanaryUpgrade.State).To(Equal(fdbtypes.CanaryUpgradeHalted))
+						Expect(cluster.Status.CanaryUpgrade.Message).To(Equal("canary process storage-1 is not reporting to the cluster"))
+					})
+				})
+			})
+
+			Context("with canaries that are soaking", func() {
+				BeforeEach(func() {
+					cluster.Status.CanaryUpgrade.State = fdbtypes.CanaryUpgradeSoaking
+					cluster.Status.CanaryUpgrade.StartTimestamp = time.Now().Unix() - 60
+				})
+
+				It("should wait for the soak period", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeFalse())
+					Expect(cluster.Status.CanaryUpgrade.State).To(Equal(fdbtypes.CanaryUpgradeSoaking))
+				})
+
+				Context("with a cluster that is not healthy", func() {
+					BeforeEach(func() {
+						adminClient.RecoveryPolls = 1
+						adminClient.TriggerRecovery()
+					})
+
+					It("should halt the upgrade", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeFalse())
+						Expect(cluster.Status.CanaryUpgrade.State).To(Equal(fdbtypes.CanaryUpgradeHalted))
+						Expect(cluster.Status.CanaryUpgrade.Message).To(Equal("the database is not available"))
+					})
+				})
+
+				Context("with a canary that has stopped reporting", func() {
+					BeforeEach(func() {
+						adminClient.MockMissingProcessGroup("storage-1", true)
+					})
+
+					It("should halt the upgrade", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeFalse())
+						Expect(cluster.Status.CanaryUpgrade.State).To(Equal(fdbtypes.CanaryUpgradeHalted))
+						Expect(cluster.Status.CanaryUpgrade.Message).To(Equal("canary process storage-1 is not reporting to the cluster"))
+					})
+				})
+
+				Context("after the soak period", func() {
+					BeforeEach(func() {
+						cluster.Status.CanaryUpgrade.StartTimestamp = time.Now().Unix() - 601
+					})
+
+					It("should promote the upgrade", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeTrue())
+						Expect(cluster.Status.CanaryUpgrade.State).To(Equal(fdbtypes.CanaryUpgradePromoted))
+					})
+				})
+			})
+
+			Context("with a halted upgrade", func() {
+				BeforeEach(func() {
+					cluster.Status.CanaryUpgrade.State = fdbtypes.CanaryUpgradeHalted
+				})
+
+				It("should block the reconciliation", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeFalse())
+					Expect(cluster.Status.CanaryUpgrade.State).To(Equal(fdbtypes.CanaryUpgradeHalted))
+				})
+
+				Context("with the canary settings removed", func() {
+					BeforeEach(func() {
+						cluster.Spec.CanaryUpgrade = fdbtypes.CanaryUpgradeConfig{}
+					})
+
+					It("should allow the reconciliation to continue", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeTrue())
+					})
+				})
+			})
+
+			Context("with canaries for a different version", func() {
+				BeforeEach(func() {
+					cluster.Status.CanaryUpgrade.Version = Versions.Default.String()
+					cluster.Status.CanaryUpgrade.State = fdbtypes.CanaryUpgradeHalted
+				})
+
+				It("should allow the reconciliation to continue", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+				})
+			})
+		})
+
+		Describe("UpdatePods", func() {
+			var result bool
+
+			BeforeEach(func() {
+				cluster.Spec.Processes = map[string]fdbtypes.ProcessSettings{"general": {PodTemplate: &corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name: "foundationdb",
+								Env: []corev1.EnvVar{
+									{
+										Name:  "TEST_CHANGE",
+										Value: "1",
+									},
+								},
+							},
+						},
+					},
+				}}}
+				fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
+			})
+
+			JustBeforeEach(func() {
+				result, err = UpdatePods{}.Reconcile(reconciler, context.TODO(), cluster)
+			})
+
+			Context("with a sidecar failure getting substitutions", func() {
+				BeforeEach(func() {
+					pods := &corev1.PodList{}
+					err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
+					Expect(err).NotTo(HaveOccurred())
+					for _, pod := range pods.Items {
+						InjectMockPodClientFault(pod.Name, "GetVariableSubstitutions", MockFault{Error: NewMockSidecarError(500, "internal error")})
+					}
+				})
+
+				It("should return the error", func() {
+					Expect(result).To(BeFalse())
+					Expect(err).To(Equal(NewMockSidecarError(500, "internal error")))
+				})
+			})
+
+			Context("with a change to the pod spec", func() {
+				It("should put the zone into maintenance mode", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(adminClient.MaintenanceZone).To(Equal("simulation"))
+					Expect(adminClient.MaintenanceZoneHistory).To(Equal([]string{"simulation"}))
+					Expect(cluster.Status.MaintenanceZone).To(Equal("simulation"))
+				})
+			})
+
+			Context("with pods that have been recreated", func() {
+				BeforeEach(func() {
+					cluster.Spec.Processes = nil
+					fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
+					err = reconciler.setMaintenanceZone(context.TODO(), cluster, "simulation")
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				Context("with a process that has not rejoined", func() {
+					BeforeEach(func() {
+						adminClient.MockMissingProcessGroup("storage-1", true)
+					})
+
+					It("should keep the zone in maintenance mode", func() {
+						Expect(result).To(BeFalse())
+						Expect(err).To(Equal(ReconciliationNotReadyError{message: "Waiting for processes to rejoin before leaving maintenance mode", retryable: true}))
+						Expect(adminClient.MaintenanceZone).To(Equal("simulation"))
+						Expect(cluster.Status.MaintenanceZone).To(Equal("simulation"))
+					})
+
+					Context("once the process rejoins", func() {
+						It("should clear the maintenance zone", func() {
+							adminClient.MockMissingProcessGroup("storage-1", false)
+							reconciler.getAdminClientSession(cluster).InvalidateStatus()
+							result, err = UpdatePods{}.Reconcile(reconciler, context.TODO(), cluster)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(result).To(BeTrue())
+							Expect(adminClient.MaintenanceZone).To(Equal(""))
+							Expect(cluster.Status.MaintenanceZone).To(Equal(""))
+						})
+					})
+				})
+
+				Context("with all of the processes reporting", func() {
+					It("should clear the maintenance zone", func() {
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(BeTrue())
+						Expect(adminClient.MaintenanceZone).To(Equal(""))
+						Expect(cluster.Status.MaintenanceZone).To(Equal(""))
+					})
+				})
+			})
+
+			Context("with a failure fetching the status", func() {
+				BeforeEach(func() {
+					adminClient.InjectFault("GetStatus", MockFault{Error: timeoutError})
+				})
+
+				It("should return the error", func() {
+					Expect(result).To(BeFalse())
+					Expect(GetAdminClientErrorReason(err)).To(Equal(AdminClientTimeout))
+				})
+
+				It("should not delete any pods", func() {
+					pods := &corev1.PodList{}
+					err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
+					Expect(err).NotTo(HaveOccurred())
+					for _, pod := range pods.Items {
+						Expect(pod.ObjectMeta.DeletionTimestamp).To(BeNil())
+					}
+				})
+			})
+		})
+
+		Describe("ReplaceFailedPods", func() {
+			var result bool
+
+			BeforeEach(func() {
+				enabled := true
+				cluster.Spec.AutomationOptions.Replacements.Enabled = &enabled
+				processGroup := cluster.Status.GetProcessGroup("storage-1")
+				Expect(processGroup).NotTo(BeNil())
+				processGroup.Conditions = []fdbtypes.ProcessGroupCondition{
+					{Type: fdbtypes.MissingProcess, Timestamp: time.Now().Unix() - 3600},
+				}
+			})
+
+			JustBeforeEach(func() {
+				result, err = ReplaceFailedPods{}.Reconcile(reconciler, context.TODO(), cluster)
+			})
+
+			Context("with a process that has been missing past the detection time", func() {
+				It("should mark the process for removal", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(cluster.Status.PendingRemovals).To(HaveKey("storage-1"))
+					Expect(cluster.Status.PendingRemovals["storage-1"].PodName).To(Equal("operator-test-1-storage-1"))
+					Expect(cluster.Status.GetProcessGroup("storage-1").HasCondition(fdbtypes.MarkedForRemoval)).To(BeTrue())
+				})
+
+				It("should reduce the process count so that a replacement is added", func() {
+					Expect(cluster.Status.ProcessCounts.Storage).To(Equal(3))
+				})
+			})
+
+			Context("with a process that has not been missing long enough", func() {
+				BeforeEach(func() {
+					cluster.Status.GetProcessGroup("storage-1").Conditions[0].Timestamp = time.Now().Unix() - 60
+				})
+
+				It("should not mark the process for removal", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(cluster.Status.PendingRemovals).To(BeNil())
+				})
+			})
+
+			Context("with replacements disabled", func() {
+				BeforeEach(func() {
+					enabled := false
+					cluster.Spec.AutomationOptions.Replacements.Enabled = &enabled
+				})
+
+				It("should not mark the process for removal", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(cluster.Status.PendingRemovals).To(BeNil())
+				})
+			})
+
+			Context("with a database that has no fault tolerance", func() {
+				BeforeEach(func() {
+					faultTolerance := 0
+					adminClient.FaultTolerance = &faultTolerance
+				})
+
+				It("should not mark the process for removal", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(cluster.Status.PendingRemovals).To(BeNil())
+				})
+			})
+
+			Context("with the maximum number of processes pending removal", func() {
+				BeforeEach(func() {
+					cluster.Status.PendingRemovals = map[string]fdbtypes.PendingRemovalState{
+						"storage-2": {PodName: "operator-test-1-storage-2", Address: "1.1.0.2"},
+					}
+				})
+
+				It("should not mark the process for removal", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(cluster.Status.PendingRemovals).NotTo(HaveKey("storage-1"))
+				})
+			})
+
+			Context("with a higher limit on concurrent replacements", func() {
+				BeforeEach(func() {
+					maxReplacements := 2
+					cluster.Spec.AutomationOptions.Replacements.MaxConcurrentReplacements = &maxReplacements
+					cluster.Status.PendingRemovals = map[string]fdbtypes.PendingRemovalState{
+						"storage-2": {PodName: "operator-test-1-storage-2", Address: "1.1.0.2"},
+					}
+				})
+
+				It("should mark the process for removal", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(cluster.Status.PendingRemovals).To(HaveKey("storage-1"))
+					Expect(cluster.Status.PendingRemovals).To(HaveKey("storage-2"))
+				})
+			})
+		})
+
+		Describe("AutoscaleStorage", func() {
+			var result bool
+
+			BeforeEach(func() {
+				enabled := true
+				cluster.Spec.Autoscaling = fdbtypes.AutoscalingConfig{
+					Enabled:             &enabled,
+					MinStorageProcesses: 3,
+					MaxStorageProcesses: 8,
+				}
+				adminClient.DiskUtilizationPercent = 90
+			})
+
+			JustBeforeEach(func() {
+				result, err = AutoscaleStorage{}.Reconcile(reconciler, context.TODO(), cluster)
+			})
+
+			Context("with high disk utilization", func() {
+				It("should increase the storage process count", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeFalse())
+					_, err = reloadClusterGenerations(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(6))
+				})
+
+				It("should record the time of the change", func() {
+					_, err = reloadClusterGenerations(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Status.Autoscaling.LastScaleTimestamp).To(BeNumerically("~", time.Now().Unix(), 5))
+				})
+			})
+
+			Context("with low disk utilization", func() {
+				BeforeEach(func() {
+					adminClient.DiskUtilizationPercent = 20
+				})
+
+				It("should decrease the storage process count to the minimum", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeFalse())
+					_, err = reloadClusterGenerations(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(3))
+				})
+			})
+
+			Context("with disk utilization within the tolerance", func() {
+				BeforeEach(func() {
+					adminClient.DiskUtilizationPercent = 65
+				})
+
+				It("should not change the storage process count", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					_, err = reloadClusterGenerations(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+				})
+			})
+
+			Context("with a recent change to the process count", func() {
+				BeforeEach(func() {
+					cluster.Status.Autoscaling.LastScaleTimestamp = time.Now().Unix() - 60
+				})
+
+				It("should not change the storage process count", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					_, err = reloadClusterGenerations(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+				})
+			})
+
+			Context("with processes pending removal", func() {
+				BeforeEach(func() {
+					cluster.Status.PendingRemovals = map[string]fdbtypes.PendingRemovalState{
+						"storage-1": {PodName: "operator-test-1-storage-1", Address: "1.1.0.1"},
+					}
+				})
+
+				It("should not change the storage process count", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					_, err = reloadClusterGenerations(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+				})
+			})
+
+			Context("with a maximum below the minimum", func() {
+				BeforeEach(func() {
+					cluster.Spec.Autoscaling.MaxStorageProcesses = 2
+				})
+
+				It("should not change the storage process count", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					_, err = reloadClusterGenerations(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+				})
+			})
+
+			Context("with only the enabled flag set", func() {
+				BeforeEach(func() {
+					cluster.Spec.Autoscaling.MinStorageProcesses = 0
+					cluster.Spec.Autoscaling.MaxStorageProcesses = 0
+				})
+
+				It("should not change the storage process count", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					_, err = reloadClusterGenerations(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+				})
+			})
+
+			Context("with a target of zero", func() {
+				BeforeEach(func() {
+					target := 0
+					cluster.Spec.Autoscaling.TargetDiskUtilizationPercent = &target
+				})
+
+				It("should not change the storage process count", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					_, err = reloadClusterGenerations(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+				})
+			})
+
+			Context("with autoscaling disabled", func() {
+				BeforeEach(func() {
+					cluster.Spec.Autoscaling.Enabled = nil
+				})
+
+				It("should not change the storage process count", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					_, err = reloadClusterGenerations(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+				})
+			})
+		})
+
+		Describe("UpdatePodDisruptionBudgets", func() {
+			var result bool
+
+			BeforeEach(func() {
+				enabled := true
+				cluster.Spec.PodDisruptionBudgets.Enabled = &enabled
+			})
+
+			JustBeforeEach(func() {
+				result, err = UpdatePodDisruptionBudgets{}.Reconcile(reconciler, context.TODO(), cluster)
+			})
+
+			It("should create a single budget for the cluster", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeTrue())
+
+				budgets := &policyv1beta1.PodDisruptionBudgetList{}
+				err = k8sClient.List(context.TODO(), budgets, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(budgets.Items)).To(Equal(1))
+				Expect(budgets.Items[0].Name).To(Equal("operator-test-1"))
+			})
+
+			Context("with a budget for a process class", func() {
+				BeforeEach(func() {
+					maxUnavailable := intstr.FromInt(1)
+					budget := &policyv1beta1.PodDisruptionBudget{
+						ObjectMeta: getObjectMetadata(cluster, nil, "storage", ""),
+					}
+					budget.ObjectMeta.Name = "operator-test-1-storage"
+					budget.ObjectMeta.OwnerReferences = buildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)
+					budget.Spec.MaxUnavailable = &maxUnavailable
+					budget.Spec.Selector = &metav1.LabelSelector{MatchLabels: getMinimalPodLabels(cluster, "storage", "")}
+					err = k8sClient.Create(context.TODO(), budget)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should replace it with the budget for the cluster", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+
+					budgets := &policyv1beta1.PodDisruptionBudgetList{}
+					err = k8sClient.List(context.TODO(), budgets, getListOptions(cluster)...)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(len(budgets.Items)).To(Equal(1))
+					Expect(budgets.Items[0].Name).To(Equal("operator-test-1"))
+				})
+			})
+		})
+
+		Describe("ExcludeInstances", func() {
+			var result bool
+
+			BeforeEach(func() {
+				cluster.Status.PendingRemovals = map[string]fdbtypes.PendingRemovalState{
+					"storage-9": {PodName: "operator-test-1-storage-9", Address: "1.1.0.9"},
+				}
+			})
+
+			JustBeforeEach(func() {
+				result, err = ExcludeInstances{}.Reconcile(reconciler, context.TODO(), cluster)
+			})
+
+			Context("with a failure running the exclude", func() {
+				BeforeEach(func() {
+					adminClient.InjectFault("ExcludeInstances", MockFault{Error: timeoutError, Count: 1})
+				})
+
+				It("should return the error", func() {
+					Expect(result).To(BeFalse())
+					Expect(GetAdminClientErrorReason(err)).To(Equal(AdminClientTimeout))
+				})
+
+				It("should not exclude the process", func() {
+					Expect(adminClient.ExcludedAddresses).To(BeNil())
+				})
+
+				It("should record that the exclusion was started", func() {
+					Expect(cluster.Status.PendingRemovals["storage-9"].ExclusionStarted).To(BeTrue())
+				})
+			})
+
+			Context("with a rejected exclude", func() {
+				BeforeEach(func() {
+					adminClient.InjectFault("ExcludeInstances", MockFault{Error: AdminClientError{Reason: AdminClientCommandRejected, Command: "exclude"}, Count: 1})
+				})
+
+				It("should return the error", func() {
+					Expect(result).To(BeFalse())
+					Expect(GetAdminClientErrorReason(err)).To(Equal(AdminClientCommandRejected))
+				})
+			})
+
+			Context("with a version that supports locality-based exclusions", func() {
+				BeforeEach(func() {
+					cluster.Spec.Version = Versions.NextMinorVersion.String()
+					cluster.Status.PendingRemovals = map[string]fdbtypes.PendingRemovalState{
+						"storage-9": {PodName: "operator-test-1-storage-9"},
+					}
+				})
+
+				It("should exclude the instance by its instance ID", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(adminClient.ExcludedAddresses).To(Equal([]string{"locality_instance_id:storage-9"}))
+					Expect(cluster.Status.PendingRemovals["storage-9"].ExclusionStarted).To(BeTrue())
+				})
+			})
+		})
+
+		Describe("ConfirmExclusionCompletion", func() {
+			var result bool
+
+			JustBeforeEach(func() {
+				result, err = ConfirmExclusionCompletion{}.Reconcile(reconciler, context.TODO(), cluster)
+			})
+
+			Context("with an exclusion by address", func() {
+				BeforeEach(func() {
+					cluster.Status.PendingRemovals = map[string]fdbtypes.PendingRemovalState{
+						"storage-9": {PodName: "operator-test-1-storage-9", Address: "1.1.0.9", ExclusionStarted: true},
+					}
+					adminClient.ExclusionDrainPolls = 1
+					err = adminClient.ExcludeInstances([]string{"1.1.0.9:4501"})
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should mark the exclusion as complete", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(cluster.Status.PendingRemovals["storage-9"].ExclusionComplete).To(BeTrue())
+				})
+			})
+
+			Context("with an exclusion by locality", func() {
+				BeforeEach(func() {
+					cluster.Spec.Version = Versions.NextMinorVersion.String()
+					cluster.Status.PendingRemovals = map[string]fdbtypes.PendingRemovalState{
+						"storage-9": {PodName: "operator-test-1-storage-9", ExclusionStarted: true},
+					}
+					adminClient.ExclusionDrainPolls = 1
+					err = adminClient.ExcludeInstances([]string{"locality_instance_id:storage-9"})
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should mark the exclusion as complete without needing an address", func() {
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result).To(BeTrue())
+					Expect(cluster.Status.PendingRemovals["storage-9"].ExclusionComplete).To(BeTrue())
+				})
+			})
+
+			Context("with a missing address on a version without locality-based exclusions", func() {
+				BeforeEach(func() {
+					cluster.Status.PendingRemovals = map[string]fdbtypes.PendingRemovalState{
+						"storage-9": {PodName: "operator-test-1-storage-9", ExclusionStarted: true},
+					}
+				})
+
+				It("should return an error", func() {
+					Expect(result).To(BeFalse())
+					Expect(err).To(MatchError("Cannot check the exclusion state of instance storage-9, which has no IP address"))
+				})
+			})
+		})
+	})
 })
 
 func getProcessClassMap(pods []corev1.Pod) map[string]int {
@@ -34,21 +34,27 @@ type ConfirmExclusionCompletion struct{}
 
 // Reconcile runs the reconciler's work.
 func (c ConfirmExclusionCompletion) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
-	adminClient, err := r.AdminClientProvider(cluster, r)
+	adminClient, err := r.getAdminClientSession(cluster).GetAdminClient()
+	if err != nil {
+		return false, err
+	}
+
+	version, err := fdbtypes.ParseFdbVersion(cluster.Spec.Version)
 	if err != nil {
 		return false, err
 	}
-	defer adminClient.Close()
 
 	addresses := make([]string, 0, len(cluster.Status.PendingRemovals))
+	instanceIDs := make(map[string]string, len(cluster.Status.PendingRemovals))
 
 	for instanceID, state := range cluster.Status.PendingRemovals {
 		if !state.ExclusionComplete {
-			if state.Address == "" {
+			address := getExclusionTarget(cluster, version, instanceID, state)
+			if address == "" {
 				return false, fmt.Errorf("Cannot check the exclusion state of instance %s, which has no IP address", instanceID)
 			}
-			address := cluster.GetFullAddress(state.Address)
 			addresses = append(addresses, address)
+			instanceIDs[removeAddressFlags([]string{address})[0]] = instanceID
 		}
 	}
 
@@ -60,11 +66,12 @@ func (c ConfirmExclusionCompletion) Reconcile(r *FoundationDBClusterReconciler,
 		}
 		if len(remaining) != len(addresses) {
 			remainingMap := make(map[string]bool, len(remaining))
-			for _, address := range remaining {
-				remainingMap[address] = true
+			for _, address := range removeAddressFlags(remaining) {
+				remainingMap[instanceIDs[address]] = true
 			}
-			for id, state := range cluster.Status.PendingRemovals {
-				if !remainingMap[state.Address] {
+			for _, id := range instanceIDs {
+				state := cluster.Status.PendingRemovals[id]
+				if !remainingMap[id] && !state.ExclusionComplete {
 					newState := state
 					newState.ExclusionComplete = true
 					cluster.Status.PendingRemovals[id] = newState
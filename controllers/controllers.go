@@ -35,6 +35,10 @@ const LastSpecKey = "foundationdb.org/last-applied-spec"
 // config map.
 const LastConfigMapKey = "foundationdb.org/last-applied-config-map"
 
+// PublicIPAnnotation provides the annotation name we use to store the public
+// IP for a pod when the public IP comes from a service.
+const PublicIPAnnotation = "foundationdb.org/public-ip"
+
 // BackupDeploymentLabel provides the label we use to connect backup
 // deployments to a cluster.
 const BackupDeploymentLabel = "foundationdb.org/backup-for"
@@ -47,6 +51,16 @@ var DefaultCLITimeout = 10
 // execute a bounce.
 const MinimumUptimeSecondsForBounce = 600
 
+// MaintenanceZoneDurationSeconds defines the time, in seconds, that the
+// operator asks the database to keep a zone in maintenance mode while it
+// updates the processes in that zone.
+const MaintenanceZoneDurationSeconds = 600
+
+// CanaryRejoinTimeoutSeconds defines the time, in seconds, that the operator
+// waits for canary processes to rejoin the cluster and for the cluster to be
+// healthy after it bounces them, before it halts the upgrade.
+const CanaryRejoinTimeoutSeconds = 600
+
 // metadataMatches determines if the current metadata on an object matches the
 // metadata specified by the cluster spec.
 func metadataMatches(currentMetadata metav1.ObjectMeta, desiredMetadata metav1.ObjectMeta) bool {
@@ -74,15 +88,3 @@ func mergeAnnotations(target *metav1.ObjectMeta, desired metav1.ObjectMeta) bool
 	}
 	return changed
 }
-
-// defaultsSelection controls how defaults that are changing get applied to our
-// specs.
-type defaultsSelection struct {
-	// Whether we should apply the latest defaults rather than the defaults that
-	// were initially established for this major version.
-	UseFutureDefaults bool
-
-	// Whether we should only fill in defaults that have changes between major
-	// versions of the operator.
-	OnlyShowChanges bool
-}
@@ -34,11 +34,11 @@ type ExcludeInstances struct{}
 
 // Reconcile runs the reconciler's work.
 func (e ExcludeInstances) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
-	adminClient, err := r.AdminClientProvider(cluster, r)
+	session := r.getAdminClientSession(cluster)
+	adminClient, err := session.GetAdminClient()
 	if err != nil {
 		return false, err
 	}
-	defer adminClient.Close()
 
 	version, err := fdbtypes.ParseFdbVersion(cluster.Spec.Version)
 	if err != nil {
@@ -48,21 +48,20 @@ func (e ExcludeInstances) Reconcile(r *FoundationDBClusterReconciler, context ct
 	addresses := make([]string, 0, len(cluster.Status.PendingRemovals))
 	hasExclusionUpdates := false
 	for id, state := range cluster.Status.PendingRemovals {
-		if state.Address != "" {
-			address := cluster.GetFullAddress(state.Address)
-			if !state.ExclusionStarted {
-				addresses = append(addresses, address)
-				newState := state
-				newState.ExclusionStarted = true
-				cluster.Status.PendingRemovals[id] = newState
-				hasExclusionUpdates = true
-			}
+		address := getExclusionTarget(cluster, version, id, state)
+		if address != "" && !state.ExclusionStarted {
+			addresses = append(addresses, address)
+			newState := state
+			newState.ExclusionStarted = true
+			cluster.Status.PendingRemovals[id] = newState
+			hasExclusionUpdates = true
 		}
 	}
 
 	if len(addresses) > 0 {
 		r.Recorder.Event(cluster, "Normal", "ExcludingProcesses", fmt.Sprintf("Excluding %v", addresses))
 		err = adminClient.ExcludeInstances(addresses)
+		session.InvalidateStatus()
 
 		if hasExclusionUpdates && !version.HasNonBlockingExcludes() {
 			updateErr := r.updatePendingRemovals(context, cluster)
@@ -91,3 +90,21 @@ func (e ExcludeInstances) Reconcile(r *FoundationDBClusterReconciler, context ct
 func (e ExcludeInstances) RequeueAfter() time.Duration {
 	return 0
 }
+
+// getExclusionTarget gets the argument we pass to the exclude command to
+// exclude an instance that is pending removal.
+//
+// On versions that support locality-based exclusions, this will exclude the
+// instance by its instance ID, so that the exclusion still applies if the pod
+// is recreated with a new IP. On older versions, this will use the address
+// recorded in the pending removal state, and will return an empty string if
+// there is no address.
+func getExclusionTarget(cluster *fdbtypes.FoundationDBCluster, version fdbtypes.FdbVersion, instanceID string, state fdbtypes.PendingRemovalState) string {
+	if version.SupportsLocalityBasedExclusions() {
+		return localityInstanceIDExclusionPrefix + instanceID
+	}
+	if state.Address == "" {
+		return ""
+	}
+	return cluster.GetFullAddress(state.Address)
+}
@@ -23,6 +23,7 @@ package controllers
 import (
 	ctx "context"
 	"errors"
+	"sort"
 	"time"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
@@ -40,7 +41,12 @@ func (g GenerateInitialClusterFile) Reconcile(r *FoundationDBClusterReconciler,
 
 	log.Info("Generating initial cluster file", "namespace", cluster.Namespace, "cluster", cluster.Name)
 	r.Recorder.Event(cluster, "Normal", "ChangingCoordinators", "Choosing initial coordinators")
-	instances, err := r.PodLifecycleManager.GetInstances(r, cluster, context, getPodListOptions(cluster, "storage", "")...)
+	candidateClass := "storage"
+	if len(cluster.Spec.CoordinatorSelection.ProcessClasses) > 0 {
+		candidateClass = ""
+	}
+
+	instances, err := r.PodLifecycleManager.GetInstances(r, cluster, context, getPodListOptions(cluster, candidateClass, "")...)
 	if err != nil {
 		return false, err
 	}
@@ -49,6 +55,26 @@ func (g GenerateInitialClusterFile) Reconcile(r *FoundationDBClusterReconciler,
 		return false, err
 	}
 
+	excludedSelector, err := getCoordinatorExclusionSelector(cluster)
+	if err != nil {
+		return false, err
+	}
+
+	candidates := make([]FdbInstance, 0, len(instances))
+	priorities := make(map[string]int, len(instances))
+	for _, instance := range instances {
+		priority, eligible := getCoordinatorPriority(cluster, instance.GetProcessClass())
+		if eligible && !isExcludedFromCoordinators(excludedSelector, instance) {
+			candidates = append(candidates, instance)
+			priorities[instance.GetInstanceID()] = priority
+		}
+	}
+	instances = candidates
+
+	sort.SliceStable(instances, func(i, j int) bool {
+		return priorities[instances[i].GetInstanceID()] > priorities[instances[j].GetInstanceID()]
+	})
+
 	count := cluster.DesiredCoordinatorCount()
 	if len(instances) < count {
 		return false, errors.New("Cannot find enough pods to recruit coordinators")
@@ -74,7 +100,7 @@ func (g GenerateInitialClusterFile) Reconcile(r *FoundationDBClusterReconciler,
 		processLocality[indexOfProcess] = locality
 	}
 
-	coordinators, err := chooseDistributedProcesses(processLocality, count, processSelectionConstraint{})
+	coordinators, err := chooseDistributedProcesses(processLocality, count, getCoordinatorSelectionConstraint(cluster))
 	if err != nil {
 		return false, err
 	}
@@ -34,17 +34,25 @@ type IncludeInstances struct{}
 
 // Reconcile runs the reconciler's work.
 func (i IncludeInstances) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
-	adminClient, err := r.AdminClientProvider(cluster, r)
+	session := r.getAdminClientSession(cluster)
+	adminClient, err := session.GetAdminClient()
+	if err != nil {
+		return false, err
+	}
+
+	version, err := fdbtypes.ParseFdbVersion(cluster.Spec.Version)
 	if err != nil {
 		return false, err
 	}
-	defer adminClient.Close()
 
 	addresses := make([]string, 0, len(cluster.Status.PendingRemovals))
-	for _, state := range cluster.Status.PendingRemovals {
+	for instanceID, state := range cluster.Status.PendingRemovals {
 		if state.Address != "" {
 			addresses = append(addresses, cluster.GetFullAddress(state.Address))
 		}
+		if version.SupportsLocalityBasedExclusions() {
+			addresses = append(addresses, getExclusionTarget(cluster, version, instanceID, state))
+		}
 	}
 
 	if len(addresses) > 0 {
@@ -52,6 +60,7 @@ func (i IncludeInstances) Reconcile(r *FoundationDBClusterReconciler, context ct
 	}
 
 	err = adminClient.IncludeInstances(addresses)
+	session.InvalidateStatus()
 	if err != nil {
 		return false, err
 	}
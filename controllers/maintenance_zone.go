@@ -0,0 +1,165 @@
+/*
+ * maintenance_zone.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	ctx "context"
+	"fmt"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+)
+
+// setMaintenanceZone puts a zone into maintenance mode before the operator
+// takes down the processes in that zone, and records the zone in the cluster
+// status.
+func (r *FoundationDBClusterReconciler) setMaintenanceZone(context ctx.Context, cluster *fdbtypes.FoundationDBCluster, zone string) error {
+	if cluster.Status.MaintenanceZone == zone {
+		return nil
+	}
+
+	session := r.getAdminClientSession(cluster)
+	adminClient, err := session.GetAdminClient()
+	if err != nil {
+		return err
+	}
+
+	log.Info("Setting maintenance zone", "namespace", cluster.Namespace, "cluster", cluster.Name, "zone", zone)
+	r.Recorder.Event(cluster, "Normal", "SettingMaintenanceZone", fmt.Sprintf("Putting zone %s into maintenance mode", zone))
+	err = adminClient.SetMaintenanceZone(zone, MaintenanceZoneDurationSeconds)
+	session.InvalidateStatus()
+	if err != nil {
+		return err
+	}
+
+	return r.updateMaintenanceZoneStatus(context, cluster, zone)
+}
+
+// clearMaintenanceZone takes the database out of maintenance mode once all of
+// the processes in the maintenance zone are reporting to the database again.
+//
+// If the database no longer reports a maintenance zone, because the
+// maintenance mode has expired or was reset outside of the operator, this will
+// clear the zone from the cluster status without waiting for the processes.
+//
+// This will return false if we are still waiting for processes to rejoin.
+func (r *FoundationDBClusterReconciler) clearMaintenanceZone(context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
+	if cluster.Status.MaintenanceZone == "" {
+		return true, nil
+	}
+
+	session := r.getAdminClientSession(cluster)
+	status, err := session.GetStatus()
+	if err != nil {
+		return false, err
+	}
+
+	if status.Cluster.MaintenanceZone == "" {
+		log.Info("Database is no longer in maintenance mode", "namespace", cluster.Namespace, "cluster", cluster.Name, "zone", cluster.Status.MaintenanceZone)
+		err = r.updateMaintenanceZoneStatus(context, cluster, "")
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	reportingInstances := make(map[string]bool, len(status.Cluster.Processes))
+	for _, process := range status.Cluster.Processes {
+		reportingInstances[process.Locality["instance_id"]] = true
+	}
+
+	instances, err := r.PodLifecycleManager.GetInstances(r, cluster, context, getPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return false, err
+	}
+
+	for _, instance := range instances {
+		instanceID := instance.GetInstanceID()
+		if instance.Pod == nil || cluster.InstanceIsBeingRemoved(instanceID) {
+			continue
+		}
+		if instance.Pod.DeletionTimestamp == nil && reportingInstances[instanceID] {
+			continue
+		}
+
+		zone, err := r.getInstanceZone(cluster, instance)
+		if err != nil {
+			log.Info("Unable to determine zone for instance", "namespace", cluster.Namespace, "cluster", cluster.Name, "instanceID", instanceID, "error", err.Error())
+		} else if zone != cluster.Status.MaintenanceZone {
+			continue
+		}
+
+		log.Info("Waiting for processes to rejoin before leaving maintenance mode", "namespace", cluster.Namespace, "cluster", cluster.Name, "zone", cluster.Status.MaintenanceZone, "instanceID", instanceID)
+		return false, nil
+	}
+
+	adminClient, err := session.GetAdminClient()
+	if err != nil {
+		return false, err
+	}
+
+	log.Info("Clearing maintenance zone", "namespace", cluster.Namespace, "cluster", cluster.Name, "zone", cluster.Status.MaintenanceZone)
+	r.Recorder.Event(cluster, "Normal", "ClearingMaintenanceZone", fmt.Sprintf("Taking zone %s out of maintenance mode", cluster.Status.MaintenanceZone))
+	err = adminClient.ResetMaintenanceMode()
+	session.InvalidateStatus()
+	if err != nil {
+		return false, err
+	}
+
+	err = r.updateMaintenanceZoneStatus(context, cluster, "")
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// updateMaintenanceZoneStatus records the maintenance zone in the cluster
+// status.
+//
+// The update replaces the cluster with the object from the API server, so we
+// restore the spec afterward to keep the defaults that the reconciler filled
+// in.
+func (r *FoundationDBClusterReconciler) updateMaintenanceZoneStatus(context ctx.Context, cluster *fdbtypes.FoundationDBCluster, zone string) error {
+	spec := cluster.Spec.DeepCopy()
+	cluster.Status.MaintenanceZone = zone
+	err := r.Status().Update(context, cluster)
+	cluster.Spec = *spec
+	return err
+}
+
+// getInstanceZone determines the zone ID that an instance uses in its
+// locality.
+//
+// In simulation, this puts every instance in the same zone, so that we can
+// update all of the pods at once.
+func (r *FoundationDBClusterReconciler) getInstanceZone(cluster *fdbtypes.FoundationDBCluster, instance FdbInstance) (string, error) {
+	podClient, err := r.getPodClient(cluster, instance)
+	if err != nil {
+		return "", err
+	}
+	substitutions, err := podClient.GetVariableSubstitutions()
+	if err != nil {
+		return "", err
+	}
+	if r.InSimulation {
+		return "simulation", nil
+	}
+	return substitutions["FDB_ZONE_ID"], nil
+}
@@ -23,6 +23,14 @@ var (
 		append(descClusterDefaultLabels, "status_type"),
 		nil,
 	)
+
+	statusCacheRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fdb_operator_status_cache_requests_total",
+			Help: "Requests for the database status during reconciliation, partitioned by whether they were served from the cache.",
+		},
+		append(descClusterDefaultLabels, "result"),
+	)
 )
 
 type fdbClusterCollector struct {
@@ -71,6 +79,7 @@ func collectMetrics(ch chan<- prometheus.Metric, cluster *v1beta1.FoundationDBCl
 func InitCustomMetrics(reconciler *FoundationDBClusterReconciler) {
 	metrics.Registry.MustRegister(
 		newFDBClusterCollector(reconciler),
+		statusCacheRequests,
 	)
 }
 
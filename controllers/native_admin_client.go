@@ -0,0 +1,375 @@
+/*
+ * native_admin_client.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"sync"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// statusJSONKey is the special key that the client library uses to expose
+// the machine-readable status.
+const statusJSONKey = "\xff\xff/status/json"
+
+// coordinatorsKey is the system key that holds the connection string for the
+// current coordinators.
+const coordinatorsKey = "\xff/coordinators"
+
+// excludedServersPrefix is the prefix for the system keys that hold the
+// excluded addresses.
+const excludedServersPrefix = "\xff/conf/excluded/"
+
+// excludedServersVersionKey is the system key that must be changed whenever
+// the exclusion list changes, so that the cluster picks up the new list.
+const excludedServersVersionKey = "\xff/conf/excluded"
+
+// excludedLocalityPrefix is the prefix for the system keys that hold the
+// excluded localities.
+const excludedLocalityPrefix = "\xff/conf/excluded_locality/"
+
+// excludedLocalityVersionKey is the system key that must be changed whenever
+// the locality exclusion list changes.
+const excludedLocalityVersionKey = "\xff/conf/excludedLocality"
+
+// getExclusionKey gets the system key that records an exclusion.
+func getExclusionKey(address string) fdb.Key {
+	if isLocalityExclusion(address) {
+		return fdb.Key(excludedLocalityPrefix + address)
+	}
+	return fdb.Key(excludedServersPrefix + address)
+}
+
+// updateExclusionVersions changes the version keys for the exclusion lists
+// that are affected by a change to the exclusions for a list of addresses.
+func updateExclusionVersions(transaction fdb.Transaction, addresses []string) {
+	hasAddresses := false
+	hasLocalities := false
+	for _, address := range addresses {
+		if isLocalityExclusion(address) {
+			hasLocalities = true
+		} else {
+			hasAddresses = true
+		}
+	}
+	if hasAddresses {
+		transaction.Set(fdb.Key(excludedServersVersionKey), generateExclusionVersion())
+	}
+	if hasLocalities {
+		transaction.Set(fdb.Key(excludedLocalityVersionKey), generateExclusionVersion())
+	}
+}
+
+// DefaultUseNativeAdminClient determines whether we use the native admin
+// client for clusters that do not specify a preference in their spec.
+var DefaultUseNativeAdminClient = false
+
+// NewAdminClient creates an admin client for a cluster, using either the CLI
+// or the client library based on the cluster spec and the operator defaults.
+func NewAdminClient(cluster *fdbtypes.FoundationDBCluster, kubeClient client.Client) (AdminClient, error) {
+	if cluster.ShouldUseNativeAdminClient(DefaultUseNativeAdminClient) {
+		return NewNativeAdminClient(cluster, kubeClient)
+	}
+	return NewCliAdminClient(cluster, kubeClient)
+}
+
+// nativeDatabases holds the databases that the native admin clients have
+// opened, keyed by the connection string that was used to open them.
+//
+// The client library does not provide a way to destroy a database, so we
+// open each database once and reuse it for the life of the process. The
+// cluster files for these databases are never removed, since the client
+// library continues to use them.
+var nativeDatabases = make(map[string]fdb.Database)
+
+// nativeDatabasesLock protects access to nativeDatabases.
+var nativeDatabasesLock sync.Mutex
+
+// getNativeDatabase gets the database for a connection string, opening it
+// if no client has opened it yet.
+func getNativeDatabase(connectionString string) (fdb.Database, error) {
+	nativeDatabasesLock.Lock()
+	defer nativeDatabasesLock.Unlock()
+
+	database, present := nativeDatabases[connectionString]
+	if present {
+		return database, nil
+	}
+
+	clusterFile, err := ioutil.TempFile("", "")
+	if err != nil {
+		return database, err
+	}
+	clusterFilePath := clusterFile.Name()
+
+	defer clusterFile.Close()
+	_, err = clusterFile.WriteString(connectionString)
+	if err != nil {
+		return database, err
+	}
+	err = clusterFile.Close()
+	if err != nil {
+		return database, err
+	}
+
+	database, err = fdb.OpenDatabase(clusterFilePath)
+	if err != nil {
+		return database, err
+	}
+	nativeDatabases[connectionString] = database
+	return database, nil
+}
+
+// NativeAdminClient provides an implementation of the admin interface using
+// the FoundationDB client library.
+//
+// Operations that the client library does not expose, such as killing
+// processes and managing backups, are delegated to the CLI. Exclusions and
+// coordinator changes also go through the CLI so that they get the same
+// safety checks that fdbcli applies.
+type NativeAdminClient struct {
+	// Cluster is the reference to the cluster model.
+	Cluster *fdbtypes.FoundationDBCluster
+
+	// database is the connection to the database. This is shared with any
+	// other clients for the same connection string.
+	database fdb.Database
+
+	// cliClient is the client we use for the operations that are not
+	// supported through the client library.
+	cliClient *CliAdminClient
+}
+
+// NewNativeAdminClient generates an admin client for a cluster that uses the
+// client library.
+func NewNativeAdminClient(cluster *fdbtypes.FoundationDBCluster, kubeClient client.Client) (AdminClient, error) {
+	database, err := getNativeDatabase(cluster.Status.ConnectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	cliClient, err := NewCliAdminClient(cluster, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NativeAdminClient{
+		Cluster:   cluster,
+		database:  database,
+		cliClient: cliClient.(*CliAdminClient),
+	}, nil
+}
+
+// transact runs a transaction with access to the system keys and the same
+// timeout that we use for CLI commands.
+func (client *NativeAdminClient) transact(operation func(fdb.Transaction) (interface{}, error)) (interface{}, error) {
+	return client.database.Transact(func(transaction fdb.Transaction) (interface{}, error) {
+		err := transaction.Options().SetAccessSystemKeys()
+		if err != nil {
+			return nil, err
+		}
+		err = transaction.Options().SetLockAware()
+		if err != nil {
+			return nil, err
+		}
+		err = transaction.Options().SetTimeout(int64(DefaultCLITimeout * 1000))
+		if err != nil {
+			return nil, err
+		}
+		return operation(transaction)
+	})
+}
+
+// GetStatus gets the database's status
+func (client *NativeAdminClient) GetStatus() (*fdbtypes.FoundationDBStatus, error) {
+	statusBytes, err := client.transact(func(transaction fdb.Transaction) (interface{}, error) {
+		return transaction.Get(fdb.Key(statusJSONKey)).Get()
+	})
+	if err != nil {
+		return nil, newNativeAdminClientError("get status", err)
+	}
+
+	status := &fdbtypes.FoundationDBStatus{}
+	err = json.Unmarshal(statusBytes.([]byte), &status)
+	if err != nil {
+		return nil, AdminClientError{Reason: AdminClientUnparseableOutput, Command: "get status", Cause: err}
+	}
+	return status, nil
+}
+
+// ConfigureDatabase sets the database configuration
+func (client *NativeAdminClient) ConfigureDatabase(configuration fdbtypes.DatabaseConfiguration, newDatabase bool) error {
+	return client.cliClient.ConfigureDatabase(configuration, newDatabase)
+}
+
+// generateExclusionVersion generates a new random value for the exclusion
+// version key.
+func generateExclusionVersion() []byte {
+	version := make([]byte, 16)
+	for index := range version {
+		version[index] = byte(rand.Intn(256))
+	}
+	return []byte(fmt.Sprintf("%x", version))
+}
+
+// ExcludeInstances starts evacuating processes so that they can be removed
+// from the database.
+//
+// This goes through the CLI so that fdbcli can refuse exclusions that would
+// leave the database without enough space or fault tolerance.
+func (client *NativeAdminClient) ExcludeInstances(addresses []string) error {
+	return client.cliClient.ExcludeInstances(addresses)
+}
+
+// IncludeInstances removes processes from the exclusion list and allows
+// them to take on roles again.
+func (client *NativeAdminClient) IncludeInstances(addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	addressesWithoutFlags := removeAddressFlags(addresses)
+	log.Info("Including instances", "namespace", client.Cluster.Namespace, "cluster", client.Cluster.Name, "addresses", addressesWithoutFlags)
+	_, err := client.transact(func(transaction fdb.Transaction) (interface{}, error) {
+		updateExclusionVersions(transaction, addressesWithoutFlags)
+		for _, address := range addressesWithoutFlags {
+			transaction.Clear(getExclusionKey(address))
+		}
+		return nil, nil
+	})
+	return newNativeAdminClientError("include", err)
+}
+
+// CanSafelyRemove checks whether it is safe to remove processes from the
+// cluster
+//
+// The list returned by this method will be the addresses that are *not*
+// safe to remove.
+//
+// This goes through the CLI, which only reports a process as safe to remove
+// once the data distributor has moved all of its data to other processes.
+func (client *NativeAdminClient) CanSafelyRemove(addresses []string) ([]string, error) {
+	return client.cliClient.CanSafelyRemove(addresses)
+}
+
+// KillInstances restarts processes
+func (client *NativeAdminClient) KillInstances(addresses []string) error {
+	return client.cliClient.KillInstances(addresses)
+}
+
+// SetMaintenanceZone places a zone into maintenance mode.
+func (client *NativeAdminClient) SetMaintenanceZone(zone string, timeoutSeconds int) error {
+	return client.cliClient.SetMaintenanceZone(zone, timeoutSeconds)
+}
+
+// ResetMaintenanceMode takes the database out of maintenance mode.
+func (client *NativeAdminClient) ResetMaintenanceMode() error {
+	return client.cliClient.ResetMaintenanceMode()
+}
+
+// ChangeCoordinators changes the coordinator set
+//
+// This goes through the CLI so that the new coordinators are checked for
+// reachability and the change goes through the coordinators' quorum.
+func (client *NativeAdminClient) ChangeCoordinators(addresses []string) (string, error) {
+	return client.cliClient.ChangeCoordinators(addresses)
+}
+
+// GetConnectionString fetches the latest connection string.
+func (client *NativeAdminClient) GetConnectionString() (string, error) {
+	connectionStringBytes, err := client.transact(func(transaction fdb.Transaction) (interface{}, error) {
+		return transaction.Get(fdb.Key(coordinatorsKey)).Get()
+	})
+	if err != nil {
+		return "", newNativeAdminClientError("get connection string", err)
+	}
+
+	connectionString, err := fdbtypes.ParseConnectionString(string(connectionStringBytes.([]byte)))
+	if err != nil {
+		return "", AdminClientError{Reason: AdminClientUnparseableOutput, Command: "get connection string", Cause: err}
+	}
+	return connectionString.String(), nil
+}
+
+// VersionSupported reports whether we can support a cluster with a given
+// version.
+func (client *NativeAdminClient) VersionSupported(version string) (bool, error) {
+	return client.cliClient.VersionSupported(version)
+}
+
+// GetProtocolVersion determines the protocol version that is used by a
+// version of FDB.
+func (client *NativeAdminClient) GetProtocolVersion(version string) (string, error) {
+	return client.cliClient.GetProtocolVersion(version)
+}
+
+// StartBackup starts a new backup.
+func (client *NativeAdminClient) StartBackup(url string, snapshotPeriodSeconds int) error {
+	return client.cliClient.StartBackup(url, snapshotPeriodSeconds)
+}
+
+// StopBackup stops a backup.
+func (client *NativeAdminClient) StopBackup(url string) error {
+	return client.cliClient.StopBackup(url)
+}
+
+// PauseBackups pauses the backups.
+func (client *NativeAdminClient) PauseBackups() error {
+	return client.cliClient.PauseBackups()
+}
+
+// ResumeBackups resumes the backups.
+func (client *NativeAdminClient) ResumeBackups() error {
+	return client.cliClient.ResumeBackups()
+}
+
+// ModifyBackup updates the backup parameters.
+func (client *NativeAdminClient) ModifyBackup(snapshotPeriodSeconds int) error {
+	return client.cliClient.ModifyBackup(snapshotPeriodSeconds)
+}
+
+// GetBackupStatus gets the status of the current backup.
+func (client *NativeAdminClient) GetBackupStatus() (*fdbtypes.FoundationDBLiveBackupStatus, error) {
+	return client.cliClient.GetBackupStatus()
+}
+
+// StartRestore starts a new restore.
+func (client *NativeAdminClient) StartRestore(url string) error {
+	return client.cliClient.StartRestore(url)
+}
+
+// GetRestoreStatus gets the status of the current restore.
+func (client *NativeAdminClient) GetRestoreStatus() (string, error) {
+	return client.cliClient.GetRestoreStatus()
+}
+
+// Close cleans up any pending resources.
+//
+// The database is left open so that later clients can reuse it.
+func (client *NativeAdminClient) Close() error {
+	return client.cliClient.Close()
+}
@@ -29,6 +29,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -54,6 +55,9 @@ type FdbPodClient interface {
 	// CheckHash checks whether a file in the sidecar has the expected contents.
 	CheckHash(filename string, contents string) (bool, error)
 
+	// GetHash gets the SHA-256 hash of a file in the sidecar.
+	GetHash(filename string) (string, error)
+
 	// GenerateMonitorConf updates the monitor conf file for a pod
 	GenerateMonitorConf() error
 
@@ -152,7 +156,7 @@ func (client *realFdbPodClient) makeRequest(method string, path string) (string,
 		protocol = "http"
 	}
 
-	url := fmt.Sprintf("%s://%s:8080/%s", protocol, client.GetPodIP(), path)
+	url := fmt.Sprintf("%s://%s/%s", protocol, net.JoinHostPort(client.GetPodIP(), "8080"), path)
 	var resp *http.Response
 	var err error
 
@@ -204,7 +208,7 @@ func (client *realFdbPodClient) IsPresent(filename string) (bool, error) {
 
 // CheckHash checks whether a file in the sidecar has the expected contents.
 func (client *realFdbPodClient) CheckHash(filename string, contents string) (bool, error) {
-	response, err := client.makeRequest("GET", fmt.Sprintf("check_hash/%s", filename))
+	response, err := client.GetHash(filename)
 	if err != nil {
 		return false, err
 	}
@@ -214,6 +218,11 @@ func (client *realFdbPodClient) CheckHash(filename string, contents string) (boo
 	return strings.Compare(expectedHashString, response) == 0, nil
 }
 
+// GetHash gets the SHA-256 hash of a file in the sidecar.
+func (client *realFdbPodClient) GetHash(filename string) (string, error) {
+	return client.makeRequest("GET", fmt.Sprintf("check_hash/%s", filename))
+}
+
 // GenerateMonitorConf updates the monitor conf file for a pod
 func (client *realFdbPodClient) GenerateMonitorConf() error {
 	_, err := client.makeRequest("POST", "copy_monitor_conf")
@@ -252,6 +261,98 @@ func NewMockFdbPodClient(cluster *fdbtypes.FoundationDBCluster, pod *corev1.Pod)
 
 var mockMissingPodIPs map[string]bool
 
+// mockPodClientFaults provides the faults that have been injected into the
+// mock pod clients, indexed by pod name and then by method name.
+var mockPodClientFaults map[string]map[string]*MockFault
+
+// mockOutdatedPodFiles provides the names of pods whose dynamic files do not
+// match the expected contents.
+var mockOutdatedPodFiles map[string]bool
+
+// mockMissingPodBinaries provides the names of pods whose dynamic conf
+// volumes do not have any binaries.
+var mockMissingPodBinaries map[string]bool
+
+// mockMismatchedPodBinaries provides the names of pods whose binaries have
+// different contents from the binaries in the other pods.
+var mockMismatchedPodBinaries map[string]bool
+
+// InjectMockPodClientFault causes calls to a method on the mock pod clients
+// for a pod to fail.
+//
+// The method should be the name of a method in the FdbPodClient interface.
+// Injecting a new fault for a method replaces any existing fault for that
+// method on that pod.
+func InjectMockPodClientFault(podName string, method string, fault MockFault) {
+	if mockPodClientFaults == nil {
+		mockPodClientFaults = make(map[string]map[string]*MockFault)
+	}
+	if mockPodClientFaults[podName] == nil {
+		mockPodClientFaults[podName] = make(map[string]*MockFault)
+	}
+	mockPodClientFaults[podName][method] = &fault
+}
+
+// MockOutdatedPodFiles sets whether the dynamic files for a pod should be
+// reported as out of date. This will be cleared when the files are
+// successfully copied.
+func MockOutdatedPodFiles(podName string, outdated bool) {
+	if outdated {
+		if mockOutdatedPodFiles == nil {
+			mockOutdatedPodFiles = make(map[string]bool)
+		}
+		mockOutdatedPodFiles[podName] = true
+	} else {
+		delete(mockOutdatedPodFiles, podName)
+	}
+}
+
+// MockMissingPodBinaries sets whether the binaries for a pod should be
+// reported as missing from its dynamic conf volume.
+func MockMissingPodBinaries(podName string, missing bool) {
+	if missing {
+		if mockMissingPodBinaries == nil {
+			mockMissingPodBinaries = make(map[string]bool)
+		}
+		mockMissingPodBinaries[podName] = true
+	} else {
+		delete(mockMissingPodBinaries, podName)
+	}
+}
+
+// MockMismatchedPodBinaries sets whether the binaries for a pod should be
+// reported with different contents from the binaries in the other pods.
+func MockMismatchedPodBinaries(podName string, mismatched bool) {
+	if mismatched {
+		if mockMismatchedPodBinaries == nil {
+			mockMismatchedPodBinaries = make(map[string]bool)
+		}
+		mockMismatchedPodBinaries[podName] = true
+	} else {
+		delete(mockMismatchedPodBinaries, podName)
+	}
+}
+
+// ClearMockPodClientFaults removes all of the faults that have been injected
+// into the mock pod clients.
+func ClearMockPodClientFaults() {
+	mockPodClientFaults = nil
+	mockOutdatedPodFiles = nil
+	mockMissingPodBinaries = nil
+	mockMismatchedPodBinaries = nil
+}
+
+// NewMockSidecarError builds an error matching the one the pod client returns
+// when the sidecar responds with an error status.
+func NewMockSidecarError(statusCode int, body string) error {
+	return failedResponse{response: &http.Response{StatusCode: statusCode}, body: body}
+}
+
+// applyFault simulates the fault for a method, if one has been injected.
+func (client *mockFdbPodClient) applyFault(method string) error {
+	return applyMockFault(mockPodClientFaults[client.Pod.Name], method)
+}
+
 // MockPodIP generates a mock IP for FDB pod
 func MockPodIP(pod *corev1.Pod) string {
 	if mockMissingPodIPs != nil && mockMissingPodIPs[pod.ObjectMeta.Name] {
@@ -266,6 +367,15 @@ func MockPodIP(pod *corev1.Pod) string {
 	return "0.0.0.0"
 }
 
+// mockPublicIP gets the public IP for a mock pod, using the IP from its
+// service when one has been assigned.
+func mockPublicIP(pod *corev1.Pod) string {
+	if pod.ObjectMeta.Annotations[PublicIPAnnotation] != "" {
+		return pod.ObjectMeta.Annotations[PublicIPAnnotation]
+	}
+	return MockPodIP(pod)
+}
+
 // GetCluster returns the cluster associated with a client
 func (client *mockFdbPodClient) GetCluster() *fdbtypes.FoundationDBCluster {
 	return client.Cluster
@@ -283,22 +393,57 @@ func (client *mockFdbPodClient) GetPodIP() string {
 
 // IsPresent checks whether a file in the sidecar is prsent.
 func (client *mockFdbPodClient) IsPresent(filename string) (bool, error) {
+	err := client.applyFault("IsPresent")
+	if err != nil {
+		return false, err
+	}
+	if strings.HasPrefix(filename, "bin/") && mockMissingPodBinaries[client.Pod.Name] {
+		return false, nil
+	}
 	return true, nil
 }
 
 // CheckHash checks whether a file in the sidecar has the expected contents.
 func (client *mockFdbPodClient) CheckHash(filename string, contents string) (bool, error) {
-	return true, nil
+	err := client.applyFault("CheckHash")
+	if err != nil {
+		return false, err
+	}
+	return !mockOutdatedPodFiles[client.Pod.Name], nil
+}
+
+// GetHash gets the SHA-256 hash of a file in the sidecar.
+func (client *mockFdbPodClient) GetHash(filename string) (string, error) {
+	err := client.applyFault("GetHash")
+	if err != nil {
+		return "", err
+	}
+	contents := filename
+	if strings.HasPrefix(filename, "bin/") && mockMismatchedPodBinaries[client.Pod.Name] {
+		contents = fmt.Sprintf("%s-mismatched", filename)
+	}
+	hash := sha256.Sum256([]byte(contents))
+	return hex.EncodeToString(hash[:]), nil
 }
 
 // GenerateMonitorConf updates the monitor conf file for a pod
 func (client *mockFdbPodClient) GenerateMonitorConf() error {
+	err := client.applyFault("GenerateMonitorConf")
+	if err != nil {
+		return err
+	}
+	MockOutdatedPodFiles(client.Pod.Name, false)
 	return nil
 }
 
 // CopyFiles copies the files from the config map to the shared dynamic conf
 // volume
 func (client *mockFdbPodClient) CopyFiles() error {
+	err := client.applyFault("CopyFiles")
+	if err != nil {
+		return err
+	}
+	MockOutdatedPodFiles(client.Pod.Name, false)
 	return nil
 }
 
@@ -341,8 +486,16 @@ func CheckDynamicFilePresent(client FdbPodClient, filename string) (bool, error)
 // GetVariableSubstitutions gets the current keys and values that this
 // instance will substitute into its monitor conf.
 func (client *mockFdbPodClient) GetVariableSubstitutions() (map[string]string, error) {
+	err := client.applyFault("GetVariableSubstitutions")
+	if err != nil {
+		return nil, err
+	}
+
 	substitutions := map[string]string{}
-	substitutions["FDB_PUBLIC_IP"] = MockPodIP(client.Pod)
+	substitutions["FDB_PUBLIC_IP"] = mockPublicIP(client.Pod)
+	if client.Cluster.GetPublicIPSource() == fdbtypes.PublicIPSourceService {
+		substitutions["FDB_POD_IP"] = MockPodIP(client.Pod)
+	}
 	if client.Cluster.Spec.FaultDomain.Key == "foundationdb.org/none" {
 		substitutions["FDB_MACHINE_ID"] = client.Pod.Name
 		substitutions["FDB_ZONE_ID"] = client.Pod.Name
@@ -33,7 +33,7 @@ var _ = Describe("pod_client", func() {
 
 	BeforeEach(func() {
 		cluster = createDefaultCluster()
-		NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+		fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 	})
 
 	Context("with TLS disabled", func() {
@@ -59,4 +59,35 @@ var _ = Describe("pod_client", func() {
 			Expect(podHasSidecarTLS(pod)).To(BeTrue())
 		})
 	})
+
+	Context("with an injected fault", func() {
+		var client FdbPodClient
+
+		BeforeEach(func() {
+			pod, err := GetPod(context.TODO(), cluster, "storage", 1, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+			client, err = NewMockFdbPodClient(cluster, pod)
+			Expect(err).NotTo(HaveOccurred())
+			MockOutdatedPodFiles(pod.Name, true)
+			InjectMockPodClientFault(pod.Name, "CopyFiles", MockFault{Error: NewMockSidecarError(500, "internal error"), Count: 2})
+		})
+
+		AfterEach(func() {
+			ClearMockPodClientFaults()
+		})
+
+		It("should fail until the fault is exhausted", func() {
+			synced, err := UpdateDynamicFiles(client, "fdb.cluster", "", func(client FdbPodClient) error { return client.CopyFiles() })
+			Expect(synced).To(BeFalse())
+			Expect(err).To(MatchError("HTTP request failed. Status=500; response=internal error"))
+
+			synced, err = UpdateDynamicFiles(client, "fdb.cluster", "", func(client FdbPodClient) error { return client.CopyFiles() })
+			Expect(synced).To(BeFalse())
+			Expect(err).To(HaveOccurred())
+
+			synced, err = UpdateDynamicFiles(client, "fdb.cluster", "", func(client FdbPodClient) error { return client.CopyFiles() })
+			Expect(synced).To(BeTrue())
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
 })
@@ -29,6 +29,8 @@ import (
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -69,6 +71,21 @@ func GetPod(context ctx.Context, cluster *fdbtypes.FoundationDBCluster, processC
 	metadata.Name = name
 	metadata.OwnerReferences = owner
 
+	if cluster.GetPublicIPSource() == fdbtypes.PublicIPSourceService {
+		service := &corev1.Service{}
+		err = kubeClient.Get(context, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, service)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, ReconciliationNotReadyError{message: fmt.Sprintf("Waiting for service %s", name), retryable: true}
+			}
+			return nil, err
+		}
+		if service.Spec.ClusterIP == "" || service.Spec.ClusterIP == "None" {
+			return nil, ReconciliationNotReadyError{message: fmt.Sprintf("Service %s does not have a cluster IP", name), retryable: true}
+		}
+		metadata.Annotations[PublicIPAnnotation] = service.Spec.ClusterIP
+	}
+
 	return &corev1.Pod{
 		ObjectMeta: metadata,
 		Spec:       *spec,
@@ -233,38 +250,12 @@ func GetPodSpec(cluster *fdbtypes.FoundationDBCluster, processClass string, idNu
 		volumes = append(volumes, *volume.DeepCopy())
 	}
 
-	var affinity *corev1.Affinity
-
-	faultDomainKey := cluster.Spec.FaultDomain.Key
-	if faultDomainKey == "" {
-		faultDomainKey = "kubernetes.io/hostname"
-	}
-
-	if faultDomainKey != "foundationdb.org/none" && faultDomainKey != "foundationdb.org/kubernetes-cluster" {
-		affinity = &corev1.Affinity{
-			PodAntiAffinity: &corev1.PodAntiAffinity{
-				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
-					{
-						Weight: 1,
-						PodAffinityTerm: corev1.PodAffinityTerm{
-							TopologyKey: faultDomainKey,
-							LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
-								"fdb-cluster-name":  cluster.ObjectMeta.Name,
-								"fdb-process-class": processClass,
-							}},
-						},
-					},
-				},
-			},
-		}
-	}
-
 	replaceContainers(podSpec.InitContainers, initContainer)
 	podSpec.InitContainers = append(podSpec.InitContainers, cluster.Spec.InitContainers...)
 	replaceContainers(podSpec.Containers, mainContainer, sidecarContainer)
 	podSpec.Containers = append(podSpec.Containers, cluster.Spec.Containers...)
 	podSpec.Volumes = append(podSpec.Volumes, volumes...)
-	podSpec.Affinity = affinity
+	configurePodSpread(cluster, podSpec, processClass)
 
 	if cluster.Spec.PodSecurityContext != nil {
 		podSpec.SecurityContext = cluster.Spec.PodSecurityContext
@@ -286,6 +277,62 @@ func GetPodSpec(cluster *fdbtypes.FoundationDBCluster, processClass string, idNu
 	return podSpec, nil
 }
 
+// configurePodSpread adds the rules for spreading the pods in a process class
+// across fault domains, based on the spread strategy in the cluster spec.
+//
+// Pod anti-affinity or topology spread constraints that are already present
+// in the pod template take precedence over the rules from the spread
+// strategy.
+func configurePodSpread(cluster *fdbtypes.FoundationDBCluster, podSpec *corev1.PodSpec, processClass string) {
+	faultDomainKey := cluster.Spec.FaultDomain.Key
+	if faultDomainKey == "" {
+		faultDomainKey = "kubernetes.io/hostname"
+	}
+
+	if faultDomainKey == "foundationdb.org/none" || faultDomainKey == "foundationdb.org/kubernetes-cluster" {
+		return
+	}
+
+	term := corev1.PodAffinityTerm{
+		TopologyKey: faultDomainKey,
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+			"fdb-cluster-name":  cluster.ObjectMeta.Name,
+			"fdb-process-class": processClass,
+		}},
+	}
+
+	switch cluster.GetPodSpreadStrategy() {
+	case fdbtypes.PodSpreadStrategyPreferredAntiAffinity, fdbtypes.PodSpreadStrategyRequiredAntiAffinity:
+		if podSpec.Affinity == nil {
+			podSpec.Affinity = &corev1.Affinity{}
+		}
+		if podSpec.Affinity.PodAntiAffinity != nil {
+			return
+		}
+		if cluster.GetPodSpreadStrategy() == fdbtypes.PodSpreadStrategyRequiredAntiAffinity {
+			podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term},
+			}
+		} else {
+			podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+					{Weight: 1, PodAffinityTerm: term},
+				},
+			}
+		}
+	case fdbtypes.PodSpreadStrategyTopologySpread:
+		if len(podSpec.TopologySpreadConstraints) > 0 {
+			return
+		}
+		podSpec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       faultDomainKey,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     term.LabelSelector,
+		}}
+	}
+}
+
 // configureSidecarContainerForCluster sets up a sidecar container for a sidecar
 // in the FDB cluster.
 func configureSidecarContainerForCluster(cluster *fdbtypes.FoundationDBCluster, container *corev1.Container, initMode bool, instanceID string) error {
@@ -294,7 +341,22 @@ func configureSidecarContainerForCluster(cluster *fdbtypes.FoundationDBCluster,
 		versionString = cluster.Spec.Version
 	}
 
-	return configureSidecarContainer(container, initMode, instanceID, versionString, cluster.Spec.SidecarVariables, cluster.Spec.FaultDomain, cluster.Spec.SidecarContainer, cluster.Spec.SidecarVersions, cluster.Spec.SidecarVersion, len(cluster.Spec.TrustedCAs) > 0)
+	sidecarVariables := cluster.Spec.SidecarVariables
+	if instanceID != "" && cluster.GetPublicIPSource() == fdbtypes.PublicIPSourceService {
+		// The public IP belongs to the service, so the processes need the pod
+		// IP to know what address to listen on.
+		extendEnv(container,
+			corev1.EnvVar{Name: "FDB_PUBLIC_IP", ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: fmt.Sprintf("metadata.annotations['%s']", PublicIPAnnotation)},
+			}},
+			corev1.EnvVar{Name: "FDB_POD_IP", ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+			}},
+		)
+		sidecarVariables = append(append(make([]string, 0, len(sidecarVariables)+1), sidecarVariables...), "FDB_POD_IP")
+	}
+
+	return configureSidecarContainer(container, initMode, instanceID, versionString, sidecarVariables, cluster.Spec.FaultDomain, cluster.Spec.SidecarContainer, cluster.Spec.SidecarVersions, cluster.Spec.SidecarVersion, len(cluster.Spec.TrustedCAs) > 0)
 }
 
 // configureSidecarContainerForBackup sets up a sidecar container for the init
@@ -769,137 +831,77 @@ func GetHeadlessService(cluster *fdbtypes.FoundationDBCluster) (*corev1.Service,
 	return service, nil
 }
 
-// ensureContainerPresent looks for a container by name from a list, and adds
-// an empty container with that name if none is present.
-func ensureContainerPresent(containers []corev1.Container, name string, insertIndex int) ([]corev1.Container, int) {
-	for index, container := range containers {
-		if container.Name == name {
-			return containers, index
-		}
+// GetService builds a service that provides a stable public IP for an
+// instance.
+//
+// This will return nil if the cluster is not using services for the public
+// IPs.
+func GetService(cluster *fdbtypes.FoundationDBCluster, processClass string, idNum int) (*corev1.Service, error) {
+	if cluster.GetPublicIPSource() != fdbtypes.PublicIPSourceService {
+		return nil, nil
 	}
 
-	if insertIndex < 0 || insertIndex >= len(containers) {
-		containers = append(containers, corev1.Container{Name: name})
-		return containers, len(containers) - 1
-	}
-	containerCount := 1 + len(containers)
-	newContainers := make([]corev1.Container, 0, containerCount)
-	for indexToCopy := 0; indexToCopy < len(containers); indexToCopy++ {
-		if indexToCopy == insertIndex {
-			newContainers = append(newContainers, corev1.Container{
-				Name: name,
-			})
-		}
-		newContainers = append(newContainers, containers[indexToCopy])
-	}
+	name, id := getInstanceID(cluster, processClass, idNum)
 
-	return newContainers, insertIndex
-}
+	service := &corev1.Service{
+		ObjectMeta: getObjectMetadata(cluster, nil, processClass, id),
+	}
+	service.ObjectMeta.Name = name
+	service.Spec.Type = corev1.ServiceTypeClusterIP
+	service.Spec.Selector = getMinimalPodLabels(cluster, processClass, id)
 
-// customizeContainerFromList finds a container by name and runs a customization
-// function on the container.
-func customizeContainerFromList(containers []corev1.Container, name string, customizer func(*corev1.Container)) []corev1.Container {
-	containers, index := ensureContainerPresent(containers, name, -1)
-	container := containers[index]
-	customizer(&container)
-	containers[index] = container
-	return containers
-}
+	// The processes need to reach each other before their pods are ready.
+	service.Spec.PublishNotReadyAddresses = true
 
-// NormalizeClusterSpec converts a cluster spec into an unambiguous,
-// future-proof form, by applying any implicit defaults and moving configuration
-// from deprecated fields into fully-supported fields.
-func NormalizeClusterSpec(spec *fdbtypes.FoundationDBClusterSpec, defaults defaultsSelection) {
-	if spec.PodTemplate != nil {
-		if spec.Processes == nil {
-			spec.Processes = make(map[string]fdbtypes.ProcessSettings)
-		}
-		generalSettings := spec.Processes["general"]
-		if generalSettings.PodTemplate == nil {
-			generalSettings.PodTemplate = spec.PodTemplate
-		}
-		spec.Processes["general"] = generalSettings
-		spec.PodTemplate = nil
+	service.Spec.Ports = []corev1.ServicePort{
+		{
+			Name:       "tls",
+			Port:       4500,
+			TargetPort: intstr.FromInt(4500),
+		},
+		{
+			Name:       "non-tls",
+			Port:       4501,
+			TargetPort: intstr.FromInt(4501),
+		},
 	}
 
-	if !defaults.OnlyShowChanges {
-		// Set up resource requirements for the main container.
+	return service, nil
+}
 
-		if spec.Processes == nil {
-			spec.Processes = make(map[string]fdbtypes.ProcessSettings)
-		}
-		_, present := spec.Processes["general"]
-		if !present {
-			spec.Processes["general"] = fdbtypes.ProcessSettings{}
-		}
+// GetPodDisruptionBudget builds a pod disruption budget for the pods in a
+// cluster.
+//
+// The budget allows disrupting as many fault domains as the database can
+// lose while remaining fully available. We use a single budget for all of
+// the process classes, since losing processes of different classes in
+// different fault domains costs as much fault tolerance as losing processes
+// of the same class.
+func GetPodDisruptionBudget(cluster *fdbtypes.FoundationDBCluster) (*policyv1beta1.PodDisruptionBudget, error) {
+	if !cluster.ShouldManagePodDisruptionBudgets() {
+		return nil, nil
+	}
 
-		for processClass, settings := range spec.Processes {
-			if settings.PodTemplate == nil {
-				settings.PodTemplate = &corev1.PodTemplateSpec{}
-			}
+	budget := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: getObjectMetadata(cluster, nil, "", ""),
+	}
+	budget.ObjectMeta.Name = cluster.Name
+	budget.Spec.Selector = &metav1.LabelSelector{MatchLabels: getMinimalPodLabels(cluster, "", "")}
 
-			settings.PodTemplate.Spec.Containers, _ = ensureContainerPresent(settings.PodTemplate.Spec.Containers, "foundationdb", 0)
-
-			settings.PodTemplate.Spec.Containers = customizeContainerFromList(settings.PodTemplate.Spec.Containers, "foundationdb", func(container *corev1.Container) {
-				if container.Resources.Requests == nil {
-					container.Resources.Requests = corev1.ResourceList{
-						"cpu":    resource.MustParse("1"),
-						"memory": resource.MustParse("1Gi"),
-					}
-				}
-
-				if container.Resources.Limits == nil {
-					container.Resources.Limits = container.Resources.Requests
-				}
-			})
-
-			spec.Processes[processClass] = settings
-		}
-	}
-
-	// Apply changes between old and new defaults.
-	// When we update the defaults in the next release, the following sections
-	// should be moved under the `!OnlyShowChanges` section, and we should use
-	// the latest defaults as the active defaults.
-
-	// Set up sidecar resource requirements
-	if spec.Processes == nil {
-		spec.Processes = make(map[string]fdbtypes.ProcessSettings)
-	}
-	_, present := spec.Processes["general"]
-	if !present {
-		spec.Processes["general"] = fdbtypes.ProcessSettings{}
-	}
-
-	for processClass, settings := range spec.Processes {
-		if settings.PodTemplate == nil {
-			settings.PodTemplate = &corev1.PodTemplateSpec{}
-		}
-
-		sidecarUpdater := func(container *corev1.Container) {
-			if defaults.UseFutureDefaults {
-				if container.Resources.Requests == nil {
-					container.Resources.Requests = corev1.ResourceList{
-						"cpu":    resource.MustParse("100m"),
-						"memory": resource.MustParse("256Mi"),
-					}
-				}
-				if container.Resources.Limits == nil {
-					container.Resources.Limits = container.Resources.Requests
-				}
-			} else {
-				if container.Resources.Requests == nil {
-					container.Resources.Requests = corev1.ResourceList{}
-				}
-				if container.Resources.Limits == nil {
-					container.Resources.Limits = corev1.ResourceList{}
-				}
-			}
+	var maxUnavailable intstr.IntOrString
+	faultTolerance := cluster.DesiredFaultTolerance()
+	if cluster.Spec.PodDisruptionBudgets.MaxUnavailable == nil && cluster.Spec.FaultDomain.Key == "foundationdb.org/kubernetes-cluster" && faultTolerance > 0 {
+		// All of the pods in this Kubernetes cluster share a fault domain, so
+		// disrupting all of them costs no more than disrupting one.
+		maxUnavailable = intstr.FromString("100%")
+	} else {
+		podCount := faultTolerance
+		if cluster.Spec.PodDisruptionBudgets.MaxUnavailable != nil {
+			podCount = *cluster.Spec.PodDisruptionBudgets.MaxUnavailable
 		}
-
-		settings.PodTemplate.Spec.InitContainers = customizeContainerFromList(settings.PodTemplate.Spec.InitContainers, "foundationdb-kubernetes-init", sidecarUpdater)
-		settings.PodTemplate.Spec.Containers = customizeContainerFromList(settings.PodTemplate.Spec.Containers, "foundationdb-kubernetes-sidecar", sidecarUpdater)
-
-		spec.Processes[processClass] = settings
+		maxUnavailable = intstr.FromInt(podCount)
 	}
+	budget.Spec.MaxUnavailable = &maxUnavailable
+
+	return budget, nil
 }
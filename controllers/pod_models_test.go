@@ -30,6 +30,7 @@ import (
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -41,7 +42,7 @@ var _ = Describe("pod_models", func() {
 
 	BeforeEach(func() {
 		cluster = createDefaultCluster()
-		NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+		fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 	})
 
 	Describe("GetPod", func() {
@@ -143,7 +144,7 @@ var _ = Describe("pod_models", func() {
 						},
 					},
 				}}}
-				NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+				fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 
 				pod, err = GetPod(context.TODO(), cluster, "storage", 1, k8sClient)
 				Expect(err).NotTo(HaveOccurred())
@@ -387,7 +388,7 @@ var _ = Describe("pod_models", func() {
 						},
 					},
 				}}}
-				NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+				fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 
 				spec, err = GetPodSpec(cluster, "storage", 1)
 			})
@@ -534,6 +535,165 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		Context("with a required anti-affinity spread strategy", func() {
+			BeforeEach(func() {
+				cluster.Spec.FaultDomain = fdbtypes.FoundationDBClusterFaultDomain{
+					SpreadStrategy: fdbtypes.PodSpreadStrategyRequiredAntiAffinity,
+				}
+				spec, err = GetPodSpec(cluster, "storage", 1)
+			})
+
+			It("should set a required pod affinity", func() {
+				Expect(spec.Affinity).To(Equal(&corev1.Affinity{
+					PodAntiAffinity: &corev1.PodAntiAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+							{
+								TopologyKey: "kubernetes.io/hostname",
+								LabelSelector: &metav1.LabelSelector{
+									MatchLabels: map[string]string{
+										"fdb-cluster-name":  cluster.Name,
+										"fdb-process-class": "storage",
+									},
+								},
+							},
+						},
+					},
+				}))
+			})
+		})
+
+		Context("with a topology spread strategy", func() {
+			BeforeEach(func() {
+				cluster.Spec.FaultDomain = fdbtypes.FoundationDBClusterFaultDomain{
+					Key:            "topology.kubernetes.io/zone",
+					SpreadStrategy: fdbtypes.PodSpreadStrategyTopologySpread,
+				}
+				spec, err = GetPodSpec(cluster, "storage", 1)
+			})
+
+			It("should set a topology spread constraint", func() {
+				Expect(spec.TopologySpreadConstraints).To(Equal([]corev1.TopologySpreadConstraint{
+					{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: corev1.DoNotSchedule,
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"fdb-cluster-name":  cluster.Name,
+								"fdb-process-class": "storage",
+							},
+						},
+					},
+				}))
+			})
+
+			It("should leave the pod affinity empty", func() {
+				Expect(spec.Affinity).To(BeNil())
+			})
+
+			Context("with custom topology spread constraints", func() {
+				BeforeEach(func() {
+					cluster.Spec.Processes = map[string]fdbtypes.ProcessSettings{"general": {PodTemplate: &corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+								MaxSkew:           2,
+								TopologyKey:       "rack",
+								WhenUnsatisfiable: corev1.ScheduleAnyway,
+							}},
+						},
+					}}}
+					fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
+					spec, err = GetPodSpec(cluster, "storage", 1)
+				})
+
+				It("should use the custom constraints", func() {
+					Expect(spec.TopologySpreadConstraints).To(Equal([]corev1.TopologySpreadConstraint{{
+						MaxSkew:           2,
+						TopologyKey:       "rack",
+						WhenUnsatisfiable: corev1.ScheduleAnyway,
+					}}))
+				})
+			})
+		})
+
+		Context("with no spread strategy", func() {
+			BeforeEach(func() {
+				cluster.Spec.FaultDomain = fdbtypes.FoundationDBClusterFaultDomain{
+					SpreadStrategy: fdbtypes.PodSpreadStrategyNone,
+				}
+				spec, err = GetPodSpec(cluster, "storage", 1)
+			})
+
+			It("should leave the pod affinity empty", func() {
+				Expect(spec.Affinity).To(BeNil())
+				Expect(spec.TopologySpreadConstraints).To(BeNil())
+			})
+		})
+
+		Context("with a custom node affinity", func() {
+			var nodeAffinity *corev1.NodeAffinity
+
+			BeforeEach(func() {
+				nodeAffinity = &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key:      "disktype",
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{"ssd"},
+							}},
+						}},
+					},
+				}
+				cluster.Spec.FaultDomain = fdbtypes.FoundationDBClusterFaultDomain{}
+				cluster.Spec.Processes = map[string]fdbtypes.ProcessSettings{"general": {PodTemplate: &corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Affinity: &corev1.Affinity{NodeAffinity: nodeAffinity},
+					},
+				}}}
+				fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
+				spec, err = GetPodSpec(cluster, "storage", 1)
+			})
+
+			It("should keep the node affinity alongside the pod anti-affinity", func() {
+				Expect(spec.Affinity.NodeAffinity).To(Equal(nodeAffinity))
+				Expect(spec.Affinity.PodAntiAffinity).NotTo(BeNil())
+				Expect(len(spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)).To(Equal(1))
+			})
+		})
+
+		Context("with a custom pod anti-affinity", func() {
+			var podAntiAffinity *corev1.PodAntiAffinity
+
+			BeforeEach(func() {
+				podAntiAffinity = &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+						Weight: 10,
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							TopologyKey: "rack",
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"fdb-cluster-name": cluster.Name},
+							},
+						},
+					}},
+				}
+				cluster.Spec.FaultDomain = fdbtypes.FoundationDBClusterFaultDomain{
+					SpreadStrategy: fdbtypes.PodSpreadStrategyRequiredAntiAffinity,
+				}
+				cluster.Spec.Processes = map[string]fdbtypes.ProcessSettings{"general": {PodTemplate: &corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Affinity: &corev1.Affinity{PodAntiAffinity: podAntiAffinity},
+					},
+				}}}
+				fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
+				spec, err = GetPodSpec(cluster, "storage", 1)
+			})
+
+			It("should use the custom pod anti-affinity", func() {
+				Expect(spec.Affinity).To(Equal(&corev1.Affinity{PodAntiAffinity: podAntiAffinity}))
+			})
+		})
+
 		Context("with cross-Kubernetes replication", func() {
 			BeforeEach(func() {
 				cluster.Spec.FaultDomain = fdbtypes.FoundationDBClusterFaultDomain{
@@ -580,7 +740,7 @@ var _ = Describe("pod_models", func() {
 						}},
 					},
 				}}}
-				NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+				fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 
 				spec, err = GetPodSpec(cluster, "storage", 1)
 				Expect(err).NotTo(HaveOccurred())
@@ -907,7 +1067,7 @@ var _ = Describe("pod_models", func() {
 						},
 					},
 				}}}
-				NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+				fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 
 				spec, err = GetPodSpec(cluster, "storage", 1)
 				Expect(err).NotTo(HaveOccurred())
@@ -1357,6 +1517,53 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		Context("with public IPs from services", func() {
+			BeforeEach(func() {
+				cluster.Spec.Services.PublicIPSource = fdbtypes.PublicIPSourceService
+				spec, err = GetPodSpec(cluster, "storage", 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should get the public IP from the pod annotation", func() {
+				sidecarContainer := spec.Containers[1]
+				Expect(sidecarContainer.Name).To(Equal("foundationdb-kubernetes-sidecar"))
+				Expect(sidecarContainer.Env).To(Equal([]corev1.EnvVar{
+					{Name: "FDB_PUBLIC_IP", ValueFrom: &corev1.EnvVarSource{
+						FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations['foundationdb.org/public-ip']"},
+					}},
+					{Name: "FDB_POD_IP", ValueFrom: &corev1.EnvVarSource{
+						FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+					}},
+					{Name: "FDB_MACHINE_ID", ValueFrom: &corev1.EnvVarSource{
+						FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+					}},
+					{Name: "FDB_ZONE_ID", ValueFrom: &corev1.EnvVarSource{
+						FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+					}},
+					{Name: "FDB_INSTANCE_ID", Value: "storage-1"},
+					{Name: "FDB_TLS_VERIFY_PEERS", Value: ""},
+				}))
+			})
+
+			It("should substitute the pod IP in the monitor conf", func() {
+				sidecarContainer := spec.Containers[1]
+				Expect(sidecarContainer.Args).To(Equal([]string{
+					"--copy-file",
+					"fdb.cluster",
+					"--input-monitor-conf",
+					"fdbmonitor.conf",
+					"--copy-binary",
+					"fdbserver",
+					"--copy-binary",
+					"fdbcli",
+					"--main-container-version",
+					"6.2.20",
+					"--substitute-variable",
+					"FDB_POD_IP",
+				}))
+			})
+		})
+
 		Context("with custom map", func() {
 			BeforeEach(func() {
 				cluster.Spec.ConfigMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config1"}}
@@ -1382,7 +1589,7 @@ var _ = Describe("pod_models", func() {
 		Context("with custom pvc", func() {
 			BeforeEach(func() {
 				cluster.Spec.Processes = map[string]fdbtypes.ProcessSettings{"general": {VolumeClaimTemplate: &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim1"}}}}
-				NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+				fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 
 				spec, err = GetPodSpec(cluster, "storage", 1)
 				Expect(err).NotTo(HaveOccurred())
@@ -1400,7 +1607,7 @@ var _ = Describe("pod_models", func() {
 				generalSettings.VolumeClaim = &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim1"}}
 				cluster.Spec.Processes["general"] = generalSettings
 
-				NormalizeClusterSpec(&cluster.Spec, defaultsSelection{})
+				fdbtypes.NormalizeClusterSpec(&cluster.Spec, fdbtypes.DefaultsSelection{})
 
 				spec, err = GetPodSpec(cluster, "storage", 1)
 				Expect(err).NotTo(HaveOccurred())
@@ -1967,6 +2174,158 @@ var _ = Describe("pod_models", func() {
 		})
 	})
 
+	Describe("GetService", func() {
+		var service *corev1.Service
+
+		BeforeEach(func() {
+			cluster.Spec.Services.PublicIPSource = fdbtypes.PublicIPSourceService
+		})
+
+		JustBeforeEach(func() {
+			service, err = GetService(cluster, "storage", 1)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("with public IPs from services", func() {
+			It("should set the metadata on the service", func() {
+				Expect(service.ObjectMeta.Namespace).To(Equal("my-ns"))
+				Expect(service.ObjectMeta.Name).To(Equal("operator-test-1-storage-1"))
+				Expect(service.ObjectMeta.Labels).To(Equal(map[string]string{
+					"fdb-cluster-name":  "operator-test-1",
+					"fdb-process-class": "storage",
+					"fdb-instance-id":   "storage-1",
+				}))
+			})
+
+			It("should select the pod for the instance", func() {
+				Expect(service.Spec).To(Equal(corev1.ServiceSpec{
+					Type: corev1.ServiceTypeClusterIP,
+					Selector: map[string]string{
+						"fdb-cluster-name":  "operator-test-1",
+						"fdb-process-class": "storage",
+						"fdb-instance-id":   "storage-1",
+					},
+					PublishNotReadyAddresses: true,
+					Ports: []corev1.ServicePort{
+						{Name: "tls", Port: 4500, TargetPort: intstr.FromInt(4500)},
+						{Name: "non-tls", Port: 4501, TargetPort: intstr.FromInt(4501)},
+					},
+				}))
+			})
+		})
+
+		Context("with public IPs from pods", func() {
+			BeforeEach(func() {
+				cluster.Spec.Services.PublicIPSource = fdbtypes.PublicIPSourcePod
+			})
+
+			It("should return nil", func() {
+				Expect(service).To(BeNil())
+			})
+		})
+	})
+
+	Describe("GetPodDisruptionBudget", func() {
+		var budget *policyv1beta1.PodDisruptionBudget
+		var enabled bool
+
+		BeforeEach(func() {
+			enabled = true
+			cluster.Spec.PodDisruptionBudgets.Enabled = &enabled
+		})
+
+		JustBeforeEach(func() {
+			budget, err = GetPodDisruptionBudget(cluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("with the default config", func() {
+			It("should set the metadata on the budget", func() {
+				Expect(budget.ObjectMeta.Namespace).To(Equal("my-ns"))
+				Expect(budget.ObjectMeta.Name).To(Equal("operator-test-1"))
+				Expect(budget.ObjectMeta.Labels).To(Equal(map[string]string{
+					"fdb-cluster-name": "operator-test-1",
+				}))
+			})
+
+			It("should select the pods for every process class", func() {
+				Expect(budget.Spec.Selector).To(Equal(&metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"fdb-cluster-name": "operator-test-1",
+					},
+				}))
+			})
+
+			It("should allow disrupting one pod", func() {
+				Expect(*budget.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+			})
+		})
+
+		Context("with triple replication", func() {
+			BeforeEach(func() {
+				cluster.Spec.RedundancyMode = "triple"
+			})
+
+			It("should allow disrupting two pods", func() {
+				Expect(*budget.Spec.MaxUnavailable).To(Equal(intstr.FromInt(2)))
+			})
+		})
+
+		Context("with single replication", func() {
+			BeforeEach(func() {
+				cluster.Spec.RedundancyMode = "single"
+			})
+
+			It("should not allow disrupting any pods", func() {
+				Expect(*budget.Spec.MaxUnavailable).To(Equal(intstr.FromInt(0)))
+			})
+		})
+
+		Context("with a Kubernetes cluster as the fault domain", func() {
+			BeforeEach(func() {
+				cluster.Spec.FaultDomain = fdbtypes.FoundationDBClusterFaultDomain{
+					Key:   "foundationdb.org/kubernetes-cluster",
+					Value: "kc2",
+				}
+			})
+
+			It("should allow disrupting all of the pods", func() {
+				Expect(*budget.Spec.MaxUnavailable).To(Equal(intstr.FromString("100%")))
+			})
+		})
+
+		Context("with an explicit limit", func() {
+			BeforeEach(func() {
+				maxUnavailable := 3
+				cluster.Spec.PodDisruptionBudgets.MaxUnavailable = &maxUnavailable
+			})
+
+			It("should use the explicit limit", func() {
+				Expect(*budget.Spec.MaxUnavailable).To(Equal(intstr.FromInt(3)))
+			})
+		})
+
+		Context("with a zone in maintenance mode", func() {
+			BeforeEach(func() {
+				cluster.Status.MaintenanceZone = "zone1"
+			})
+
+			It("should not allow disrupting any more pods", func() {
+				Expect(*budget.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+			})
+		})
+
+		Context("with pod disruption budgets disabled", func() {
+			BeforeEach(func() {
+				enabled = false
+			})
+
+			It("should return nil", func() {
+				Expect(budget).To(BeNil())
+			})
+		})
+	})
+
 	Describe("GetBackupDeployment", func() {
 		var backup *fdbtypes.FoundationDBBackup
 		var deployment *appsv1.Deployment
@@ -2309,7 +2668,7 @@ var _ = Describe("pod_models", func() {
 
 		Describe("deprecations", func() {
 			JustBeforeEach(func() {
-				NormalizeClusterSpec(spec, defaultsSelection{})
+				fdbtypes.NormalizeClusterSpec(spec, fdbtypes.DefaultsSelection{})
 			})
 
 			Context("with a custom value for the Spec.PodTemplate field", func() {
@@ -2370,7 +2729,7 @@ var _ = Describe("pod_models", func() {
 		Describe("defaults", func() {
 			Context("with the current defaults", func() {
 				JustBeforeEach(func() {
-					NormalizeClusterSpec(spec, defaultsSelection{UseFutureDefaults: false, OnlyShowChanges: false})
+					fdbtypes.NormalizeClusterSpec(spec, fdbtypes.DefaultsSelection{UseFutureDefaults: false, OnlyShowChanges: false})
 				})
 
 				It("should have both containers", func() {
@@ -2499,7 +2858,7 @@ var _ = Describe("pod_models", func() {
 
 			Context("with the current defaults, changes only", func() {
 				JustBeforeEach(func() {
-					NormalizeClusterSpec(spec, defaultsSelection{UseFutureDefaults: false, OnlyShowChanges: true})
+					fdbtypes.NormalizeClusterSpec(spec, fdbtypes.DefaultsSelection{UseFutureDefaults: false, OnlyShowChanges: true})
 				})
 
 				It("should have a single container", func() {
@@ -2509,6 +2868,16 @@ var _ = Describe("pod_models", func() {
 					Expect(len(containers)).To(Equal(1))
 				})
 
+				It("should have pod disruption budgets disabled", func() {
+					Expect(spec.PodDisruptionBudgets.Enabled).NotTo(BeNil())
+					Expect(*spec.PodDisruptionBudgets.Enabled).To(BeFalse())
+				})
+
+				It("should not preserve healthy coordinators", func() {
+					Expect(spec.CoordinatorSelection.PreserveHealthyCoordinators).NotTo(BeNil())
+					Expect(*spec.CoordinatorSelection.PreserveHealthyCoordinators).To(BeFalse())
+				})
+
 				It("should have empty sidecar resource requirements", func() {
 					generalProcessConfig, present := spec.Processes["general"]
 					Expect(present).To(BeTrue())
@@ -2524,7 +2893,7 @@ var _ = Describe("pod_models", func() {
 
 			Context("with the future defaults", func() {
 				JustBeforeEach(func() {
-					NormalizeClusterSpec(spec, defaultsSelection{UseFutureDefaults: true, OnlyShowChanges: false})
+					fdbtypes.NormalizeClusterSpec(spec, fdbtypes.DefaultsSelection{UseFutureDefaults: true, OnlyShowChanges: false})
 				})
 
 				It("should have default sidecar resource requirements", func() {
@@ -2649,7 +3018,17 @@ var _ = Describe("pod_models", func() {
 
 			Context("with the future defaults, changes only", func() {
 				JustBeforeEach(func() {
-					NormalizeClusterSpec(spec, defaultsSelection{UseFutureDefaults: true, OnlyShowChanges: true})
+					fdbtypes.NormalizeClusterSpec(spec, fdbtypes.DefaultsSelection{UseFutureDefaults: true, OnlyShowChanges: true})
+				})
+
+				It("should have pod disruption budgets enabled", func() {
+					Expect(spec.PodDisruptionBudgets.Enabled).NotTo(BeNil())
+					Expect(*spec.PodDisruptionBudgets.Enabled).To(BeTrue())
+				})
+
+				It("should preserve healthy coordinators", func() {
+					Expect(spec.CoordinatorSelection.PreserveHealthyCoordinators).NotTo(BeNil())
+					Expect(*spec.CoordinatorSelection.PreserveHealthyCoordinators).To(BeTrue())
 				})
 
 				It("should have default sidecar resource requirements", func() {
@@ -2673,12 +3052,12 @@ var _ = Describe("pod_models", func() {
 				var originalSpec *fdbtypes.FoundationDBClusterSpec
 
 				BeforeEach(func() {
-					NormalizeClusterSpec(spec, defaultsSelection{UseFutureDefaults: false, OnlyShowChanges: true})
+					fdbtypes.NormalizeClusterSpec(spec, fdbtypes.DefaultsSelection{UseFutureDefaults: false, OnlyShowChanges: true})
 					originalSpec = spec.DeepCopy()
 				})
 
 				JustBeforeEach(func() {
-					NormalizeClusterSpec(spec, defaultsSelection{UseFutureDefaults: true, OnlyShowChanges: true})
+					fdbtypes.NormalizeClusterSpec(spec, fdbtypes.DefaultsSelection{UseFutureDefaults: true, OnlyShowChanges: true})
 				})
 
 				It("should be equal to the version with the old explicit defaults", func() {
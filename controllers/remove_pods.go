@@ -39,22 +39,33 @@ func (u RemovePods) Reconcile(r *FoundationDBClusterReconciler, context ctx.Cont
 	if len(cluster.Status.PendingRemovals) == 0 {
 		return true, nil
 	}
-	r.Recorder.Event(cluster, "Normal", "RemovingProcesses", fmt.Sprintf("Removing pods: %v", cluster.Status.PendingRemovals))
-	for _, state := range cluster.Status.PendingRemovals {
-		if state.PodName != "" {
-			err := r.removePod(context, cluster, state.PodName)
-			if err != nil {
-				return false, err
-			}
+
+	podNames := make([]string, 0, len(cluster.Status.PendingRemovals))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if !processGroup.HasCondition(fdbtypes.MarkedForRemoval) {
+			continue
+		}
+		if !processGroup.HasCondition(fdbtypes.Excluded) {
+			return false, ReconciliationNotReadyError{message: fmt.Sprintf("Waiting for instance %s to be excluded", processGroup.InstanceID), retryable: true}
+		}
+		podName := cluster.Status.PendingRemovals[processGroup.InstanceID].PodName
+		if podName != "" {
+			podNames = append(podNames, podName)
+		}
+	}
+
+	r.Recorder.Event(cluster, "Normal", "RemovingProcesses", fmt.Sprintf("Removing pods: %v", podNames))
+	for _, podName := range podNames {
+		err := r.removePod(context, cluster, podName)
+		if err != nil {
+			return false, err
 		}
 	}
 
-	for _, state := range cluster.Status.PendingRemovals {
-		if state.PodName != "" {
-			removed, err := r.confirmPodRemoval(context, cluster, state.PodName)
-			if !removed {
-				return removed, err
-			}
+	for _, podName := range podNames {
+		removed, err := r.confirmPodRemoval(context, cluster, podName)
+		if !removed {
+			return removed, err
 		}
 	}
 
@@ -68,7 +79,7 @@ func (r *FoundationDBClusterReconciler) removePod(context ctx.Context, cluster *
 	}
 	if len(instances) > 0 {
 		err = r.PodLifecycleManager.DeleteInstance(r, context, instances[0])
-
+		r.getAdminClientSession(cluster).InvalidateStatus()
 		if err != nil {
 			return err
 		}
@@ -26,6 +26,7 @@ import (
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -39,24 +40,80 @@ func (u RemoveServices) Reconcile(r *FoundationDBClusterReconciler, context ctx.
 		return false, err
 	}
 
-	if service != nil {
-		return true, nil
+	if service == nil {
+		existingServices := &corev1.ServiceList{}
+		err = r.List(context, existingServices, client.InNamespace(cluster.Namespace), client.MatchingField("metadata.name", cluster.Name))
+		if err != nil {
+			return false, err
+		}
+
+		if len(existingServices.Items) > 0 {
+			err = r.Delete(context, &existingServices.Items[0])
+			if err != nil {
+				return false, err
+			}
+		}
 	}
 
-	existingServices := &corev1.ServiceList{}
-	err = r.List(context, existingServices, client.InNamespace(cluster.Namespace), client.MatchingField("metadata.name", cluster.Name))
+	err = removeInstanceServices(r, context, cluster)
 	if err != nil {
 		return false, err
 	}
 
-	if len(existingServices.Items) > 0 {
-		err = r.Delete(context, &existingServices.Items[0])
-		if err != nil {
-			return false, err
+	return true, nil
+}
+
+// removeInstanceServices removes the services that provide the public IPs
+// for instances that no longer need them.
+//
+// A service is kept while its instance still has a pod, or while its
+// instance is waiting for its pod to be recreated, so that the instance keeps
+// its address. Once the instance has been removed, or the cluster has stopped
+// using services for the public IPs, the service is deleted.
+func removeInstanceServices(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) error {
+	services := &corev1.ServiceList{}
+	err := r.List(context, services, getPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return err
+	}
+
+	instances, err := r.PodLifecycleManager.GetInstances(r, cluster, context, getPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return err
+	}
+
+	pods := make(map[string]*corev1.Pod, len(instances))
+	for _, instance := range instances {
+		pods[instance.GetInstanceID()] = instance.Pod
+	}
+
+	usesServiceIPs := cluster.GetPublicIPSource() == fdbtypes.PublicIPSourceService
+
+	for index := range services.Items {
+		service := &services.Items[index]
+		instanceID := GetInstanceIDFromMeta(service.ObjectMeta)
+		if instanceID == "" || !metav1.IsControlledBy(service, cluster) {
+			continue
+		}
+
+		pod := pods[instanceID]
+		var needsRemoval bool
+		if usesServiceIPs {
+			needsRemoval = pod == nil && cluster.InstanceIsBeingRemoved(instanceID)
+		} else {
+			needsRemoval = pod == nil || pod.ObjectMeta.Annotations[PublicIPAnnotation] == ""
+		}
+
+		if needsRemoval {
+			log.Info("Deleting service", "namespace", cluster.Namespace, "cluster", cluster.Name, "name", service.Name)
+			err = r.Delete(context, service)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
-	return true, nil
+	return nil
 }
 
 // RequeueAfter returns the delay before we should run the reconciliation
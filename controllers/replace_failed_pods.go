@@ -0,0 +1,161 @@
+/*
+ * replace_failed_pods.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2019 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	ctx "context"
+	"fmt"
+	"sort"
+	"time"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+)
+
+// ReplaceFailedPods identifies processes that have been missing from the
+// database for longer than the failure detection time, and marks them for
+// removal so that they will be replaced.
+type ReplaceFailedPods struct{}
+
+// Reconcile runs the reconciler's work.
+func (c ReplaceFailedPods) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
+	if !cluster.ShouldReplaceMissingProcesses() {
+		return true, nil
+	}
+
+	candidates := getFailedProcessGroups(cluster, time.Now().Unix())
+	if len(candidates) == 0 {
+		return true, nil
+	}
+
+	availableReplacements := cluster.GetMaxConcurrentReplacements() - len(cluster.Status.PendingRemovals)
+	if availableReplacements <= 0 {
+		log.Info("Deferring automatic replacements because too many processes are pending removal", "namespace", cluster.Namespace, "cluster", cluster.Name, "pendingRemovals", len(cluster.Status.PendingRemovals))
+		return true, nil
+	}
+
+	status, err := r.getAdminClientSession(cluster).GetStatus()
+	if err != nil {
+		return false, err
+	}
+
+	if !status.Client.DatabaseStatus.Available || status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingData < 1 {
+		log.Info("Deferring automatic replacements because the database has no fault tolerance", "namespace", cluster.Namespace, "cluster", cluster.Name)
+		r.Recorder.Event(cluster, "Normal", "ReplacementsDeferred", "Automatic replacements are waiting for the database to regain fault tolerance")
+		return true, nil
+	}
+
+	removals := cluster.Status.PendingRemovals
+	if removals == nil {
+		removals = make(map[string]fdbtypes.PendingRemovalState)
+	}
+
+	hasNewRemovals := false
+	for _, processGroup := range candidates {
+		if availableReplacements <= 0 {
+			break
+		}
+
+		instances, err := r.PodLifecycleManager.GetInstances(r, cluster, context, getSinglePodListOptions(cluster, processGroup.InstanceID)...)
+		if err != nil {
+			return false, err
+		}
+		if len(instances) == 0 {
+			continue
+		}
+
+		missingTime := time.Now().Unix() - *processGroup.GetConditionTime(fdbtypes.MissingProcess)
+		log.Info("Replacing missing process", "namespace", cluster.Namespace, "cluster", cluster.Name, "instance", processGroup.InstanceID, "missingSeconds", missingTime)
+		r.Recorder.Event(cluster, "Normal", "ReplacingMissingProcess", fmt.Sprintf("Replacing instance %s, which has been missing for %d seconds", processGroup.InstanceID, missingTime))
+
+		removals[processGroup.InstanceID] = r.getPendingRemovalState(instances[0])
+		cluster.Status.ProcessCounts.IncreaseCount(processGroup.ProcessClass, -1)
+		availableReplacements--
+		hasNewRemovals = true
+	}
+
+	if hasNewRemovals {
+		cluster.Status.PendingRemovals = removals
+		err = r.updatePendingRemovals(context, cluster)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// getFailedProcessGroups gets the process groups that have been missing for
+// longer than the failure detection time, and are not already being removed.
+//
+// The process groups are sorted so that the ones that have been missing the
+// longest come first.
+func getFailedProcessGroups(cluster *fdbtypes.FoundationDBCluster, now int64) []fdbtypes.ProcessGroupStatus {
+	failed := make([]fdbtypes.ProcessGroupStatus, 0)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.HasCondition(fdbtypes.MarkedForRemoval) {
+			continue
+		}
+
+		missingTime := processGroup.GetConditionTime(fdbtypes.MissingProcess)
+		if missingTime != nil && now-*missingTime >= int64(cluster.GetFailureDetectionTimeSeconds()) {
+			failed = append(failed, processGroup)
+		}
+	}
+
+	sort.Slice(failed, func(i, j int) bool {
+		return *failed[i].GetConditionTime(fdbtypes.MissingProcess) < *failed[j].GetConditionTime(fdbtypes.MissingProcess)
+	})
+	return failed
+}
+
+// getReplacementCheckDelay gets the delay before we should run reconciliation
+// again in order to replace processes that are currently missing.
+//
+// This will return 0 if there are no missing processes that could be
+// replaced.
+func getReplacementCheckDelay(cluster *fdbtypes.FoundationDBCluster, now int64) time.Duration {
+	if !cluster.ShouldReplaceMissingProcesses() {
+		return 0
+	}
+
+	var delay time.Duration
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		missingTime := processGroup.GetConditionTime(fdbtypes.MissingProcess)
+		if missingTime == nil || processGroup.HasCondition(fdbtypes.MarkedForRemoval) {
+			continue
+		}
+
+		remaining := time.Duration(*missingTime+int64(cluster.GetFailureDetectionTimeSeconds())-now) * time.Second
+		if remaining < time.Minute {
+			remaining = time.Minute
+		}
+		if delay == 0 || remaining < delay {
+			delay = remaining
+		}
+	}
+	return delay
+}
+
+// RequeueAfter returns the delay before we should run the reconciliation
+// again.
+func (c ReplaceFailedPods) RequeueAfter() time.Duration {
+	return 0
+}
@@ -143,6 +143,7 @@ func (c ReplaceMisconfiguredPods) Reconcile(r *FoundationDBClusterReconciler, co
 
 	if hasNewRemovals {
 		cluster.Status.PendingRemovals = removals
+		cluster.Status.UpdateProcessGroupRemovals()
 		err = r.Status().Update(context, cluster)
 		if err != nil {
 			return false, err
@@ -45,7 +45,7 @@ var _ = Describe("restore_controller", func() {
 		ClearMockAdminClients()
 		cluster = createDefaultCluster()
 		restore = createDefaultRestore(cluster)
-		adminClient, err = newMockAdminClientUncast(cluster, k8sClient)
+		adminClient, err = NewMockAdminClientUncast(cluster, k8sClient)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
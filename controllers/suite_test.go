@@ -22,6 +22,7 @@ package controllers
 
 import (
 	"context"
+	"io/ioutil"
 	"path/filepath"
 	"testing"
 	"time"
@@ -34,6 +35,8 @@ import (
 	"github.com/onsi/gomega/gexec"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -41,6 +44,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -66,8 +70,10 @@ var _ = BeforeSuite(func(done Done) {
 	logf.SetLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(GinkgoWriter)))
 
 	By("bootstrapping test environment")
+	crds, err := loadCRDs(filepath.Join("..", "config", "crd", "bases"))
+	Expect(err).NotTo(HaveOccurred())
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths: []string{filepath.Join("..", "config", "crd", "bases")},
+		CRDs: crds,
 	}
 
 	cfg, err := testEnv.Start()
@@ -132,6 +138,39 @@ var _ = BeforeSuite(func(done Done) {
 	close(done)
 }, 60)
 
+// loadCRDs reads the CRDs for the test environment.
+//
+// The test environment does not run the conversion webhook, so we store the
+// resources in the version that the controllers use. Otherwise the API server
+// would drop the fields that are not present in the storage version.
+func loadCRDs(directory string) ([]*apiextensionsv1beta1.CustomResourceDefinition, error) {
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	crds := make([]*apiextensionsv1beta1.CustomResourceDefinition, 0, len(files))
+	for _, file := range files {
+		if filepath.Ext(file.Name()) != ".yaml" {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(directory, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		crd := &apiextensionsv1beta1.CustomResourceDefinition{}
+		err = yaml.Unmarshal(data, crd)
+		if err != nil {
+			return nil, err
+		}
+		for index := range crd.Spec.Versions {
+			crd.Spec.Versions[index].Storage = crd.Spec.Versions[index].Name == fdbtypes.GroupVersion.Version
+		}
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
+
 var _ = AfterSuite(func() {
 	By("tearing down the test environment")
 	gexec.KillAndWait(5 * time.Second)
@@ -140,7 +179,7 @@ var _ = AfterSuite(func() {
 })
 
 var Versions = struct {
-	NextMajorVersion,
+	NextMajorVersion, NextMinorVersion, NextPatchVersion,
 	WithSidecarInstanceIDSubstitution, WithoutSidecarInstanceIDSubstitution,
 	WithCommandLineVariablesForSidecar, WithEnvironmentVariablesForSidecar,
 	WithBinariesFromMainContainer, WithoutBinariesFromMainContainer,
@@ -150,6 +189,8 @@ var Versions = struct {
 }{
 	Default:                              fdbtypes.FdbVersion{Major: 6, Minor: 2, Patch: 20},
 	NextMajorVersion:                     fdbtypes.FdbVersion{Major: 7, Minor: 0, Patch: 0},
+	NextMinorVersion:                     fdbtypes.FdbVersion{Major: 6, Minor: 3, Patch: 0},
+	NextPatchVersion:                     fdbtypes.FdbVersion{Major: 6, Minor: 2, Patch: 21},
 	WithSidecarInstanceIDSubstitution:    fdbtypes.FdbVersion{Major: 6, Minor: 2, Patch: 15},
 	WithoutSidecarInstanceIDSubstitution: fdbtypes.FdbVersion{Major: 6, Minor: 2, Patch: 11},
 	WithCommandLineVariablesForSidecar:   fdbtypes.FdbVersion{Major: 6, Minor: 2, Patch: 15},
@@ -248,6 +289,15 @@ func cleanupCluster(cluster *fdbtypes.FoundationDBCluster) {
 		err = k8sClient.Delete(context.TODO(), &item)
 		Expect(err).NotTo(HaveOccurred())
 	}
+
+	budgets := &policyv1beta1.PodDisruptionBudgetList{}
+	err = k8sClient.List(context.TODO(), budgets, getListOptions(cluster)...)
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, item := range budgets.Items {
+		err = k8sClient.Delete(context.TODO(), &item)
+		Expect(err).NotTo(HaveOccurred())
+	}
 }
 
 func cleanupBackup(backup *fdbtypes.FoundationDBBackup) {
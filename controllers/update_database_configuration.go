@@ -36,19 +36,18 @@ type UpdateDatabaseConfiguration struct{}
 
 // Reconcile runs the reconciler's work.
 func (u UpdateDatabaseConfiguration) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
-	adminClient, err := r.AdminClientProvider(cluster, r)
-
+	session := r.getAdminClientSession(cluster)
+	adminClient, err := session.GetAdminClient()
 	if err != nil {
 		return false, err
 	}
-	defer adminClient.Close()
 
 	desiredConfiguration := cluster.DesiredDatabaseConfiguration()
 	desiredConfiguration.RoleCounts.Storage = 0
 	needsChange := false
 	var currentConfiguration fdbtypes.DatabaseConfiguration
 
-	status, err := adminClient.GetStatus()
+	status, err := session.GetStatus()
 	if err != nil {
 		return false, err
 	}
@@ -121,6 +120,7 @@ func (u UpdateDatabaseConfiguration) Reconcile(r *FoundationDBClusterReconciler,
 			fmt.Sprintf("Setting database configuration to `%s`", configurationString),
 		)
 		err = adminClient.ConfigureDatabase(nextConfiguration, initialConfig)
+		session.InvalidateStatus()
 		if err != nil {
 			return false, err
 		}
@@ -0,0 +1,103 @@
+/*
+ * update_pod_disruption_budgets.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2020 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	ctx "context"
+	"reflect"
+	"time"
+
+	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UpdatePodDisruptionBudgets provides a reconciliation step for keeping the
+// pod disruption budget for the cluster in sync with the cluster spec.
+//
+// The operator deletes pods directly rather than evicting them, so the budget
+// does not need to be relaxed while the operator is updating pods. The pods
+// that the operator takes down still count against the budget, which prevents
+// a node drain from taking down another fault domain at the same time.
+type UpdatePodDisruptionBudgets struct{}
+
+// Reconcile runs the reconciler's work.
+func (u UpdatePodDisruptionBudgets) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
+	existingBudgets := &policyv1beta1.PodDisruptionBudgetList{}
+	err := r.List(context, existingBudgets, getPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return false, err
+	}
+
+	budget, err := GetPodDisruptionBudget(cluster)
+	if err != nil {
+		return false, err
+	}
+
+	var existingBudget *policyv1beta1.PodDisruptionBudget
+	for index := range existingBudgets.Items {
+		if budget != nil && existingBudgets.Items[index].Name == budget.Name {
+			existingBudget = &existingBudgets.Items[index]
+			continue
+		}
+		if !metav1.IsControlledBy(&existingBudgets.Items[index], cluster) {
+			continue
+		}
+
+		log.Info("Deleting pod disruption budget", "namespace", cluster.Namespace, "cluster", cluster.Name, "name", existingBudgets.Items[index].Name)
+		err = r.Delete(context, &existingBudgets.Items[index])
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if budget == nil {
+		return true, nil
+	}
+
+	if existingBudget == nil {
+		log.Info("Creating pod disruption budget", "namespace", cluster.Namespace, "cluster", cluster.Name, "maxUnavailable", budget.Spec.MaxUnavailable.String())
+		budget.ObjectMeta.OwnerReferences = buildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta)
+		err = r.Create(context, budget)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if !reflect.DeepEqual(existingBudget.Spec.MaxUnavailable, budget.Spec.MaxUnavailable) || !reflect.DeepEqual(existingBudget.Spec.Selector, budget.Spec.Selector) {
+		log.Info("Updating pod disruption budget", "namespace", cluster.Namespace, "cluster", cluster.Name, "maxUnavailable", budget.Spec.MaxUnavailable.String())
+		existingBudget.Spec.MaxUnavailable = budget.Spec.MaxUnavailable
+		existingBudget.Spec.Selector = budget.Spec.Selector
+		err = r.Update(context, existingBudget)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// RequeueAfter returns the delay before we should run the reconciliation
+// again.
+func (u UpdatePodDisruptionBudgets) RequeueAfter() time.Duration {
+	return 0
+}
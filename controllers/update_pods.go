@@ -23,6 +23,7 @@ package controllers
 import (
 	ctx "context"
 	"fmt"
+	"sort"
 	"time"
 
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
@@ -35,6 +36,14 @@ type UpdatePods struct{}
 
 // Reconcile runs the reconciler's work.
 func (u UpdatePods) Reconcile(r *FoundationDBClusterReconciler, context ctx.Context, cluster *fdbtypes.FoundationDBCluster) (bool, error) {
+	ready, err := r.clearMaintenanceZone(context, cluster)
+	if err != nil {
+		return false, err
+	}
+	if !ready {
+		return false, ReconciliationNotReadyError{message: "Waiting for processes to rejoin before leaving maintenance mode", retryable: true}
+	}
+
 	instances, err := r.PodLifecycleManager.GetInstances(r, cluster, context, getPodListOptions(cluster, "", "")...)
 	if err != nil {
 		return false, err
@@ -42,8 +51,6 @@ func (u UpdatePods) Reconcile(r *FoundationDBClusterReconciler, context ctx.Cont
 
 	updates := make(map[string][]FdbInstance)
 
-	removals := cluster.Status.PendingRemovals
-
 	for _, instance := range instances {
 		if instance.Pod == nil {
 			continue
@@ -51,11 +58,9 @@ func (u UpdatePods) Reconcile(r *FoundationDBClusterReconciler, context ctx.Cont
 
 		instanceID := instance.GetInstanceID()
 
-		if removals != nil {
-			_, pendingRemoval := removals[instanceID]
-			if pendingRemoval {
-				continue
-			}
+		processGroup := cluster.Status.GetProcessGroup(instanceID)
+		if processGroup != nil && processGroup.HasCondition(fdbtypes.MarkedForRemoval) {
+			continue
 		}
 
 		if instance.Pod.DeletionTimestamp != nil && !cluster.InstanceIsBeingRemoved(instanceID) {
@@ -73,18 +78,10 @@ func (u UpdatePods) Reconcile(r *FoundationDBClusterReconciler, context ctx.Cont
 		}
 
 		if instance.Metadata.Annotations[LastSpecKey] != specHash {
-			podClient, err := r.getPodClient(cluster, instance)
-			if err != nil {
-				return false, err
-			}
-			substitutions, err := podClient.GetVariableSubstitutions()
+			zone, err := r.getInstanceZone(cluster, instance)
 			if err != nil {
 				return false, err
 			}
-			zone := substitutions["FDB_ZONE_ID"]
-			if r.InSimulation {
-				zone = "simulation"
-			}
 			if updates[zone] == nil {
 				updates[zone] = make([]FdbInstance, 0)
 			}
@@ -116,38 +113,57 @@ func (u UpdatePods) Reconcile(r *FoundationDBClusterReconciler, context ctx.Cont
 		}
 	}
 
-	for zone, zoneInstances := range updates {
-		log.Info("Deleting pods", "namespace", cluster.Namespace, "cluster", cluster.Name, "zone", zone, "count", len(zoneInstances))
-		r.Recorder.Event(cluster, "Normal", "UpdatingPods", fmt.Sprintf("Recreating pods in zone %s", zone))
-		ready, err := r.PodLifecycleManager.CanDeletePods(r, context, cluster)
-		if err != nil {
-			return false, err
-		}
-		if !ready {
-			return false, ReconciliationNotReadyError{message: "Reconciliation requires deleting pods, but deletion is not currently safe"}
-		}
+	if len(updates) == 0 {
+		return true, nil
+	}
 
-		lockClient, err := r.getLockClient(cluster)
-		if err != nil {
-			return false, err
-		}
+	// We only update one zone at a time, since the database can only have
+	// one zone in maintenance mode.
+	zones := make([]string, 0, len(updates))
+	for zone := range updates {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	zone := zones[0]
+	zoneInstances := updates[zone]
 
-		hasLock, err := lockClient.TakeLock()
-		if err != nil {
-			return false, err
-		}
-		if !hasLock {
-			log.Info("Failed to get lock", "namespace", cluster.Namespace, "cluster", cluster.Name)
-			r.Recorder.Event(cluster, "Normal", "LockAcquisitionFailed", "Lock required before updating pods")
-			return false, nil
-		}
+	log.Info("Deleting pods", "namespace", cluster.Namespace, "cluster", cluster.Name, "zone", zone, "count", len(zoneInstances))
+	r.Recorder.Event(cluster, "Normal", "UpdatingPods", fmt.Sprintf("Recreating pods in zone %s", zone))
+	ready, err = r.PodLifecycleManager.CanDeletePods(r, context, cluster)
+	if err != nil {
+		return false, err
+	}
+	if !ready {
+		return false, ReconciliationNotReadyError{message: "Reconciliation requires deleting pods, but deletion is not currently safe"}
+	}
 
-		err = r.PodLifecycleManager.UpdatePods(r, context, cluster, zoneInstances)
-		if err != nil {
-			return false, err
-		}
+	lockClient, err := r.getLockClient(cluster)
+	if err != nil {
+		return false, err
+	}
+
+	hasLock, err := lockClient.TakeLock()
+	if err != nil {
+		return false, err
+	}
+	if !hasLock {
+		log.Info("Failed to get lock", "namespace", cluster.Namespace, "cluster", cluster.Name)
+		r.Recorder.Event(cluster, "Normal", "LockAcquisitionFailed", "Lock required before updating pods")
+		return false, nil
+	}
+
+	err = r.setMaintenanceZone(context, cluster, zone)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.PodLifecycleManager.UpdatePods(r, context, cluster, zoneInstances)
+	r.getAdminClientSession(cluster).InvalidateStatus()
+	if err != nil {
+		return false, err
 	}
-	return true, nil
+
+	return len(updates) == 1, nil
 }
 
 // RequeueAfter returns the delay before we should run the reconciliation
@@ -31,6 +31,7 @@ import (
 	fdbtypes "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -59,12 +60,10 @@ func (s UpdateStatus) Reconcile(r *FoundationDBClusterReconciler, context ctx.Co
 			},
 		}
 	} else {
-		adminClient, err := r.AdminClientProvider(cluster, r)
-		if err != nil {
-			return false, err
-		}
-		defer adminClient.Close()
-		databaseStatus, err = adminClient.GetStatus()
+		session := r.getAdminClientSession(cluster)
+		session.InvalidateStatus()
+		var err error
+		databaseStatus, err = session.GetStatus()
 		if err != nil {
 			if cluster.Spec.Version != cluster.Status.RunningVersion && cluster.Status.RunningVersion != "" {
 				log.Info("Failed to get status; falling back to version from spec", "runningVersion", cluster.Status.RunningVersion, "newVersion", cluster.Spec.Version)
@@ -76,7 +75,7 @@ func (s UpdateStatus) Reconcile(r *FoundationDBClusterReconciler, context ctx.Co
 					return false, clientErr
 				}
 				defer fallbackAdminClient.Close()
-				databaseStatusFallback, errFallback := adminClient.GetStatus()
+				databaseStatusFallback, errFallback := fallbackAdminClient.GetStatus()
 				if errFallback != nil {
 					cluster.Status.RunningVersion = originalRunningVersion
 					return false, err
@@ -141,13 +140,22 @@ func (s UpdateStatus) Reconcile(r *FoundationDBClusterReconciler, context ctx.Co
 		processClass := instance.GetProcessClass()
 		instanceID := instance.GetInstanceID()
 
+		processGroup := fdbtypes.ProcessGroupStatus{InstanceID: instanceID}
+		existingGroup := cluster.Status.GetProcessGroup(instanceID)
+		if existingGroup != nil {
+			processGroup = *existingGroup.DeepCopy()
+		}
+		processGroup.ProcessClass = processClass
+
 		if cluster.InstanceIsBeingRemoved(instanceID) {
+			status.ProcessGroups = append(status.ProcessGroups, processGroup)
 			continue
 		}
 
 		status.ProcessCounts.IncreaseCount(processClass, 1)
 
 		processStatus := processMap[instanceID]
+		processGroup.UpdateCondition(fdbtypes.MissingProcess, len(processStatus) == 0)
 		if len(processStatus) == 0 {
 			existingTime, exists := cluster.Status.MissingProcesses[instanceID]
 			if exists {
@@ -155,7 +163,13 @@ func (s UpdateStatus) Reconcile(r *FoundationDBClusterReconciler, context ctx.Co
 			} else {
 				status.MissingProcesses[instanceID] = time.Now().Unix()
 			}
+			processGroup.UpdateCondition(fdbtypes.IncorrectCommandLine, false)
 		} else {
+			processGroup.Addresses = make([]string, 0, len(processStatus))
+			for _, process := range processStatus {
+				processGroup.Addresses = append(processGroup.Addresses, process.Address)
+			}
+
 			podClient, err := r.getPodClient(cluster, instance)
 			correct := false
 			if err != nil {
@@ -180,6 +194,7 @@ func (s UpdateStatus) Reconcile(r *FoundationDBClusterReconciler, context ctx.Co
 					status.IncorrectProcesses[instanceID] = time.Now().Unix()
 				}
 			}
+			processGroup.UpdateCondition(fdbtypes.IncorrectCommandLine, !correct)
 		}
 
 		if instance.Pod != nil {
@@ -226,6 +241,7 @@ func (s UpdateStatus) Reconcile(r *FoundationDBClusterReconciler, context ctx.Co
 			if incorrectPod {
 				status.IncorrectPods = append(status.IncorrectPods, instance.Metadata.Name)
 			}
+			processGroup.UpdateCondition(fdbtypes.IncorrectPodSpec, incorrectPod)
 
 			for _, container := range instance.Pod.Spec.Containers {
 				if container.Name == "foundationdb" {
@@ -240,12 +256,20 @@ func (s UpdateStatus) Reconcile(r *FoundationDBClusterReconciler, context ctx.Co
 				}
 			}
 
+			podFailing := false
 			for _, container := range instance.Pod.Status.ContainerStatuses {
 				if !container.Ready {
 					status.FailingPods = append(status.FailingPods, instance.Metadata.Name)
+					podFailing = true
 				}
 			}
+			processGroup.UpdateCondition(fdbtypes.PodFailing, podFailing)
+		} else {
+			processGroup.UpdateCondition(fdbtypes.IncorrectPodSpec, false)
+			processGroup.UpdateCondition(fdbtypes.PodFailing, false)
 		}
+
+		status.ProcessGroups = append(status.ProcessGroups, processGroup)
 	}
 
 	existingConfigMap := &corev1.ConfigMap{}
@@ -257,6 +281,13 @@ func (s UpdateStatus) Reconcile(r *FoundationDBClusterReconciler, context ctx.Co
 	}
 
 	status.RunningVersion = cluster.Status.RunningVersion
+	status.MaintenanceZone = cluster.Status.MaintenanceZone
+	status.Conditions = cluster.Status.Conditions
+	status.Autoscaling = cluster.Status.Autoscaling
+	status.Bounce = cluster.Status.Bounce
+	status.UpgradeLaggingPods = cluster.Status.UpgradeLaggingPods
+	status.CanaryUpgrade = cluster.Status.CanaryUpgrade
+	status.StorageProcessSelector = labels.SelectorFromSet(getMinimalPodLabels(cluster, "storage", "")).String()
 
 	if status.RunningVersion == "" {
 		version, present := existingConfigMap.Data["running-version"]
@@ -306,6 +337,16 @@ func (s UpdateStatus) Reconcile(r *FoundationDBClusterReconciler, context ctx.Co
 		}
 	}
 
+	for instanceID := range status.PendingRemovals {
+		if status.GetProcessGroup(instanceID) == nil {
+			existingGroup := cluster.Status.GetProcessGroup(instanceID)
+			if existingGroup != nil {
+				status.ProcessGroups = append(status.ProcessGroups, *existingGroup.DeepCopy())
+			}
+		}
+	}
+	status.UpdateProcessGroupRemovals()
+
 	status.HasIncorrectConfigMap = status.HasIncorrectConfigMap || !reflect.DeepEqual(existingConfigMap.Data, configMap.Data) || !metadataMatches(existingConfigMap.ObjectMeta, configMap.ObjectMeta)
 
 	service, err := GetHeadlessService(cluster)
@@ -21,6 +21,7 @@ import (
 	"os"
 
 	appsv1beta1 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta1"
+	appsv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/FoundationDB/fdb-kubernetes-operator/controllers"
 	"github.com/apple/foundationdb/bindings/go/src/fdb"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -40,6 +41,7 @@ func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 
 	_ = appsv1beta1.AddToScheme(scheme)
+	_ = appsv1beta2.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -49,6 +51,8 @@ func main() {
 	var logFile string
 	var cliTimeout int
 	var useFutureDefaults bool
+	var useNativeAdminClient bool
+	var enableWebhooks bool
 
 	fdb.MustAPIVersion(610)
 
@@ -60,6 +64,12 @@ func main() {
 	flag.BoolVar(&useFutureDefaults, "use-future-defaults", false,
 		"Apply defaults from the next major version of the operator. This is only intended for use in development.",
 	)
+	flag.BoolVar(&useNativeAdminClient, "use-native-admin-client", false,
+		"Use the FoundationDB client library for administrative operations rather than running fdbcli. This can be overridden for individual clusters through the useNativeAdminClient field in the cluster spec.",
+	)
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the admission webhooks that validate the custom resources. This requires the webhook server certificates to be mounted in the operator pod.",
+	)
 	flag.Parse()
 
 	var logWriter io.Writer
@@ -81,6 +91,7 @@ func main() {
 	}))
 
 	controllers.DefaultCLITimeout = cliTimeout
+	controllers.DefaultUseNativeAdminClient = useNativeAdminClient
 
 	options := ctrl.Options{
 		Scheme:             scheme,
@@ -107,7 +118,7 @@ func main() {
 		Scheme:              mgr.GetScheme(),
 		PodLifecycleManager: controllers.StandardPodLifecycleManager{},
 		PodClientProvider:   controllers.NewFdbPodClient,
-		AdminClientProvider: controllers.NewCliAdminClient,
+		AdminClientProvider: controllers.NewAdminClient,
 		LockClientProvider:  controllers.NewRealLockClient,
 		UseFutureDefaults:   useFutureDefaults,
 	}
@@ -122,7 +133,7 @@ func main() {
 		Recorder:            mgr.GetEventRecorderFor("foundationdbcluster-controller"),
 		Log:                 ctrl.Log.WithName("controllers").WithName("FoundationDBCluster"),
 		Scheme:              mgr.GetScheme(),
-		AdminClientProvider: controllers.NewCliAdminClient,
+		AdminClientProvider: controllers.NewAdminClient,
 	}
 
 	if err = backupReconciler.SetupWithManager(mgr); err != nil {
@@ -135,7 +146,7 @@ func main() {
 		Recorder:            mgr.GetEventRecorderFor("foundationdbrestore-controller"),
 		Log:                 ctrl.Log.WithName("controllers").WithName("FoundationDBRestore"),
 		Scheme:              mgr.GetScheme(),
-		AdminClientProvider: controllers.NewCliAdminClient,
+		AdminClientProvider: controllers.NewAdminClient,
 	}
 
 	if err = restoreReconciler.SetupWithManager(mgr); err != nil {
@@ -143,6 +154,25 @@ func main() {
 		os.Exit(1)
 	}
 
+	if enableWebhooks {
+		appsv1beta1.WebhookDefaults.UseFutureDefaults = useFutureDefaults
+
+		if err = (&appsv1beta1.FoundationDBCluster{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "FoundationDBCluster")
+			os.Exit(1)
+		}
+
+		if err = (&appsv1beta1.FoundationDBBackup{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "FoundationDBBackup")
+			os.Exit(1)
+		}
+
+		if err = (&appsv1beta1.FoundationDBRestore{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "FoundationDBRestore")
+			os.Exit(1)
+		}
+	}
+
 	if metricsAddr != "0" {
 		controllers.InitCustomMetrics(clusterReconciler)
 	}